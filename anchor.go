@@ -0,0 +1,362 @@
+package niceyaml
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/tokens"
+)
+
+// Anchor describes an anchor (&name) in a [Source], and the aliases (*name)
+// that reference it.
+//
+// See [Source.Anchors].
+type Anchor struct {
+	// Name is the anchor name, without the "&" indicator.
+	Name string
+	// Aliases holds each alias referencing the anchor, in document order.
+	Aliases []Alias
+	// Range covers the anchor, including the "&" indicator.
+	Range position.Range
+}
+
+// Alias describes an alias (*name) in a [Source].
+type Alias struct {
+	// Name is the alias name, without the "*" indicator.
+	Name string
+	// Range covers the alias, including the "*" indicator.
+	Range position.Range
+	// Merge is true if the alias is the value of a merge key ("<<: *name"),
+	// or an item of a flow sequence that is.
+	Merge bool
+}
+
+// Anchors returns all anchors in the [Source] in document order, each with
+// the aliases that reference it.
+//
+// Anchors are resolved per document: an alias refers to the closest preceding
+// anchor with the same name in the same document. Aliases without a matching
+// anchor are not included.
+//
+// Anchors are found by scanning tokens, so the [Source] does not need to be
+// valid YAML, and positions refer to lines of the [Source] itself (including
+// for diff sources).
+func (s *Source) Anchors() []Anchor {
+	var (
+		anchors []Anchor
+		defined = map[string]int{}
+		merge   mergeState
+	)
+
+	for i, ln := range s.lines {
+		col := 0
+		tks := ln.Tokens()
+
+		for j, tk := range tks {
+			start := col + leadingWhitespaceRunes(tk.Origin, tokens.ValueOffset(tk))
+			col += utf8.RuneCountInString(strings.TrimSuffix(tk.Origin, "\n"))
+
+			inMerge := merge.next(tk)
+
+			switch tk.Type {
+			case token.DocumentHeaderType, token.DocumentEndType:
+				clear(defined)
+
+			case token.AnchorType, token.AliasType:
+				if j+1 >= len(tks) {
+					continue
+				}
+
+				name := tks[j+1].Value
+				end := position.New(i, start+1+utf8.RuneCountInString(name))
+				rng := position.NewRange(position.New(i, start), end)
+
+				if tk.Type == token.AnchorType {
+					defined[name] = len(anchors)
+					anchors = append(anchors, Anchor{Name: name, Range: rng})
+
+					continue
+				}
+
+				if idx, ok := defined[name]; ok {
+					anchors[idx].Aliases = append(anchors[idx].Aliases, Alias{
+						Name:  name,
+						Range: rng,
+						Merge: inMerge,
+					})
+				}
+
+			default:
+				// Not an anchor or alias.
+			}
+		}
+	}
+
+	return anchors
+}
+
+// ResolveAlias returns the [Anchor] referenced by the alias at the given
+// position.
+//
+// Returns false if there is no resolvable alias at the position.
+func (s *Source) ResolveAlias(pos position.Position) (Anchor, bool) {
+	for _, a := range s.Anchors() {
+		for _, alias := range a.Aliases {
+			if alias.Range.Contains(pos) {
+				return a, true
+			}
+		}
+	}
+
+	return Anchor{}, false
+}
+
+// AliasesOf returns the aliases referencing the anchor at the given position.
+// The position may also be on any alias of the anchor.
+//
+// Returns nil if there is no anchor or resolvable alias at the position.
+func (s *Source) AliasesOf(pos position.Position) []Alias {
+	for _, a := range s.Anchors() {
+		if a.Range.Contains(pos) {
+			return a.Aliases
+		}
+
+		for _, alias := range a.Aliases {
+			if alias.Range.Contains(pos) {
+				return a.Aliases
+			}
+		}
+	}
+
+	return nil
+}
+
+// AnnotateMerges adds a [line.Below] annotation to each line containing a
+// merge key ("<<: *name"), showing the entries merged from the anchored
+// mapping, e.g. "*base: {x: 1, y: {…}}".
+//
+// Nested collections are abbreviated as "{…}" or "[…]".
+//
+// Annotations are added to the lines of the [Source] directly; use a separate
+// [Source] if the original should remain unannotated.
+//
+// Returns an error if the [Source] cannot be parsed.
+func (s *Source) AnnotateMerges() error {
+	file, err := s.File()
+	if err != nil {
+		return err
+	}
+
+	type merged struct {
+		col      int
+		contents []string
+	}
+
+	byLine := map[int]*merged{}
+
+	for _, doc := range file.Docs {
+		anchors := ast.Filter(ast.AnchorType, doc)
+
+		for _, n := range ast.Filter(ast.MappingValueType, doc) {
+			mv, ok := n.(*ast.MappingValueNode)
+			if !ok {
+				continue
+			}
+
+			if _, ok := mv.Key.(*ast.MergeKeyNode); !ok {
+				continue
+			}
+
+			for _, alias := range mergeAliases(mv.Value) {
+				anchor := findAnchor(anchors, alias)
+				if anchor == nil {
+					continue
+				}
+
+				pos := position.NewFromToken(alias.Start)
+				if pos.Line >= len(s.lines) {
+					continue
+				}
+
+				m := byLine[pos.Line]
+				if m == nil {
+					m = &merged{col: pos.Col}
+					byLine[pos.Line] = m
+				}
+
+				m.contents = append(m.contents,
+					"*"+alias.Value.GetToken().Value+": "+summarizeValue(anchor.Value))
+			}
+		}
+	}
+
+	for lineIdx, m := range byLine {
+		s.Line(lineIdx).AddAnnotation(line.Annotation{
+			Content:  strings.Join(m.contents, "; "),
+			Position: line.Below,
+			Col:      m.col,
+		})
+	}
+
+	return nil
+}
+
+// mergeState tracks whether tokens are in the value of a merge key.
+type mergeState struct {
+	// Key is true after a merge key, and value after its ":" indicator.
+	key, value bool
+	// Flow is true inside a flow sequence value of a merge key.
+	flow bool
+}
+
+// next advances the state with tk, and reports whether tk is part of a merge
+// key value.
+func (m *mergeState) next(tk *token.Token) bool {
+	switch {
+	case m.flow:
+		if tk.Type == token.SequenceEndType {
+			m.flow = false
+		}
+
+		return true
+
+	case m.value:
+		m.value = false
+
+		if tk.Type == token.SequenceStartType {
+			m.flow = true
+		}
+
+		return true
+
+	case tk.Type == token.MergeKeyType:
+		// A merge key may be split across lines, e.g. "\n" and "  <<".
+		m.key = true
+
+		return false
+
+	case m.key:
+		m.key = false
+		m.value = tk.Type == token.MappingValueType
+
+		return false
+
+	default:
+		return false
+	}
+}
+
+// mergeAliases returns the aliases in the value of a merge key: either a
+// single alias, or a sequence of aliases.
+func mergeAliases(value ast.Node) []*ast.AliasNode {
+	switch v := value.(type) {
+	case *ast.AliasNode:
+		return []*ast.AliasNode{v}
+
+	case *ast.SequenceNode:
+		var aliases []*ast.AliasNode
+
+		for _, item := range v.Values {
+			if alias, ok := item.(*ast.AliasNode); ok {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		return aliases
+
+	default:
+		return nil
+	}
+}
+
+// findAnchor returns the last anchor with the alias's name that precedes the
+// alias.
+func findAnchor(anchors []ast.Node, alias *ast.AliasNode) *ast.AnchorNode {
+	if alias.Value == nil {
+		return nil
+	}
+
+	name := alias.Value.GetToken().Value
+	aliasPos := position.NewFromToken(alias.Start)
+
+	var found *ast.AnchorNode
+
+	for _, n := range anchors {
+		anchor, ok := n.(*ast.AnchorNode)
+		if !ok || anchor.Name == nil || anchor.Name.GetToken().Value != name {
+			continue
+		}
+
+		if !positionBefore(position.NewFromToken(anchor.Start), aliasPos) {
+			break
+		}
+
+		found = anchor
+	}
+
+	return found
+}
+
+// positionBefore reports whether a is before b.
+func positionBefore(a, b position.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Col < b.Col)
+}
+
+// summarizeValue returns a one-line summary of a mapping's entries, e.g.
+// "{x: 1, y: {…}}".
+func summarizeValue(value ast.Node) string {
+	var entries []*ast.MappingValueNode
+
+	switch v := unwrapNode(value).(type) {
+	case *ast.MappingNode:
+		entries = v.Values
+	case *ast.MappingValueNode:
+		entries = []*ast.MappingValueNode{v}
+	default:
+		return abbreviateValue(v)
+	}
+
+	parts := make([]string, 0, len(entries))
+	for _, mv := range entries {
+		parts = append(parts, mv.Key.GetToken().Value+": "+abbreviateValue(mv.Value))
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// abbreviateValue returns a short representation of a value node.
+func abbreviateValue(value ast.Node) string {
+	switch v := unwrapNode(value).(type) {
+	case nil, *ast.NullNode:
+		return "null"
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return "{…}"
+	case *ast.SequenceNode:
+		return "[…]"
+	case *ast.LiteralNode:
+		return v.Start.Value + "…"
+	case *ast.AliasNode:
+		return "*" + v.Value.GetToken().Value
+	default:
+		return v.GetToken().Value
+	}
+}
+
+// unwrapNode strips anchor and tag nodes from value.
+func unwrapNode(value ast.Node) ast.Node {
+	for {
+		switch v := value.(type) {
+		case *ast.AnchorNode:
+			value = v.Value
+		case *ast.TagNode:
+			value = v.Value
+		default:
+			return value
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+const anchorInput = `
+	base: &base
+	  x: 1
+	  y:
+	    z: 2
+	extra: &extra {w: 3}
+	copy: *base
+	merged:
+	  <<: [*base, *extra]
+	  v: 4
+	---
+	copy: *base
+	base: &base 5
+	again: *base
+`
+
+func rng(line, start, end int) position.Range {
+	return position.NewRange(position.New(line, start), position.New(line, end))
+}
+
+func TestSource_Anchors(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(anchorInput))
+
+	want := []niceyaml.Anchor{
+		{
+			Name:  "base",
+			Range: rng(0, 6, 11),
+			Aliases: []niceyaml.Alias{
+				{Name: "base", Range: rng(5, 6, 11)},
+				{Name: "base", Range: rng(7, 7, 12), Merge: true},
+			},
+		},
+		{
+			Name:  "extra",
+			Range: rng(4, 7, 13),
+			Aliases: []niceyaml.Alias{
+				{Name: "extra", Range: rng(7, 14, 20), Merge: true},
+			},
+		},
+		{
+			Name:  "base",
+			Range: rng(11, 6, 11),
+			Aliases: []niceyaml.Alias{
+				{Name: "base", Range: rng(12, 7, 12)},
+			},
+		},
+	}
+
+	assert.Equal(t, want, source.Anchors())
+}
+
+func TestSource_ResolveAlias(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(anchorInput))
+
+	tcs := map[string]struct {
+		pos  position.Position
+		want position.Range
+		ok   bool
+	}{
+		"alias start": {
+			pos:  position.New(5, 6),
+			want: rng(0, 6, 11),
+			ok:   true,
+		},
+		"merge sequence item": {
+			pos:  position.New(7, 15),
+			want: rng(4, 7, 13),
+			ok:   true,
+		},
+		"second document": {
+			pos:  position.New(12, 10),
+			want: rng(11, 6, 11),
+			ok:   true,
+		},
+		"alias before anchor in document": {
+			pos: position.New(10, 7),
+		},
+		"not an alias": {
+			pos: position.New(0, 7),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			anchor, ok := source.ResolveAlias(tc.pos)
+			require.Equal(t, tc.ok, ok)
+
+			if tc.ok {
+				assert.Equal(t, tc.want, anchor.Range)
+			}
+		})
+	}
+}
+
+func TestSource_AliasesOf(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(anchorInput))
+
+	ranges := func(aliases []niceyaml.Alias) []position.Range {
+		var out []position.Range
+		for _, a := range aliases {
+			out = append(out, a.Range)
+		}
+
+		return out
+	}
+
+	want := []position.Range{rng(5, 6, 11), rng(7, 7, 12)}
+
+	assert.Equal(t, want, ranges(source.AliasesOf(position.New(0, 8))), "from anchor")
+	assert.Equal(t, want, ranges(source.AliasesOf(position.New(7, 8))), "from alias")
+	assert.Nil(t, source.AliasesOf(position.New(1, 2)))
+}
+
+func TestSource_AnnotateMerges(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merged entries", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			base: &base
+			  x: 1
+			  y:
+			    z: 2
+			  list: [a]
+			other: &other {w: 3}
+			merged:
+			  <<: [*base, *other]
+			single:
+			  <<: *other
+		`))
+
+		require.NoError(t, source.AnnotateMerges())
+
+		var got []line.Annotation
+		for _, ln := range source.Lines() {
+			got = append(got, ln.Annotations...)
+		}
+
+		want := []line.Annotation{
+			{
+				Content:  "*base: {x: 1, y: {…}, list: […]}; *other: {w: 3}",
+				Position: line.Below,
+				Col:      7,
+			},
+			{
+				Content:  "*other: {w: 3}",
+				Position: line.Below,
+				Col:      6,
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("key: [unclosed\n")
+
+		var yamlErr *niceyaml.Error
+		require.ErrorAs(t, source.AnnotateMerges(), &yamlErr)
+	})
+}
@@ -48,10 +48,49 @@
 //
 // Configure these styles in your theme (see [theme] package).
 //
+// # Folding
+//
+// Mapping entries and sequence items spanning multiple lines can be folded
+// into a single placeholder line, e.g. "key: … (42 lines)". Gutters keep the
+// original line numbers.
+//
+// [Model.ToggleFold] folds or unfolds the node at the top of the viewport,
+// [Model.FoldToDepth] collapses everything at or below a given depth, and
+// [Model.UnfoldAll] expands everything. By default these are bound to "z",
+// "1"-"9", and "0" respectively.
+//
+// Folding applies to plain content in [ViewModeFull]. Fold state is kept while
+// switching modes, and reset when the displayed source changes. Search
+// automatically unfolds nodes hiding the current match.
+//
+// # Sticky Header
+//
+// Set [Model.StickyHeader] (or use [WithStickyHeader]) to pin the ancestor
+// key lines of the first visible line at the top of the viewport, similar to
+// "sticky scroll" in editors. The header is rendered with the [Printer], keeps
+// original line numbers, and updates as the viewport scrolls.
+//
+// Ancestors are found with the [outline.Outline] of the revision each line
+// belongs to, so the header also works for diffs. In [ViewModeSideBySide],
+// each pane shows its own header. In [ViewModeHunks],
+// the header shows the ancestors of the first hunk.
+//
+// # Anchors and Aliases
+//
+// [Model.JumpToAnchor] scrolls to the anchor referenced by the first alias
+// visible in the viewport, and [Model.JumpBack] returns to where the jump
+// started. Jumps can be nested. By default these are bound to "*" and
+// "ctrl+o".
+//
+// Use [Model.SetMergeAnnotations] (or [WithMergeAnnotations]) to annotate
+// each merge key with the entries it merges, e.g. "*base: {x: 1, y: {…}}".
+// By default this is toggled with "e".
+//
 // # Customization
 //
 // Provide a custom [Printer] via [WithPrinter] to control syntax highlighting,
-// line numbers, and annotations.
+// line numbers, and annotations. Folding requires the [Printer] to also have
+// a SetFolds method, like [niceyaml.Printer].
 //
 // Provide a custom [Finder] via [WithFinder] for specialized search behavior
 // (e.g., case-insensitive matching).
@@ -36,6 +36,19 @@ type KeyMap struct {
 	ToggleViewMode key.Binding
 	// ToggleWordWrap toggles word wrapping.
 	ToggleWordWrap key.Binding
+	// ToggleFold folds or unfolds the node at the top of the viewport.
+	ToggleFold key.Binding
+	// FoldToDepth folds all nodes to the depth given by the pressed digit.
+	// Keys should be the digits "1" through "9".
+	FoldToDepth key.Binding
+	// UnfoldAll unfolds all nodes.
+	UnfoldAll key.Binding
+	// JumpToAnchor jumps from the first visible alias to its anchor.
+	JumpToAnchor key.Binding
+	// JumpBack returns to the position before the last anchor jump.
+	JumpBack key.Binding
+	// ToggleMergeAnnotations toggles annotations showing merged entries.
+	ToggleMergeAnnotations key.Binding
 }
 
 // DefaultKeyMap returns a new [KeyMap] with pager-like default keybindings.
@@ -93,5 +106,29 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("w"),
 			key.WithHelp("w", "toggle word wrap"),
 		),
+		ToggleFold: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle fold"),
+		),
+		FoldToDepth: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			key.WithHelp("1-9", "fold to depth"),
+		),
+		UnfoldAll: key.NewBinding(
+			key.WithKeys("0"),
+			key.WithHelp("0", "unfold all"),
+		),
+		JumpToAnchor: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "jump to anchor"),
+		),
+		JumpBack: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "jump back"),
+		),
+		ToggleMergeAnnotations: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "toggle merge annotations"),
+		),
 	}
 }
@@ -0,0 +1,186 @@
+package yamlviewport
+
+import (
+	"iter"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/outline"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// stickyHeader returns the indices of the ancestor key lines of the line at
+// row top in src, to be pinned above the content.
+//
+// At most maxHeight-1 lines are returned, keeping the innermost ancestors, so
+// that at least one row of content remains visible.
+// Returns nil if [Model.StickyHeader] is disabled.
+func (m *Model) stickyHeader(src niceyaml.LineGetter, top int) []int {
+	if !m.StickyHeader || src == nil {
+		return nil
+	}
+
+	ancestors := m.stickyOutlines.stickyAncestors(src, m.rowToLine(top))
+	if limit := max(0, m.maxHeight()-1); len(ancestors) > limit {
+		ancestors = ancestors[len(ancestors)-limit:]
+	}
+
+	return ancestors
+}
+
+// stickyHeight returns the number of header lines pinned when the given row is
+// at the top of the viewport.
+func (m *Model) stickyHeight(top int) int {
+	height := len(m.stickyHeader(m.left, top))
+	if m.viewMode == ViewModeSideBySide && m.right != nil {
+		height = max(height, len(m.stickyHeader(m.right, top)))
+	}
+
+	return height
+}
+
+// renderSticky renders the given lines of src for use as a sticky header.
+// Annotations are omitted, and folds are disabled.
+func (m *Model) renderSticky(src niceyaml.LineIterator, idxs []int) []string {
+	if len(idxs) == 0 {
+		return nil
+	}
+
+	m.setPrinterFolds(nil)
+
+	return splitLines(m.printer.Print(stickyLines{LineIterator: src, idxs: idxs}))
+}
+
+// padRows pads rows with empty strings to n rows.
+func padRows(rows []string, n int) []string {
+	for len(rows) < n {
+		rows = append(rows, "")
+	}
+
+	return rows
+}
+
+// stickyLines is a [niceyaml.LineIterator] that yields only the given line
+// indices of the underlying iterator, without annotations.
+// Lines keep their original positions, so gutters show original numbers.
+type stickyLines struct {
+	niceyaml.LineIterator
+
+	idxs []int
+}
+
+// AllLines yields the selected lines, ignoring spans.
+func (s stickyLines) AllLines(...position.Span) iter.Seq2[position.Position, line.Line] {
+	return func(yield func(position.Position, line.Line) bool) {
+		for _, idx := range s.idxs {
+			for pos, ln := range s.LineIterator.AllLines(position.NewSpan(idx, idx+1)) {
+				ln.Annotations = nil
+
+				if !yield(pos, ln) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// stickyOutlines caches the outlines used to find the ancestors of lines,
+// built on demand for each source. It is replaced whenever the sources change.
+type stickyOutlines map[stickyKey]*stickyOutline
+
+// stickyKey identifies the outline of one revision within a source.
+type stickyKey struct {
+	src  niceyaml.LineGetter
+	skip line.Flag
+}
+
+// stickyOutline is the [outline.Outline] of the lines of a source that belong
+// to one revision.
+type stickyOutline struct {
+	outline *outline.Outline
+	// Lines maps outline line indices to source line indices.
+	lines []int
+	// Index maps source line indices to outline line indices, or -1 for
+	// lines of the other revision.
+	index []int
+}
+
+// stickyAncestors returns the indices of the key lines enclosing the line at
+// idx of src, outermost first.
+//
+// Diff sources interleave the lines of two revisions, so ancestors are found
+// in the outline of the revision the line belongs to: inserted lines are
+// skipped for deleted lines, and deleted lines for all others.
+func (o stickyOutlines) stickyAncestors(src niceyaml.LineGetter, idx int) []int {
+	lines := src.Lines()
+	if idx <= 0 || idx >= len(lines) {
+		return nil
+	}
+
+	skip := line.FlagDeleted
+	if lines[idx].Flag == line.FlagDeleted {
+		skip = line.FlagInserted
+	}
+
+	key := stickyKey{src: src, skip: skip}
+
+	so, ok := o[key]
+	if !ok {
+		so = newStickyOutline(lines, skip)
+		if o != nil {
+			o[key] = so
+		}
+	}
+
+	if so == nil || so.index[idx] < 0 {
+		return nil
+	}
+
+	var ancestors []int
+
+	for _, n := range so.outline.Enclosing(so.index[idx]) {
+		start := n.Span.Start
+		if start >= so.index[idx] {
+			break
+		}
+
+		// Sequence items and their first mapping entry share a line.
+		if i := so.lines[start]; len(ancestors) == 0 || ancestors[len(ancestors)-1] != i {
+			ancestors = append(ancestors, i)
+		}
+	}
+
+	return ancestors
+}
+
+// newStickyOutline builds the outline of lines, excluding lines flagged skip.
+// Returns nil if the remaining lines cannot be parsed.
+func newStickyOutline(lines line.Lines, skip line.Flag) *stickyOutline {
+	so := &stickyOutline{index: make([]int, len(lines))}
+
+	var sb strings.Builder
+
+	for i, ln := range lines {
+		if ln.Flag == skip {
+			so.index[i] = -1
+
+			continue
+		}
+
+		so.index[i] = len(so.lines)
+		so.lines = append(so.lines, i)
+
+		sb.WriteString(ln.Content())
+		sb.WriteByte('\n')
+	}
+
+	o, err := niceyaml.NewSourceFromString(sb.String()).Outline()
+	if err != nil {
+		return nil
+	}
+
+	so.outline = o
+
+	return so
+}
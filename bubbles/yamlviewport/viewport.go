@@ -3,6 +3,7 @@ package yamlviewport
 import (
 	"cmp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"charm.land/bubbles/v2/key"
@@ -14,6 +15,7 @@ import (
 	"go.jacobcolvin.com/niceyaml"
 	"go.jacobcolvin.com/niceyaml/line"
 	"go.jacobcolvin.com/niceyaml/normalizer"
+	"go.jacobcolvin.com/niceyaml/outline"
 	"go.jacobcolvin.com/niceyaml/position"
 	"go.jacobcolvin.com/niceyaml/style"
 )
@@ -31,6 +33,12 @@ type Printer interface {
 	Style(s style.Style) *lipgloss.Style
 }
 
+// foldPrinter is implemented by [Printer]s that can collapse folded nodes,
+// such as [niceyaml.Printer]. Folding is unavailable with other printers.
+type foldPrinter interface {
+	SetFolds(f *niceyaml.Folds)
+}
+
 // Finder finds [position.Range]s for a search string.
 //
 // See [niceyaml.Finder] for an implementation.
@@ -80,6 +88,8 @@ const (
 //   - [WithPrinter]
 //   - [WithStyle]
 //   - [WithFinder]
+//   - [WithStickyHeader]
+//   - [WithMergeAnnotations]
 type Option func(*Model)
 
 // WithPrinter is an [Option] that sets the [Printer] used for rendering.
@@ -106,6 +116,21 @@ func WithFinder(f Finder) Option {
 	}
 }
 
+// WithStickyHeader is an [Option] that sets [Model.StickyHeader].
+func WithStickyHeader(enabled bool) Option {
+	return func(m *Model) {
+		m.StickyHeader = enabled
+	}
+}
+
+// WithMergeAnnotations is an [Option] that enables merge annotations.
+// See [Model.SetMergeAnnotations].
+func WithMergeAnnotations(enabled bool) Option {
+	return func(m *Model) {
+		m.mergeAnnotations = enabled
+	}
+}
+
 // New creates a new [Model] with the given options.
 func New(opts ...Option) Model {
 	var m Model
@@ -129,6 +154,11 @@ type Model struct {
 	// Cached diff between base and current revision.
 	revision   *niceyaml.Revision
 	diffResult *niceyaml.DiffResult
+	// Cached line index of each row in ViewModeHunks, used for the sticky
+	// header. Only populated when StickyHeader is enabled.
+	hunkRowLines []int
+	// Outlines for the sticky header, built on demand for each source.
+	stickyOutlines stickyOutlines
 	// Left holds the source for the left pane or main content.
 	// In ViewModeFull/ViewModeHunks: Unified diff or plain content.
 	// In ViewModeSideBySide with diff: Before source.
@@ -137,6 +167,18 @@ type Model struct {
 	// Right holds the right pane source for side-by-side diff rendering.
 	// Only populated when viewMode == ViewModeSideBySide and showing a diff.
 	right *niceyaml.Source
+	// Fold state for foldSource, which is retained while switching modes.
+	folds      *niceyaml.Folds
+	foldSource *niceyaml.Source
+	// Visible maps rows to line indices in left when folds hide lines.
+	// Nil when every line is visible.
+	visible []int
+	// Annotated copy of mergeFrom, used when mergeAnnotations is enabled.
+	mergeFrom   *niceyaml.Source
+	mergeSource *niceyaml.Source
+	// Jump history of top line indices in jumpSource, most recent last.
+	jumps      []int
+	jumpSource *niceyaml.Source
 	// Current search query.
 	searchTerm string
 	// KeyMap contains the keybindings for viewport navigation.
@@ -156,6 +198,8 @@ type Model struct {
 	xOffset         int
 	viewMode        ViewMode
 	hunkContext     int
+	// Show the entries merged by merge keys as annotations.
+	mergeAnnotations bool
 	// FillHeight pads output with empty lines to fill the viewport height when true.
 	FillHeight bool
 	// MouseWheelEnabled enables mouse wheel scrolling.
@@ -163,7 +207,10 @@ type Model struct {
 	MouseWheelEnabled bool
 	// WrapEnabled enables line wrapping based on viewport width.
 	WrapEnabled bool
-	initialized bool
+	// StickyHeader pins the ancestor key lines of the first visible line at
+	// the top of the viewport, rendered with the [Printer].
+	StickyHeader bool
+	initialized  bool
 }
 
 func (m *Model) setInitialValues() {
@@ -417,6 +464,7 @@ func (m *Model) HunkContext() int {
 // in [ViewModeHunks]. Default is 3.
 func (m *Model) SetHunkContext(n int) {
 	m.hunkContext = max(0, n)
+	m.updateHunkRowLines()
 }
 
 // ToggleWordWrap toggles word wrapping on or off.
@@ -462,7 +510,10 @@ func (m *Model) seekRevision(delta int) {
 // Actual rendering is deferred to renderVisible for on-demand rendering.
 func (m *Model) rerender() {
 	m.diffResult = nil // Invalidate cached diff result.
+	m.hunkRowLines = nil
+	m.stickyOutlines = stickyOutlines{}
 	m.right = nil
+	m.visible = nil
 
 	// Handle side-by-side mode with diff specially.
 	if m.viewMode == ViewModeSideBySide {
@@ -488,11 +539,287 @@ func (m *Model) rerender() {
 	m.left = left
 	m.updateSearchState(left)
 	m.applySearchOverlays(left)
+	m.updateFoldState()
+	m.updateHunkRowLines()
+}
+
+// foldableSource returns the source that folds apply to, or nil if folding is
+// unavailable. Folding applies only to plain (non-diff) content in
+// [ViewModeFull], with a [foldPrinter].
+func (m *Model) foldableSource() *niceyaml.Source {
+	if m.viewMode != ViewModeFull || m.IsShowingDiff() {
+		return nil
+	}
+
+	if _, ok := m.printer.(foldPrinter); !ok {
+		return nil
+	}
+
+	return m.left
+}
+
+// updateFoldState resets the folds when the foldable source changes, and
+// recomputes the visible line mapping.
+func (m *Model) updateFoldState() {
+	m.visible = nil
+
+	src := m.foldableSource()
+	if src == nil {
+		return
+	}
+
+	if src != m.foldSource {
+		m.foldSource = src
+		m.folds = niceyaml.NewFolds()
+
+		return
+	}
+
+	if m.folds.Len() > 0 {
+		m.visible = m.folds.VisibleLines(src.Len())
+	}
+}
+
+// activeFolds returns the folds for the displayed source, or nil if folding
+// is unavailable.
+func (m *Model) activeFolds() *niceyaml.Folds {
+	if src := m.foldableSource(); src == nil || src != m.foldSource {
+		return nil
+	}
+
+	return m.folds
+}
+
+// setPrinterFolds sets the folds of the [Printer], if it is a [foldPrinter].
+func (m *Model) setPrinterFolds(f *niceyaml.Folds) {
+	if p, ok := m.printer.(foldPrinter); ok {
+		p.SetFolds(f)
+	}
+}
+
+// changeFolds applies fn to the active folds and the outline of the displayed
+// source, then recomputes the visible lines while keeping the line at the top
+// of the viewport in place.
+// Does nothing if folding is unavailable or the source cannot be parsed.
+func (m *Model) changeFolds(fn func(*niceyaml.Folds, *outline.Outline)) {
+	folds := m.activeFolds()
+	if folds == nil {
+		return
+	}
+
+	o, err := m.foldSource.Outline()
+	if err != nil {
+		return
+	}
+
+	top := m.rowToLine(m.YOffset())
+
+	fn(folds, o)
+	m.updateFoldState()
+	m.SetYOffset(m.lineToRow(top))
+}
+
+// ToggleFold folds or unfolds the node at the top of the viewport.
+//
+// If the top line is the first line of a folded node, that node is unfolded.
+// Otherwise, the innermost foldable node containing the top line is folded,
+// scrolling up to its first line if needed.
+//
+// Folding applies only to plain content in [ViewModeFull]; in other modes,
+// this does nothing.
+func (m *Model) ToggleFold() {
+	m.changeFolds(func(folds *niceyaml.Folds, o *outline.Outline) {
+		chain := o.Enclosing(m.rowToLine(m.YOffset()))
+
+		// The top line is visible, so any folded node containing it starts on
+		// it. Unfold the outermost one, since that is the one displayed.
+		for _, n := range chain {
+			if folds.IsFolded(n) {
+				folds.Unfold(n)
+
+				return
+			}
+		}
+
+		for _, n := range slices.Backward(chain) {
+			if n.IsFoldable() {
+				folds.Fold(n)
+
+				return
+			}
+		}
+	})
+}
+
+// FoldToDepth folds every node at the given depth or deeper, so that only
+// the first depth-1 levels of nesting remain expanded. A depth of 1 collapses
+// every top-level entry.
+//
+// See [niceyaml.Folds.FoldToDepth] for details.
+func (m *Model) FoldToDepth(depth int) {
+	m.changeFolds(func(folds *niceyaml.Folds, o *outline.Outline) {
+		folds.FoldToDepth(o, depth)
+	})
+}
+
+// UnfoldAll unfolds all nodes.
+func (m *Model) UnfoldAll() {
+	m.changeFolds(func(folds *niceyaml.Folds, _ *outline.Outline) {
+		folds.UnfoldAll()
+	})
+}
+
+// FoldCount returns the number of folded nodes in the displayed source.
+func (m *Model) FoldCount() int {
+	if folds := m.activeFolds(); folds != nil {
+		return folds.Len()
+	}
+
+	return 0
+}
+
+// rowToLine converts a row index to a line index in the displayed source,
+// accounting for lines hidden by folds.
+func (m *Model) rowToLine(row int) int {
+	if len(m.visible) == 0 {
+		return row
+	}
+
+	return m.visible[clamp(row, 0, len(m.visible)-1)]
+}
+
+// lineToRow converts a line index in the displayed source to a row index.
+// Lines hidden by folds map to the row of the fold's first line.
+func (m *Model) lineToRow(lineIdx int) int {
+	if len(m.visible) == 0 {
+		return lineIdx
+	}
+
+	row, found := slices.BinarySearch(m.visible, lineIdx)
+	if !found && row > 0 {
+		row--
+	}
+
+	return row
+}
+
+// MergeAnnotations reports whether merge annotations are enabled.
+func (m *Model) MergeAnnotations() bool {
+	return m.mergeAnnotations
+}
+
+// SetMergeAnnotations enables or disables merge annotations and rerenders.
+//
+// When enabled, each line with a merge key ("<<: *base") is annotated with the
+// entries merged from the anchored mapping. See
+// [niceyaml.Source.AnnotateMerges] for details.
+//
+// Annotations are shown for plain (non-diff) content only, and are added to a
+// copy of the revision's [niceyaml.Source].
+func (m *Model) SetMergeAnnotations(enabled bool) {
+	m.mergeAnnotations = enabled
+	m.rerender()
+}
+
+// ToggleMergeAnnotations toggles merge annotations on or off.
+func (m *Model) ToggleMergeAnnotations() {
+	m.SetMergeAnnotations(!m.mergeAnnotations)
+}
+
+// JumpToAnchor scrolls to the anchor referenced by the first alias visible in
+// the viewport, placing the anchor's line at the top.
+// The previous position is recorded, so that [Model.JumpBack] can return to
+// it.
+//
+// Jumping applies only to [ViewModeFull]; in other modes, or if no resolvable
+// alias is visible, this does nothing.
+func (m *Model) JumpToAnchor() {
+	if m.viewMode != ViewModeFull || m.left == nil || m.lineCount() == 0 {
+		return
+	}
+
+	top := m.YOffset()
+	first := m.rowToLine(top)
+	last := m.rowToLine(min(top+m.maxHeight(), m.lineCount()) - 1)
+
+	var (
+		target position.Position
+		alias  *position.Range
+	)
+
+	for _, a := range m.left.Anchors() {
+		for _, al := range a.Aliases {
+			start := al.Range.Start
+			if start.Line < first || start.Line > last || !m.isLineVisible(start.Line) {
+				continue
+			}
+
+			if alias == nil || start.Line < alias.Start.Line ||
+				(start.Line == alias.Start.Line && start.Col < alias.Start.Col) {
+				alias = &al.Range
+				target = a.Range.Start
+			}
+		}
+	}
+
+	if alias == nil {
+		return
+	}
+
+	if m.jumpSource != m.left {
+		m.jumpSource = m.left
+		m.jumps = nil
+	}
+
+	m.jumps = append(m.jumps, first)
+	m.scrollToLine(target.Line)
+}
+
+// JumpBack returns to the position before the most recent
+// [Model.JumpToAnchor].
+// Does nothing if there is no earlier position, or the displayed content has
+// changed since the jump.
+func (m *Model) JumpBack() {
+	if m.jumpSource != m.left {
+		m.jumpSource = nil
+		m.jumps = nil
+	}
+
+	if len(m.jumps) == 0 {
+		return
+	}
+
+	lineIdx := m.jumps[len(m.jumps)-1]
+	m.jumps = m.jumps[:len(m.jumps)-1]
+
+	m.scrollToLine(lineIdx)
+}
+
+// scrollToLine unfolds any nodes hiding the line at lineIdx, and scrolls so
+// that it is at the top of the viewport.
+func (m *Model) scrollToLine(lineIdx int) {
+	if folds := m.activeFolds(); folds != nil && folds.Reveal(lineIdx) {
+		m.updateFoldState()
+	}
+
+	m.SetYOffset(m.lineToRow(lineIdx))
+}
+
+// isLineVisible reports whether the line at lineIdx is not hidden by a fold.
+func (m *Model) isLineVisible(lineIdx int) bool {
+	if len(m.visible) == 0 {
+		return true
+	}
+
+	_, found := slices.BinarySearch(m.visible, lineIdx)
+
+	return found
 }
 
 // applySearchOverlays sets overlay highlights for all search matches.
+// Overlays of other kinds, such as syntax errors, are kept.
 func (m *Model) applySearchOverlays(lines *niceyaml.Source) {
-	lines.ClearOverlays()
+	lines.RemoveOverlays(searchOverlayKinds...)
 
 	for i, match := range m.searchMatches {
 		if i == m.searchIndex {
@@ -503,6 +830,9 @@ func (m *Model) applySearchOverlays(lines *niceyaml.Source) {
 	}
 }
 
+// searchOverlayKinds are the overlay kinds of search highlights.
+var searchOverlayKinds = []style.Style{style.GenericHighlight, style.GenericHighlightDim}
+
 // searchMatch pairs a match range with its source.
 type searchMatch struct {
 	rng    position.Range
@@ -622,7 +952,7 @@ func (m *Model) applySideBySidePaneOverlays(
 		return
 	}
 
-	src.ClearOverlays()
+	src.RemoveOverlays(searchOverlayKinds...)
 
 	for _, match := range matches {
 		isSelected := match.Start == selectedPos && showSelected
@@ -670,15 +1000,19 @@ func (m *Model) renderVisible() []string {
 	}
 
 	start := m.YOffset()
-	end := min(start+m.maxHeight(), m.left.Len())
+	header := m.stickyHeader(m.left, start)
+	end := min(start+m.maxHeight()-len(header), m.lineCount())
 
 	if start >= end {
 		return nil
 	}
 
-	content := m.printer.Print(m.left, position.NewSpan(start, end))
+	span := position.NewSpan(m.rowToLine(start), m.rowToLine(end-1)+1)
+	rows := m.renderSticky(m.left, header)
+
+	m.setPrinterFolds(m.activeFolds())
 
-	return splitLines(content)
+	return append(rows, splitLines(m.printer.Print(m.left, span))...)
 }
 
 // getDiffBaseRevision returns the base revision for diff comparison based on
@@ -703,12 +1037,38 @@ func (m *Model) getDiffBaseRevision() *niceyaml.Revision {
 // [DiffMode].
 func (m *Model) getDisplayLines() *niceyaml.Source {
 	if src, needsDiff := m.resolveRevisionSource(); !needsDiff {
-		return src
+		return m.mergeAnnotated(src)
 	}
 
 	return m.getDiffResult().Unified()
 }
 
+// mergeAnnotated returns a copy of src with merge annotations if they are
+// enabled, or src otherwise.
+//
+// The copy is cached, so that fold state is kept across rerenders, and src
+// itself is never modified. If src cannot be parsed, it is returned as-is.
+func (m *Model) mergeAnnotated(src *niceyaml.Source) *niceyaml.Source {
+	if !m.mergeAnnotations || src == nil {
+		return src
+	}
+
+	if src == m.mergeFrom {
+		return m.mergeSource
+	}
+
+	annotated := src.Clone()
+
+	if err := annotated.AnnotateMerges(); err != nil {
+		annotated = src
+	}
+
+	m.mergeFrom = src
+	m.mergeSource = annotated
+
+	return annotated
+}
+
 // getDiffResult returns the cached [niceyaml.DiffResult], computing it if nil.
 func (m *Model) getDiffResult() *niceyaml.DiffResult {
 	if m.diffResult == nil {
@@ -760,7 +1120,7 @@ func (m *Model) ScrollPercent() float64 {
 		return 1.0
 	}
 
-	return scrollPercent(m.YOffset(), m.maxHeight(), m.left.Len())
+	return scrollPercent(m.YOffset(), m.maxHeight(), m.lineCount())
 }
 
 // HorizontalScrollPercent returns the horizontal scroll position as a float
@@ -791,15 +1151,31 @@ func (m *Model) maxYOffset() int {
 		return 0
 	}
 
-	return max(0, lineCount-m.maxHeight())
+	offset := max(0, lineCount-m.maxHeight())
+	if !m.StickyHeader {
+		return offset
+	}
+
+	// The sticky header takes rows from the content, so allow scrolling
+	// further until the last line fits below it.
+	for offset < lineCount-1 && lineCount-offset > m.maxHeight()-m.stickyHeight(offset) {
+		offset++
+	}
+
+	return offset
 }
 
-// lineCount returns the line count for the current view mode.
+// lineCount returns the line count for the current view mode, excluding
+// lines hidden by folds.
 func (m *Model) lineCount() int {
 	if m.left == nil {
 		return 0
 	}
 
+	if m.visible != nil {
+		return len(m.visible)
+	}
+
 	return m.left.Len()
 }
 
@@ -965,7 +1341,8 @@ func (m *Model) GotoBottom() {
 	m.SetYOffset(m.maxYOffset())
 }
 
-// TotalLineCount returns the total number of lines.
+// TotalLineCount returns the total number of lines, excluding lines hidden by
+// folds.
 func (m *Model) TotalLineCount() int {
 	return m.lineCount()
 }
@@ -1050,11 +1427,16 @@ func (m *Model) scrollToCurrentMatch() {
 	match := m.searchMatches[m.searchIndex]
 	startLine := match.rng.Start.Line
 
+	// Unfold any nodes hiding the match.
+	if folds := m.activeFolds(); folds != nil && folds.Reveal(startLine) {
+		m.updateFoldState()
+	}
+
 	// Center the match in the viewport.
 	// Use (maxHeight-1)/2 to ensure the match appears at the visual center.
 	// For height 22: (22-1)/2 = 10, placing the match at position 10 (middle).
 	// For height 21: (21-1)/2 = 10, placing the match at position 10 (middle).
-	m.SetYOffset(startLine - (m.maxHeight()-1)/2)
+	m.SetYOffset(m.lineToRow(startLine) - (m.maxHeight()-1)/2)
 }
 
 // Update processes Bubble Tea messages and returns the updated model.
@@ -1106,6 +1488,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 		case key.Matches(msg, m.KeyMap.ToggleWordWrap):
 			m.ToggleWordWrap()
+
+		case key.Matches(msg, m.KeyMap.ToggleFold):
+			m.ToggleFold()
+
+		case key.Matches(msg, m.KeyMap.FoldToDepth):
+			if depth, err := strconv.Atoi(msg.String()); err == nil {
+				m.FoldToDepth(depth)
+			}
+
+		case key.Matches(msg, m.KeyMap.UnfoldAll):
+			m.UnfoldAll()
+
+		case key.Matches(msg, m.KeyMap.JumpToAnchor):
+			m.JumpToAnchor()
+
+		case key.Matches(msg, m.KeyMap.JumpBack):
+			m.JumpBack()
+
+		case key.Matches(msg, m.KeyMap.ToggleMergeAnnotations):
+			m.ToggleMergeAnnotations()
 		}
 
 	case tea.MouseWheelMsg:
@@ -1198,8 +1600,7 @@ func (m Model) View() string {
 
 	switch m.viewMode {
 	case ViewModeHunks:
-		hunksContent := m.getHunksDiffContent()
-		lines = m.visibleLines(splitLines(hunksContent))
+		lines = m.visibleLines(m.getHunksDiffRows())
 
 	case ViewModeSideBySide:
 		return m.renderSideBySide(w, h)
@@ -1211,19 +1612,77 @@ func (m Model) View() string {
 	return m.renderContent(lines, w, h)
 }
 
-// getHunksDiffContent returns diff content with context lines for hunks mode.
-func (m *Model) getHunksDiffContent() string {
+// getHunksDiffRows returns the rows of diff content with context lines for
+// hunks mode.
+func (m *Model) getHunksDiffRows() []string {
+	m.setPrinterFolds(nil)
+
 	if src, needsDiff := m.resolveRevisionSource(); !needsDiff {
 		if src == nil {
-			return ""
+			return nil
 		}
 
-		return m.printer.Print(src)
+		return splitLines(m.printer.Print(src))
 	}
 
 	source, ranges := m.getDiffResult().Hunks(m.hunkContext)
+	rows := splitLines(m.printer.Print(source, ranges...))
+
+	if !m.StickyHeader {
+		return rows
+	}
 
-	return m.printer.Print(source, ranges...)
+	rowLines := m.hunkRowLines
+	if rowLines == nil {
+		rowLines = m.countHunkRowLines(source, ranges)
+	}
+
+	if len(rowLines) != len(rows) || len(rows) == 0 {
+		return rows
+	}
+
+	// Hunks are rendered from the top, so the header for the line of the
+	// first row is placed above its hunk header rather than over it. Hunk
+	// lines match the lines of the unified diff in m.left, whose outlines are
+	// cached.
+	header := m.stickyOutlines.stickyAncestors(m.left, rowLines[0])
+	if limit := max(0, m.maxHeight()-1); len(header) > limit {
+		header = header[len(header)-limit:]
+	}
+
+	return append(m.renderSticky(source, header), rows...)
+}
+
+// updateHunkRowLines caches the line index of each hunks mode row for the
+// sticky header.
+func (m *Model) updateHunkRowLines() {
+	m.hunkRowLines = nil
+
+	if !m.StickyHeader || m.viewMode != ViewModeHunks {
+		return
+	}
+
+	if _, needsDiff := m.resolveRevisionSource(); needsDiff {
+		m.setPrinterFolds(nil)
+		m.hunkRowLines = m.countHunkRowLines(m.getDiffResult().Hunks(m.hunkContext))
+	}
+}
+
+// countHunkRowLines returns the line index of each row printed for the given
+// ranges of source, including the rows of hunk headers.
+func (m *Model) countHunkRowLines(source *niceyaml.Source, ranges position.Spans) []int {
+	var rowLines []int
+
+	for _, r := range ranges {
+		for i := r.Start; i < r.End; i++ {
+			n := len(splitLines(m.printer.Print(source, position.NewSpan(i, i+1))))
+			for range n {
+				rowLines = append(rowLines, i)
+			}
+		}
+	}
+
+	return rowLines
 }
 
 // sideBySideSeparator is the column divider between panes.
@@ -1234,7 +1693,7 @@ const sideBySideSeparator = " │ "
 //nolint:gocritic // hugeParam: required for value receiver compatibility with View().
 func (m Model) renderSideBySide(contentW, contentH int) string {
 	// Get iterators for both panes.
-	var leftIter, rightIter niceyaml.LineIterator
+	var leftIter, rightIter *niceyaml.Source
 
 	if src, needsDiff := m.resolveRevisionSource(); !needsDiff {
 		// Not showing a diff: show same content on both sides.
@@ -1262,9 +1721,12 @@ func (m Model) renderSideBySide(contentW, contentH int) string {
 
 	extraPadding := availableWidth % 2 // Add to separator if odd.
 
-	// Determine visible span.
+	// Determine visible span, leaving room for the sticky header.
 	start := m.YOffset()
-	end := min(start+m.maxHeight(), leftIter.Len())
+	leftHeader := m.stickyHeader(leftIter, start)
+	rightHeader := m.stickyHeader(rightIter, start)
+	headerHeight := max(len(leftHeader), len(rightHeader))
+	end := min(start+m.maxHeight()-headerHeight, leftIter.Len())
 
 	if start >= end {
 		return m.renderContent(nil, contentW, contentH)
@@ -1273,6 +1735,7 @@ func (m Model) renderSideBySide(contentW, contentH int) string {
 	span := position.NewSpan(start, end)
 
 	// Render both panes.
+	m.setPrinterFolds(nil)
 	m.printer.SetWordWrap(m.WrapEnabled)
 	m.printer.SetWidth(paneWidth)
 
@@ -1283,6 +1746,14 @@ func (m Model) renderSideBySide(contentW, contentH int) string {
 	leftLines := splitLines(leftContent)
 	rightLines := splitLines(rightContent)
 
+	if headerHeight > 0 {
+		leftHeaderRows := m.renderSticky(leftIter, leftHeader)
+		rightHeaderRows := m.renderSticky(rightIter, rightHeader)
+		rows := max(len(leftHeaderRows), len(rightHeaderRows))
+		leftLines = append(padRows(leftHeaderRows, rows), leftLines...)
+		rightLines = append(padRows(rightHeaderRows, rows), rightLines...)
+	}
+
 	// Get text style for padding empty areas.
 	textStyle := lipgloss.NewStyle()
 	if st := m.printer.Style(style.Text); st != nil {
@@ -2,6 +2,7 @@ package yamlviewport_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"charm.land/bubbles/v2/key"
@@ -2618,6 +2619,47 @@ func TestViewport_SetSearchTermEmpty(t *testing.T) {
 	assert.Equal(t, 0, m.SearchCount())
 }
 
+func TestViewport_SearchKeepsOtherOverlays(t *testing.T) {
+	t.Parallel()
+
+	m := yamlviewport.New(yamlviewport.WithPrinter(testPrinter()))
+	m.SetWidth(80)
+	m.SetHeight(10)
+
+	src := niceyaml.NewSourceFromString("key: value\nbad: [1\n")
+	_, err := src.ParseTolerant()
+	require.Error(t, err)
+
+	invalid := func() int {
+		n := 0
+
+		for _, ln := range src.Lines() {
+			for _, o := range ln.Overlays {
+				if o.Kind == style.GenericErrorInvalid {
+					n++
+				}
+			}
+		}
+
+		return n
+	}
+
+	want := invalid()
+	require.Positive(t, want)
+
+	m.AddRevision(src)
+	m.View()
+	assert.Equal(t, want, invalid())
+
+	m.SetSearchTerm("value")
+	m.View()
+	assert.Equal(t, want, invalid())
+
+	m.ClearSearch()
+	m.View()
+	assert.Equal(t, want, invalid())
+}
+
 func TestSideBySideSearch_MatchCounting(t *testing.T) {
 	t.Parallel()
 
@@ -2666,3 +2708,410 @@ func TestSideBySideSearch_MatchCounting(t *testing.T) {
 		})
 	}
 }
+
+func TestViewport_Folding(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		metadata:
+		  name: app
+		  labels:
+		    a: b
+		    c: d
+		spec:
+		  replicas: 1
+		  paused: false
+	`)
+
+	newModel := func() yamlviewport.Model {
+		m := yamlviewport.New(yamlviewport.WithPrinter(testPrinterWithLineNumbers()))
+		m.SetWidth(80)
+		m.SetHeight(4)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+
+		return m
+	}
+
+	t.Run("toggle fold at top line", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+
+		m.ToggleFold()
+		assert.Equal(t, 1, m.FoldCount())
+		assert.Equal(t, 4, m.TotalLineCount())
+		assert.Contains(t, m.View(), "   1  metadata: … (5 lines)")
+		assert.Contains(t, m.View(), "   6  spec:")
+
+		m.ToggleFold()
+		assert.Equal(t, 0, m.FoldCount())
+		assert.Equal(t, 8, m.TotalLineCount())
+	})
+
+	t.Run("unavailable without SetFolds", func(t *testing.T) {
+		t.Parallel()
+
+		// Embedding the interface hides SetFolds of the underlying printer.
+		printer := struct{ yamlviewport.Printer }{testPrinterWithLineNumbers()}
+
+		m := yamlviewport.New(yamlviewport.WithPrinter(printer))
+		m.SetWidth(80)
+		m.SetHeight(4)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+
+		m.ToggleFold()
+		assert.Equal(t, 0, m.FoldCount())
+		assert.Equal(t, 8, m.TotalLineCount())
+		assert.Contains(t, m.View(), "   2    name: app")
+	})
+
+	t.Run("toggle fold scrolls to node start", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+		m.SetYOffset(3) // "    a: b", inside labels.
+
+		m.ToggleFold()
+		assert.Equal(t, 2, m.YOffset())
+		assert.Contains(t, m.View(), "   3    labels: … (3 lines)")
+	})
+
+	t.Run("fold to depth and unfold all via keys", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: '1', Text: "1"})
+		assert.Equal(t, 2, m.TotalLineCount())
+		assert.Equal(t, 3, m.FoldCount())
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+		assert.Equal(t, 6, m.TotalLineCount())
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: '0', Text: "0"})
+		assert.Equal(t, 8, m.TotalLineCount())
+		assert.Equal(t, 0, m.FoldCount())
+	})
+
+	t.Run("search reveals folded match", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+		m.FoldToDepth(1)
+
+		m.SetSearchTerm("replicas")
+		assert.Equal(t, 2, m.FoldCount())
+		assert.Contains(t, m.View(), "replicas")
+	})
+
+	t.Run("folds are kept across view modes", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+		m.FoldToDepth(1)
+
+		m.SetViewMode(yamlviewport.ViewModeSideBySide)
+		assert.Equal(t, 0, m.FoldCount())
+		assert.Equal(t, 8, m.TotalLineCount())
+
+		m.SetViewMode(yamlviewport.ViewModeFull)
+		assert.Equal(t, 3, m.FoldCount())
+		assert.Equal(t, 2, m.TotalLineCount())
+	})
+
+	t.Run("folds reset for new content", func(t *testing.T) {
+		t.Parallel()
+
+		m := newModel()
+		m.FoldToDepth(1)
+
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+		assert.Equal(t, 0, m.FoldCount())
+	})
+}
+
+func TestViewport_StickyHeader(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		spec:
+		  template:
+		    containers:
+		      - name: app
+		        image: nginx
+		        ports:
+		          - 80
+		          - 443
+		  replicas: 1
+	`)
+
+	// viewRows returns the rendered rows with trailing padding removed.
+	viewRows := func(m *yamlviewport.Model) []string {
+		rows := strings.Split(m.View(), "\n")
+		for i, row := range rows {
+			rows[i] = strings.TrimRight(row, " ")
+		}
+
+		return rows
+	}
+
+	t.Run("pins ancestors of first visible line", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(
+			yamlviewport.WithPrinter(testPrinterWithLineNumbers()),
+			yamlviewport.WithStickyHeader(true),
+		)
+		m.SetWidth(80)
+		m.SetHeight(6)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+		m.SetYOffset(3)
+
+		want := []string{
+			"   1  spec:",
+			"   2    template:",
+			"   3      containers:",
+			"   4        - name: app",
+			"   5          image: nginx",
+			"   6          ports:",
+		}
+		assert.Equal(t, want, viewRows(&m))
+
+		m.SetYOffset(6)
+
+		want = []string{
+			"   1  spec:",
+			"   2    template:",
+			"   3      containers:",
+			"   4        - name: app",
+			"   6          ports:",
+			"   7            - 80",
+		}
+		assert.Equal(t, want, viewRows(&m))
+
+		// The header shrinks when leaving the nested block, and the last line
+		// remains reachable.
+		m.GotoBottom()
+		assert.Equal(t, 8, m.YOffset())
+
+		want = []string{
+			"   1  spec:",
+			"   9    replicas: 1",
+			"",
+			"",
+			"",
+			"",
+		}
+		assert.Equal(t, want, viewRows(&m))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(yamlviewport.WithPrinter(testPrinterWithLineNumbers()))
+		m.SetWidth(80)
+		m.SetHeight(2)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+		m.SetYOffset(4)
+
+		want := []string{
+			"   5          image: nginx",
+			"   6          ports:",
+		}
+		assert.Equal(t, want, viewRows(&m))
+	})
+
+	t.Run("unified diff", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(
+			yamlviewport.WithPrinter(testPrinter()),
+			yamlviewport.WithStickyHeader(true),
+		)
+		m.SetWidth(60)
+		m.SetHeight(4)
+		m.AddRevision(niceyaml.NewSourceFromString(input))
+		m.AddRevision(niceyaml.NewSourceFromString(strings.Replace(input, "template:", "tmpl:", 1)))
+		m.SetYOffset(3)
+
+		// Each line takes its ancestors from its own revision.
+		want := []string{
+			" spec:",
+			"+  tmpl:",
+			"     containers:",
+			"       - name: app",
+		}
+		assert.Equal(t, want, viewRows(&m))
+	})
+
+	t.Run("side by side", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(
+			yamlviewport.WithPrinter(testPrinter()),
+			yamlviewport.WithStickyHeader(true),
+		)
+		m.SetWidth(60)
+		m.SetHeight(6)
+		m.AddRevision(niceyaml.NewSourceFromString(input))
+		m.AddRevision(niceyaml.NewSourceFromString(strings.Replace(input, "nginx", "httpd", 1)))
+		m.SetViewMode(yamlviewport.ViewModeSideBySide)
+		m.SetYOffset(4)
+
+		want := []string{
+			" spec:                       │   spec:",
+			"   template:                 │     template:",
+			"     containers:             │       containers:",
+			"       - name: app           │         - name: app",
+			"-        image: nginx        │  +        image: httpd",
+			"         ports:              │           ports:",
+		}
+		assert.Equal(t, want, viewRows(&m))
+	})
+
+	t.Run("hunks", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(
+			yamlviewport.WithPrinter(testPrinter()),
+			yamlviewport.WithStickyHeader(true),
+		)
+		m.SetWidth(60)
+		m.SetHeight(20)
+		m.SetHunkContext(0)
+		m.AddRevision(niceyaml.NewSourceFromString(input))
+		m.AddRevision(niceyaml.NewSourceFromString(strings.Replace(input, "443", "8443", 1)))
+		m.SetViewMode(yamlviewport.ViewModeHunks)
+
+		want := []string{
+			" spec:",
+			"   template:",
+			"     containers:",
+			"       - name: app",
+			"         ports:",
+			" @@ -8 +8 @@",
+			"-          - 443",
+			"+          - 8443",
+		}
+		assert.Equal(t, want, viewRows(&m)[:len(want)])
+	})
+}
+
+func TestViewport_Anchors(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		base: &base
+		  x: 1
+		  y: 2
+		other:
+		  a: b
+		  c: d
+		merged:
+		  <<: *base
+		  z: 3
+		copy: *base
+	`)
+
+	t.Run("jump to anchor and back", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(yamlviewport.WithPrinter(testPrinter()))
+		m.SetWidth(80)
+		m.SetHeight(3)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+
+		// No alias is visible.
+		m.JumpToAnchor()
+		assert.Equal(t, 0, m.YOffset())
+
+		m.SetYOffset(6)
+		m.JumpToAnchor()
+		assert.Equal(t, 0, m.YOffset())
+
+		m.JumpBack()
+		assert.Equal(t, 6, m.YOffset())
+
+		// Nothing left to jump back to.
+		m.JumpBack()
+		assert.Equal(t, 6, m.YOffset())
+	})
+
+	t.Run("jump reveals folded anchor", func(t *testing.T) {
+		t.Parallel()
+
+		src := niceyaml.NewSourceFromString(stringtest.Input(`
+			root:
+			  base: &base
+			    x: 1
+			copy: *base
+		`))
+
+		m := yamlviewport.New(yamlviewport.WithPrinter(testPrinter()))
+		m.SetWidth(80)
+		m.SetHeight(2)
+		m.SetTokens(src)
+		m.FoldToDepth(1)
+		require.Equal(t, 2, m.FoldCount())
+
+		// The enclosing fold is opened; the anchored node stays folded, since
+		// its first line is visible.
+		m.JumpToAnchor()
+		assert.Equal(t, 1, m.FoldCount())
+		assert.Equal(t, 1, m.YOffset())
+	})
+
+	t.Run("key bindings", func(t *testing.T) {
+		t.Parallel()
+
+		m := yamlviewport.New(yamlviewport.WithPrinter(testPrinter()))
+		m.SetWidth(80)
+		m.SetHeight(3)
+		m.SetTokens(niceyaml.NewSourceFromString(input))
+		m.SetYOffset(7)
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: '*', Text: "*"})
+		assert.Equal(t, 0, m.YOffset())
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: 'o', Mod: tea.ModCtrl})
+		assert.Equal(t, 7, m.YOffset())
+
+		m, _ = m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+		assert.True(t, m.MergeAnnotations())
+	})
+
+	t.Run("merge annotations", func(t *testing.T) {
+		t.Parallel()
+
+		src := niceyaml.NewSourceFromString(input)
+
+		m := yamlviewport.New(
+			yamlviewport.WithPrinter(testPrinter()),
+			yamlviewport.WithMergeAnnotations(true),
+		)
+		m.SetWidth(80)
+		m.SetHeight(4)
+		m.SetTokens(src)
+		m.SetYOffset(6)
+
+		want := []string{
+			" merged:",
+			"   <<: *base",
+			"       ^ *base: {x: 1, y: 2}",
+			"   z: 3",
+		}
+		rows := strings.Split(m.View(), "\n")
+		for i, row := range rows {
+			rows[i] = strings.TrimRight(row, " ")
+		}
+
+		assert.Equal(t, want, rows)
+
+		for _, ln := range src.Lines() {
+			assert.Empty(t, ln.Annotations, "original source is not modified")
+		}
+
+		m.SetMergeAnnotations(false)
+		assert.NotContains(t, m.View(), "*base: {")
+	})
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/normalizer"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+func catCmd() *cobra.Command {
+	var (
+		themeName   string
+		lineNumbers bool
+		width       int
+		wrap        bool
+		lineRange   string
+		search      string
+		colorMode   string
+		stdinName   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cat [file.yaml...]",
+		Short: "Print YAML files with syntax highlighting",
+		Long: "Print YAML files with syntax highlighting, without starting the viewer.\n" +
+			"Reads stdin if no files are given, or for \"-\".\n" +
+			"Colors depend on the terminal, and are disabled when the output is not a terminal,\n" +
+			"unless --color=always is set, e.g. for \"less -R\".\n" +
+			"Line numbers and wrapping are also disabled when the output is not a terminal,\n" +
+			"unless --line-numbers or --wrap is set.\n" +
+			"Supports glob patterns like *.yaml.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := loadProjectConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			styles, ok := theme.Styles(themeName)
+			if !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			span, err := parseLineRange(lineRange)
+			if err != nil {
+				return err
+			}
+
+			profile, err := outputProfile(cmd.OutOrStdout(), colorMode)
+			if err != nil {
+				return err
+			}
+
+			paths, err := catPaths(args)
+			if err != nil {
+				return err
+			}
+
+			in := newInputs(cmd, stdinName)
+
+			if !term.IsTerminal(os.Stdout.Fd()) {
+				if !cmd.Flags().Changed("line-numbers") {
+					lineNumbers = false
+				}
+
+				if !cmd.Flags().Changed("wrap") {
+					wrap = false
+				}
+			}
+
+			gutter := niceyaml.NoGutter()
+			if lineNumbers {
+				gutter = niceyaml.LineNumberGutter()
+			}
+
+			printerOpts := []niceyaml.PrinterOption{niceyaml.WithStyles(styles), niceyaml.WithGutter(gutter)}
+			if profile < colorprofile.ANSI {
+				printerOpts = []niceyaml.PrinterOption{
+					niceyaml.WithStyles(style.Styles{}),
+					niceyaml.WithStyle(lipgloss.NewStyle()),
+					niceyaml.WithGutter(gutter),
+				}
+			}
+
+			printer := niceyaml.NewPrinter(printerOpts...)
+
+			if width <= 0 {
+				width = stdoutWidth()
+			}
+
+			printer.SetWidth(width)
+			printer.SetWordWrap(wrap)
+
+			out := &colorprofile.Writer{Forward: cmd.OutOrStdout(), Profile: profile}
+
+			for i, path := range paths {
+				source, err := in.source(path)
+				if err != nil {
+					return err
+				}
+
+				if search != "" {
+					highlightMatches(source, search)
+				}
+
+				var lines []string
+
+				if len(paths) > 1 {
+					if i > 0 {
+						lines = append(lines, "")
+					}
+
+					lines = append(lines, printer.Style(style.TextAccent).Render(source.Name()+":"))
+				}
+
+				if s := (position.Spans{span}).Clamp(0, source.Len())[0]; s.Len() > 0 {
+					lines = append(lines, printer.Print(source, s))
+				}
+
+				if len(lines) == 0 {
+					continue
+				}
+
+				_, err = fmt.Fprintln(out, strings.Join(lines, "\n"))
+				if err != nil {
+					return fmt.Errorf("write output: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "theme name")
+	cmd.Flags().BoolVarP(&lineNumbers, "line-numbers", "n", true, "show line numbers")
+	cmd.Flags().IntVarP(&width, "width", "W", 0, "output width (default: terminal width)")
+	cmd.Flags().BoolVar(&wrap, "wrap", true, "wrap lines longer than the output width")
+	cmd.Flags().StringVarP(&lineRange, "lines", "l", "", "lines to print, e.g. 10:40, 10:, or :40")
+	cmd.Flags().StringVarP(&search, "search", "s", "", "highlight matches of a search term")
+	cmd.Flags().StringVar(&colorMode, "color", "auto", "when to use colors: auto, always, or never")
+	addStdinNameFlag(cmd, &stdinName)
+
+	return cmd
+}
+
+// catPaths returns the paths of the files matching args, or [stdinArg] if
+// there are none, since cat reads stdin even from a terminal.
+func catPaths(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{stdinArg}, nil
+	}
+
+	return expandInputs(args)
+}
+
+// parseLineRange parses a range of one-based, inclusive line numbers, such as
+// "10:40", "10:", ":40", or "10", into a [position.Span] of zero-based line
+// indices. An empty range selects all lines.
+func parseLineRange(s string) (position.Span, error) {
+	all := position.NewSpan(0, math.MaxInt)
+	if s == "" {
+		return all, nil
+	}
+
+	startText, endText, isRange := strings.Cut(s, ":")
+	if !isRange {
+		endText = startText
+	}
+
+	span := all
+
+	if startText != "" {
+		start, err := strconv.Atoi(startText)
+		if err != nil || start < 1 {
+			return span, fmt.Errorf("invalid line range %q: start must be a positive number", s)
+		}
+
+		span.Start = start - 1
+	}
+
+	if endText != "" {
+		end, err := strconv.Atoi(endText)
+		if err != nil || end < 1 {
+			return span, fmt.Errorf("invalid line range %q: end must be a positive number", s)
+		}
+
+		span.End = end
+	}
+
+	if span.End <= span.Start {
+		return span, fmt.Errorf("invalid line range %q: end is before start", s)
+	}
+
+	return span, nil
+}
+
+// outputProfile returns the color profile for w: the detected profile for
+// "auto", the profile of the environment for "always", even if w is not a
+// terminal, and [colorprofile.NoTTY] for "never".
+func outputProfile(w io.Writer, mode string) (colorprofile.Profile, error) {
+	switch mode {
+	case "auto":
+		return colorprofile.Detect(w, os.Environ()), nil
+	case "always":
+		return max(colorprofile.Env(os.Environ()), colorprofile.ANSI), nil
+	case "never":
+		return colorprofile.NoTTY, nil
+	default:
+		return colorprofile.NoTTY, fmt.Errorf("unknown color mode %q", mode)
+	}
+}
+
+// highlightMatches highlights the matches of search in source, ignoring case
+// and diacritics like the viewer.
+func highlightMatches(source *niceyaml.Source, search string) {
+	finder := niceyaml.NewFinder(niceyaml.WithNormalizer(normalizer.New()))
+	finder.Load(source)
+
+	for _, rng := range finder.Find(search) {
+		source.AddOverlay(style.GenericHighlight, rng)
+	}
+}
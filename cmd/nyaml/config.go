@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"go.jacobcolvin.com/x/jsonschema"
+
+	_ "embed"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/bubbles/yamlviewport"
+	"go.jacobcolvin.com/niceyaml/internal/filepaths"
+	"go.jacobcolvin.com/niceyaml/lint"
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/schema"
+	"go.jacobcolvin.com/niceyaml/schema/loader"
+	"go.jacobcolvin.com/niceyaml/schema/matcher"
+	"go.jacobcolvin.com/niceyaml/schema/registry"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+// configFile is the name of the nyaml configuration file, see [config].
+const configFile = ".nyaml.yaml"
+
+var (
+	//go:embed config.schema.json
+	configSchemaJSON []byte
+
+	configValidator = schema.NewValidator(jsonschema.MustCompileJSON(configSchemaJSON))
+)
+
+// config is the content of [configFile], which is found in the working
+// directory or its parents:
+//
+//	theme: dracula
+//	line-numbers: false
+//	keys:
+//	  quit: [q, ctrl+c]
+//	  search-next: [n, ctrl+n]
+//	schemas:
+//	  - schema: schemas/deployment.json
+//	    files: ["k8s/**/*.yaml"]
+//	    content:
+//	      $.kind: Deployment
+//	  - schema: https://json.schemastore.org/github-workflow.json
+//	    files: [".github/workflows/*.yaml"]
+//	lint:
+//	  rules:
+//	    line-length: disable
+//
+// Theme and line numbers are the defaults of the --theme and --line-numbers
+// flags of view, cat, and lint. Keys replace the keys of the viewer actions,
+// see [keyBindings]. Schemas map files and document contents to JSON schemas,
+// like the "yaml.schemas" setting of VS Code. Lint is a yamllint-compatible
+// [lint.Config].
+type config struct {
+	LineNumbers *bool               `yaml:"line-numbers"`
+	Lint        *lint.Config        `yaml:"lint"`
+	Keys        map[string][]string `yaml:"keys"`
+	Theme       string              `yaml:"theme"`
+	Schemas     []schemaMapping     `yaml:"schemas"`
+
+	// Directory of the configuration file, which relative paths are
+	// resolved against.
+	dir string
+	// Matchers of Schemas, in the same order.
+	matchers []matcher.Matcher
+	// Options of Lint.
+	lintOptions []lint.Option
+}
+
+// schemaMapping maps files and document contents to a JSON schema.
+// Documents match if their file matches any of Files, and their values
+// match all of Content. Either may be omitted.
+type schemaMapping struct {
+	// Content holds the values of matching documents by YAML path, e.g.
+	// "$.kind".
+	Content map[string]string `yaml:"content"`
+	// Schema is the JSON schema file path, relative to the configuration
+	// file, or http/https URL.
+	Schema string `yaml:"schema"`
+	// Files are glob patterns of matching files, relative to the
+	// configuration file.
+	Files []string `yaml:"files"`
+}
+
+// findConfig returns the path of the nearest [configFile] in dir or its
+// parents, or an empty string if there is none.
+func findConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("find configuration: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, configFile)
+
+		_, err := os.Stat(path)
+		switch {
+		case err == nil:
+			return path, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return "", fmt.Errorf("find configuration: %w", err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// loadConfig reads the configuration file at path and validates it against
+// its embedded JSON schema. Errors are annotated with the file's source.
+func loadConfig(path string) (*config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("load configuration: %w", err)
+	}
+
+	source, err := niceyaml.NewSourceFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := source.Decoder()
+	if err != nil {
+		return nil, source.WrapError(err)
+	}
+
+	cfg := &config{dir: filepath.Dir(absPath)}
+
+	for _, doc := range decoder.Documents() {
+		err = doc.ValidateSchema(configValidator)
+		if err != nil {
+			return nil, source.WrapError(err)
+		}
+
+		err = doc.Decode(cfg)
+		if err != nil {
+			return nil, source.WrapError(err)
+		}
+	}
+
+	err = cfg.compile()
+	if err != nil {
+		return nil, source.WrapError(err)
+	}
+
+	return cfg, nil
+}
+
+// compile checks the values that the schema cannot, such as theme names and
+// glob patterns, and builds the schema matchers and lint options.
+func (c *config) compile() error {
+	if c.Theme != "" {
+		if _, ok := theme.Styles(c.Theme); !ok {
+			return niceyaml.NewError(
+				fmt.Sprintf("unknown theme %q", c.Theme),
+				niceyaml.WithPath(paths.Root().Child("theme").Value()),
+			)
+		}
+	}
+
+	actions := keyBindings(&keyMap{}, &yamlviewport.KeyMap{})
+	for _, name := range slices.Sorted(maps.Keys(c.Keys)) {
+		if _, ok := actions[name]; !ok {
+			return niceyaml.NewError(
+				fmt.Sprintf("unknown key action %q", name),
+				niceyaml.WithPath(paths.Root().Child("keys", name).Key()),
+			)
+		}
+	}
+
+	c.matchers = make([]matcher.Matcher, 0, len(c.Schemas))
+
+	for i, m := range c.Schemas {
+		sm, err := m.matcher(c.dir, i)
+		if err != nil {
+			return err
+		}
+
+		c.matchers = append(c.matchers, sm)
+	}
+
+	if c.Lint != nil {
+		opts, err := c.Lint.Options()
+		if err != nil {
+			return err
+		}
+
+		c.lintOptions = opts
+	}
+
+	return nil
+}
+
+// matcher returns the [matcher.Matcher] of m, with glob patterns relative to
+// dir. Errors are annotated with paths under the mapping at index.
+func (m *schemaMapping) matcher(dir string, index int) (matcher.Matcher, error) {
+	var matchers []matcher.Matcher
+
+	if len(m.Files) > 0 {
+		patterns := make([]filepaths.Pattern, 0, len(m.Files))
+
+		for i, f := range m.Files {
+			p, err := filepaths.NewPattern(f)
+			if err != nil {
+				return nil, niceyaml.NewErrorFrom(
+					fmt.Errorf("%w: %s", err, f),
+					niceyaml.WithPath(paths.Root().Child("schemas").Index(index).Child("files").Index(i).Value()),
+				)
+			}
+
+			patterns = append(patterns, p)
+		}
+
+		matchers = append(matchers, relativeFileMatcher(dir, patterns))
+	}
+
+	for _, expr := range slices.Sorted(maps.Keys(m.Content)) {
+		p, err := paths.FromString(expr)
+		if err != nil {
+			return nil, niceyaml.NewErrorFrom(
+				fmt.Errorf("invalid YAML path %q: %w", expr, err),
+				niceyaml.WithPath(paths.Root().Child("schemas").Index(index).Child("content", expr).Key()),
+			)
+		}
+
+		matchers = append(matchers, matcher.Content(p.Path(), m.Content[expr]))
+	}
+
+	if len(matchers) == 0 {
+		return nil, niceyaml.NewError(
+			"schema mapping needs files or content",
+			niceyaml.WithPath(paths.Root().Child("schemas").Index(index).Value()),
+		)
+	}
+
+	return matcher.All(matchers...), nil
+}
+
+// relativeFileMatcher returns a [matcher.Matcher] for documents of files in
+// dir or its subdirectories whose path relative to dir matches any of
+// patterns.
+func relativeFileMatcher(dir string, patterns []filepaths.Pattern) matcher.Matcher {
+	return matcher.Func(func(_ context.Context, doc *niceyaml.DocumentDecoder) bool {
+		if doc.FilePath() == "" {
+			return false
+		}
+
+		path, err := filepath.Abs(doc.FilePath())
+		if err != nil {
+			return false
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || !filepath.IsLocal(rel) {
+			return false
+		}
+
+		return slices.ContainsFunc(patterns, func(p filepaths.Pattern) bool {
+			return p.Match(rel)
+		})
+	})
+}
+
+// registerSchemas registers the schema mappings of c with reg, in order.
+// Schemas are resolved relative to the configuration file.
+func (c *config) registerSchemas(reg *registry.Registry) {
+	for i, m := range c.Schemas {
+		reg.RegisterFunc(c.matchers[i], loader.Ref(c.dir, m.Schema))
+	}
+}
+
+// loadProjectConfig loads the nearest [configFile], if any, or returns an
+// empty [*config]. Its theme and line numbers become the values of the --theme
+// and --line-numbers flags of cmd, unless they are set on the command line.
+//
+// It is called by the commands that use the configuration only, so that a
+// broken configuration file does not break other commands, such as
+// completion.
+func loadProjectConfig(cmd *cobra.Command) (*config, error) {
+	path, err := findConfig(".")
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return &config{}, nil
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	defaults := map[string]string{}
+	if cfg.Theme != "" {
+		defaults["theme"] = cfg.Theme
+	}
+
+	if cfg.LineNumbers != nil {
+		defaults["line-numbers"] = strconv.FormatBool(*cfg.LineNumbers)
+	}
+
+	for name, value := range defaults {
+		if cmd.Flags().Lookup(name) == nil || cmd.Flags().Changed(name) {
+			continue
+		}
+
+		err := cmd.Flags().Set(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("set %s flag from %s: %w", name, path, err)
+		}
+	}
+
+	return cfg, nil
+}
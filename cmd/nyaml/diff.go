@@ -0,0 +1,268 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/diff"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+// errDifferent indicates that the compared files differ. Like diff(1), it is
+// reported by the exit status only.
+var errDifferent = errors.New("files differ")
+
+// errLayoutConflict indicates that --unified and --side-by-side are both set.
+var errLayoutConflict = errors.New("--unified and --side-by-side cannot be used together")
+
+// sideBySideSeparator is the column divider between the panes of
+// side-by-side diffs.
+const sideBySideSeparator = " │ "
+
+func diffCmd() *cobra.Command {
+	var (
+		themeName     string
+		algorithmName string
+		diffContext   int
+		unified       bool
+		sideBySide    bool
+		noColor       bool
+		width         int
+		stdinName     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff a.yaml b.yaml",
+		Short: "Show differences between YAML files",
+		Long: "Show the differences between two YAML files with syntax highlighting.\n" +
+			"Like diff(1), exits with status 1 if the files differ, and 2 if there is an error.\n" +
+			"Either file may be \"-\" for stdin.\n" +
+			"Works as a git difftool, e.g. with difftool.nyaml.cmd 'nyaml diff \"$LOCAL\" \"$REMOTE\"',\n" +
+			"and as an external diff driver (GIT_EXTERNAL_DIFF), which is passed seven arguments\n" +
+			"and always exits with status 0.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 7 {
+				return nil
+			}
+
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unified && sideBySide {
+				return errLayoutConflict
+			}
+
+			styles, ok := theme.Styles(themeName)
+			if !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			algo, err := newDiffAlgorithm(algorithmName)
+			if err != nil {
+				return err
+			}
+
+			in := newInputs(cmd, stdinName)
+			beforePath, afterPath := args[0], args[1]
+			beforeName, afterName := in.name(beforePath), in.name(afterPath)
+
+			// Git runs external diff drivers with the path, then the old file,
+			// hex, and mode, then the new file, hex, and mode.
+			external := len(args) == 7
+			if external {
+				beforePath, afterPath = args[1], args[4]
+				beforeName, afterName = "a/"+args[0], "b/"+args[0]
+			}
+
+			if beforePath == stdinArg && afterPath == stdinArg {
+				return errStdinTwice
+			}
+
+			before, err := in.source(beforePath, niceyaml.WithName(beforeName))
+			if err != nil {
+				return err
+			}
+
+			after, err := in.source(afterPath, niceyaml.WithName(afterName))
+			if err != nil {
+				return err
+			}
+
+			result := niceyaml.NewDiffer(niceyaml.WithAlgorithm(algo)).
+				Diff(niceyaml.NewRevision(before), niceyaml.NewRevision(after))
+			if added, removed := result.Stats(); added+removed == 0 {
+				return nil
+			}
+
+			printerOpts := []niceyaml.PrinterOption{niceyaml.WithStyles(styles)}
+			if noColor {
+				printerOpts = []niceyaml.PrinterOption{
+					niceyaml.WithStyles(style.Styles{}),
+					niceyaml.WithStyle(lipgloss.NewStyle()),
+				}
+			}
+
+			if sideBySide {
+				printerOpts = append(printerOpts, niceyaml.WithGutter(sideBySideGutter()))
+			}
+
+			printer := niceyaml.NewPrinter(printerOpts...)
+
+			if width <= 0 {
+				width = stdoutWidth()
+			}
+
+			var out string
+
+			switch {
+			case sideBySide:
+				out = renderSideBySide(printer, result, beforeName, afterName, diffContext, width)
+			case unified:
+				out = renderDiffHeader(printer, beforeName, afterName) + printer.Print(result.Unified())
+			default:
+				hunks, spans := result.Hunks(diffContext)
+				out = renderDiffHeader(printer, beforeName, afterName) + printer.Print(hunks, spans...)
+			}
+
+			_, err = lipgloss.Fprintln(cmd.OutOrStdout(), out)
+			if err != nil {
+				return fmt.Errorf("write diff: %w", err)
+			}
+
+			// Git stops when external diff drivers fail.
+			if external {
+				return nil
+			}
+
+			return errDifferent
+		},
+	}
+
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "theme name")
+	cmd.Flags().StringVarP(&algorithmName, "algorithm", "a", "hirschberg", "diff algorithm: hirschberg or patience")
+	cmd.Flags().IntVarP(&diffContext, "context", "C", 3, "lines of context around changes")
+	cmd.Flags().BoolVarP(&unified, "unified", "u", false, "show all lines, not only changes and their context")
+	cmd.Flags().BoolVarP(&sideBySide, "side-by-side", "y", false, "show the files in two columns")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "disable syntax highlighting and colors")
+	cmd.Flags().IntVarP(&width, "width", "W", 0, "output width for --side-by-side (default: terminal width)")
+	addStdinNameFlag(cmd, &stdinName)
+
+	return cmd
+}
+
+// newDiffAlgorithm returns the [diff.Algorithm] with the given name.
+func newDiffAlgorithm(name string) (diff.Algorithm, error) {
+	switch name {
+	case "hirschberg":
+		return diff.NewHirschberg(), nil
+	case "patience":
+		return diff.NewPatience(), nil
+	default:
+		return nil, fmt.Errorf("unknown diff algorithm %q", name)
+	}
+}
+
+// renderDiffHeader renders the "---" and "+++" lines of a unified diff.
+func renderDiffHeader(printer *niceyaml.Printer, beforeName, afterName string) string {
+	return printer.Style(style.GenericDeleted).Render("--- "+beforeName) + "\n" +
+		printer.Style(style.GenericInserted).Render("+++ "+afterName) + "\n"
+}
+
+// renderSideBySide renders the changes of result and their context in two
+// columns that fit in width.
+func renderSideBySide(
+	printer *niceyaml.Printer,
+	result *niceyaml.DiffResult,
+	beforeName, afterName string,
+	context, width int,
+) string {
+	before, after := result.Before(), result.After()
+
+	var changed []int
+
+	for i, ln := range before.Lines() {
+		if ln.Flag != line.FlagDefault || after.Lines()[i].Flag != line.FlagDefault {
+			changed = append(changed, i)
+		}
+	}
+
+	context = max(0, context)
+	spans := position.GroupIndices(changed, context).Expand(context).Clamp(0, before.Len())
+
+	separatorWidth := ansi.StringWidth(sideBySideSeparator)
+	paneWidth := max(1, (width-separatorWidth)/2)
+
+	printer.SetWidth(paneWidth)
+	printer.SetWordWrap(false)
+
+	textStyle := printer.Style(style.Text)
+
+	leftLines := append(
+		[]string{printer.Style(style.GenericDeleted).Render(beforeName)},
+		strings.Split(printer.Print(before, spans...), "\n")...,
+	)
+	rightLines := append(
+		[]string{printer.Style(style.GenericInserted).Render(afterName)},
+		strings.Split(printer.Print(after, spans...), "\n")...,
+	)
+
+	rows := make([]string, max(len(leftLines), len(rightLines)))
+	for i := range rows {
+		var left, right string
+
+		if i < len(leftLines) {
+			left = ansi.Truncate(leftLines[i], paneWidth, "")
+		}
+
+		if i < len(rightLines) {
+			right = ansi.Truncate(rightLines[i], paneWidth, "")
+		}
+
+		if padding := paneWidth - ansi.StringWidth(left); padding > 0 {
+			left += textStyle.Render(strings.Repeat(" ", padding))
+		}
+
+		rows[i] = left + textStyle.Render(sideBySideSeparator) + right
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// sideBySideGutter returns a [niceyaml.GutterFunc] like
+// [niceyaml.DefaultGutter], which leaves the gutter of the placeholder lines
+// of side-by-side diffs blank.
+func sideBySideGutter() niceyaml.GutterFunc {
+	gutter := niceyaml.DefaultGutter()
+
+	return func(ctx niceyaml.GutterContext) string {
+		if ctx.Number > 0 {
+			return gutter(ctx)
+		}
+
+		return ctx.Styles.Style(style.Text).Render(strings.Repeat(" ", ansi.StringWidth(gutter(ctx))))
+	}
+}
+
+// stdoutWidth returns the width of the terminal of stdout, or 80 if stdout is
+// not a terminal.
+func stdoutWidth() int {
+	if term.IsTerminal(os.Stdout.Fd()) {
+		w, _, err := term.GetSize(os.Stdout.Fd())
+		if err == nil && w > 0 {
+			return w
+		}
+	}
+
+	return 80
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"charm.land/lipgloss/v2"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/format"
+	"go.jacobcolvin.com/niceyaml/schema"
+	"go.jacobcolvin.com/niceyaml/schema/loader"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+// errCheckConflict indicates that --check and --write are both set.
+var errCheckConflict = errors.New("--check and --write cannot be used together")
+
+// errStdinWrite indicates that --write is set for stdin.
+var errStdinWrite = errors.New(`--write cannot be used with stdin ("-")`)
+
+// errSchemaRequired indicates that --sort-keys=schema is set without --schema.
+var errSchemaRequired = errors.New("--sort-keys=schema requires --schema")
+
+func fmtCmd() *cobra.Command {
+	var (
+		write           bool
+		check           bool
+		themeName       string
+		indent          int
+		indentSequences bool
+		quoteName       string
+		docStartName    string
+		maxBlankLines   int
+		diffContext     int
+		sortKeys        string
+		schemaRef       string
+		stdinName       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fmt [file.yaml...]",
+		Short: "Format YAML files",
+		Long: "Format YAML files, preserving comments and anchors.\n" +
+			"Formatted files are written to stdout, unless --write or --check is set.\n" +
+			"With --check, the changes that formatting would make are shown instead,\n" +
+			"and the command fails if any file is not formatted.\n" +
+			"Reads stdin for \"-\", or if no files are given and stdin is piped.\n" +
+			"Supports glob patterns like *.yaml.",
+		Args: inputArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if check && write {
+				return errCheckConflict
+			}
+
+			styles, ok := theme.Styles(themeName)
+			if !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			quoteStyle, err := format.ParseQuoteStyle(quoteName)
+			if err != nil {
+				return err
+			}
+
+			docStart, err := format.ParseDocumentStart(docStartName)
+			if err != nil {
+				return err
+			}
+
+			keyOrder, err := loadKeyOrder(cmd.Context(), sortKeys, schemaRef)
+			if err != nil {
+				return err
+			}
+
+			yamlPaths, err := expandInputs(args)
+			if err != nil {
+				return err
+			}
+
+			if write && slices.Contains(yamlPaths, stdinArg) {
+				return errStdinWrite
+			}
+
+			in := newInputs(cmd, stdinName)
+
+			formatter := format.New(
+				format.WithIndent(indent),
+				format.WithIndentSequences(indentSequences),
+				format.WithQuoteStyle(quoteStyle),
+				format.WithDocumentStart(docStart),
+				format.WithMaxBlankLines(maxBlankLines),
+				format.WithKeyOrder(keyOrder),
+			)
+			printer := niceyaml.NewPrinter(niceyaml.WithStyles(styles))
+			unformatted := 0
+
+			for _, yamlPath := range yamlPaths {
+				// The raw content is kept for --check, since sources drop the
+				// final newline, CRLF line endings, and trailing spaces.
+				data, err := in.read(yamlPath)
+				if err != nil {
+					return err
+				}
+
+				source := niceyaml.NewSourceFromString(string(data),
+					niceyaml.WithName(in.name(yamlPath)),
+					niceyaml.WithFilePath(in.name(yamlPath)),
+					niceyaml.WithErrorOptions(
+						niceyaml.WithPrinter(printer),
+						niceyaml.WithWidthFunc(getTerminalWidth),
+					),
+				)
+
+				formatted, err := formatter.Format(source)
+				if err != nil {
+					return err
+				}
+
+				if check {
+					if string(data) == formattedContent(formatted) {
+						continue
+					}
+
+					unformatted++
+
+					result := niceyaml.Diff(niceyaml.NewRevision(source), niceyaml.NewRevision(formatted))
+
+					changes := printer.Style(style.TextSubtle).Render("only line endings or trailing whitespace differ")
+					if added, removed := result.Stats(); added+removed > 0 {
+						hunks, spans := result.Hunks(diffContext)
+						changes = printer.Print(hunks, spans...)
+					}
+
+					_, err = lipgloss.Fprintln(cmd.OutOrStdout(), in.name(yamlPath)+":\n"+changes+"\n")
+					if err != nil {
+						return fmt.Errorf("write diff: %w", err)
+					}
+
+					continue
+				}
+
+				if write {
+					err = writeFormatted(yamlPath, formatted)
+					if err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				_, err = fmt.Fprint(cmd.OutOrStdout(), formattedContent(formatted))
+				if err != nil {
+					return fmt.Errorf("write output: %w", err)
+				}
+			}
+
+			if unformatted > 0 {
+				return fmt.Errorf("%d of %d files are not formatted", unformatted, len(yamlPaths))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "write formatted files in place")
+	cmd.Flags().BoolVar(&check, "check", false, "show what would change, and fail if any file is not formatted")
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "theme name")
+	cmd.Flags().IntVar(&indent, "indent", 2, "spaces per indentation level")
+	cmd.Flags().BoolVar(&indentSequences, "indent-sequences", true, "indent sequences nested in mappings")
+	cmd.Flags().StringVar(&quoteName, "quote", "preserve", "quote style: preserve, single, or double")
+	cmd.Flags().StringVar(&docStartName, "document-start", "preserve",
+		"document start marker: preserve, require, or forbid")
+	cmd.Flags().IntVar(&maxBlankLines, "max-blank-lines", 1, "maximum number of consecutive blank lines")
+	cmd.Flags().IntVar(&diffContext, "context", 3, "lines of context around changes with --check")
+	cmd.Flags().StringVar(&sortKeys, "sort-keys", "",
+		"sort mapping keys: alphabetical, kubernetes, or schema (property order of --schema)")
+	cmd.Flags().StringVarP(&schemaRef, "schema", "s", "", "schema file path or URL for --sort-keys=schema")
+	addStdinNameFlag(cmd, &stdinName)
+
+	return cmd
+}
+
+// loadKeyOrder returns the [niceyaml.KeyOrder] named by sortKeys, or nil if
+// sortKeys is empty. The "schema" order loads the schema at schemaRef.
+func loadKeyOrder(ctx context.Context, sortKeys, schemaRef string) (niceyaml.KeyOrder, error) {
+	switch sortKeys {
+	case "":
+		return nil, nil
+	case "alphabetical":
+		return niceyaml.AlphabeticalKeyOrder(), nil
+	case "kubernetes":
+		return niceyaml.KubernetesKeyOrder(), nil
+	case "schema":
+		if schemaRef == "" {
+			return nil, errSchemaRequired
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("get working directory: %w", err)
+		}
+
+		result, err := loader.Ref(cwd, schemaRef).Load(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("load schema: %w", err)
+		}
+
+		order, err := schema.PropertyOrder(result.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", schemaRef, err)
+		}
+
+		return order, nil
+	default:
+		return nil, fmt.Errorf("unknown key order %q", sortKeys)
+	}
+}
+
+// formattedContent returns the content of the formatted source, with a
+// trailing newline unless it is empty.
+func formattedContent(formatted *niceyaml.Source) string {
+	content := formatted.Content()
+	if content == "" {
+		return ""
+	}
+
+	return content + "\n"
+}
+
+// writeFormatted writes the formatted source to path, keeping its
+// permissions. Files that are already formatted are not written.
+func writeFormatted(path string, formatted *niceyaml.Source) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("write formatted file: %w", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided file paths are intentional.
+	if err != nil {
+		return fmt.Errorf("write formatted file: %w", err)
+	}
+
+	content := formattedContent(formatted)
+	if string(data) == content {
+		return nil
+	}
+
+	err = os.WriteFile(path, []byte(content), info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("write formatted file: %w", err)
+	}
+
+	return nil
+}
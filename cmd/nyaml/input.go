@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/internal/filepaths"
+)
+
+// stdinArg is the file argument that reads stdin.
+const stdinArg = "-"
+
+// errStdinTwice indicates that stdin is given more than once.
+var errStdinTwice = errors.New(`stdin ("-") can only be read once`)
+
+// inputs opens the file arguments of a command, which may be [stdinArg] for
+// stdin, e.g. for "kubectl get -o yaml | nyaml view".
+type inputs struct {
+	stdin     io.Reader
+	stdinName string
+}
+
+// newInputs creates new [*inputs] reading the stdin of cmd, named stdinName.
+func newInputs(cmd *cobra.Command, stdinName string) *inputs {
+	return &inputs{stdin: cmd.InOrStdin(), stdinName: stdinName}
+}
+
+// addStdinNameFlag adds the --stdin-name flag, which sets the name of stdin.
+func addStdinNameFlag(cmd *cobra.Command, name *string) {
+	cmd.Flags().StringVar(name, "stdin-name", "stdin",
+		`name and path of stdin ("-") in output and for schema matching, e.g. deployment.yaml`)
+}
+
+// name returns the display name of path.
+func (in *inputs) name(path string) string {
+	if path == stdinArg {
+		return in.stdinName
+	}
+
+	return path
+}
+
+// read returns the content of path, or of stdin for [stdinArg].
+func (in *inputs) read(path string) ([]byte, error) {
+	if path != stdinArg {
+		data, err := os.ReadFile(path) //nolint:gosec // User-provided file paths are intentional.
+		if err != nil {
+			return nil, fmt.Errorf("read file %s: %w", path, err)
+		}
+
+		return data, nil
+	}
+
+	data, err := io.ReadAll(in.stdin)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+
+	return data, nil
+}
+
+// source returns a [*niceyaml.Source] for path, or for stdin for [stdinArg],
+// with the stdin name as its name and file path.
+func (in *inputs) source(path string, opts ...niceyaml.SourceOption) (*niceyaml.Source, error) {
+	if path != stdinArg {
+		return niceyaml.NewSourceFromFile(path, opts...)
+	}
+
+	opts = append([]niceyaml.SourceOption{
+		niceyaml.WithName(in.stdinName),
+		niceyaml.WithFilePath(in.stdinName),
+	}, opts...)
+
+	source, err := niceyaml.NewSourceFromReader(in.stdin, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+
+	return source, nil
+}
+
+// inputArgs is a [cobra.PositionalArgs] for commands that read files or
+// stdin, which requires at least one argument unless stdin is piped.
+func inputArgs(cmd *cobra.Command, args []string) error {
+	if stdinPiped() {
+		return nil
+	}
+
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+// expandInputs expands the glob patterns of args, keeping [stdinArg]. Without
+// args, it returns [stdinArg] if stdin is piped.
+func expandInputs(args []string) ([]string, error) {
+	if len(args) == 0 && stdinPiped() {
+		return []string{stdinArg}, nil
+	}
+
+	stdinArgs := 0
+
+	for _, arg := range args {
+		if arg == stdinArg {
+			stdinArgs++
+		}
+	}
+
+	if stdinArgs > 1 {
+		return nil, errStdinTwice
+	}
+
+	return filepaths.Expand(args...)
+}
+
+// stdinPiped reports whether stdin is not a terminal, e.g. a pipe or a file.
+func stdinPiped() bool {
+	return !term.IsTerminal(os.Stdin.Fd())
+}
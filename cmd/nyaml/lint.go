@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"charm.land/lipgloss/v2"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/lint"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+func lintCmd() *cobra.Command {
+	var (
+		configPath string
+		themeName  string
+		formatName string
+		strict     bool
+		stdinName  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [file.yaml...]",
+		Short: "Lint YAML files",
+		Long: "Lint YAML files with yamllint-equivalent rules.\n" +
+			"Rules are configured by the \"lint\" key of the nearest " + configFile + " in the current\n" +
+			"directory or its parents, or else by a .yamllint file in the current directory,\n" +
+			"unless --config is set.\n" +
+			"Comments such as \"# nyaml-disable-line truthy\" disable rules inline.\n" +
+			"Reads stdin for \"-\", or if no files are given and stdin is piped.\n" +
+			"Supports glob patterns like *.yaml.",
+		Args: inputArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadProjectConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			styles, ok := theme.Styles(themeName)
+			if !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			errorFormat, err := niceyaml.ParseErrorFormat(formatName)
+			if err != nil {
+				return err
+			}
+
+			opts, err := loadLintOptions(configPath, cfg)
+			if err != nil {
+				return err
+			}
+
+			yamlPaths, err := expandInputs(args)
+			if err != nil {
+				return err
+			}
+
+			in := newInputs(cmd, stdinName)
+
+			linter := lint.New(opts...)
+			printer := niceyaml.NewPrinter(niceyaml.WithStyles(styles))
+			failed := 0
+
+			for _, yamlPath := range yamlPaths {
+				source, err := in.source(
+					yamlPath,
+					niceyaml.WithErrorOptions(
+						niceyaml.WithPrinter(printer),
+						niceyaml.WithWidthFunc(getTerminalWidth),
+						niceyaml.WithErrorFormat(errorFormat),
+					),
+				)
+				if err != nil {
+					return err
+				}
+
+				err = linter.Lint(source)
+				if err == nil {
+					continue
+				}
+
+				if hasLintErrors(err, strict) {
+					failed++
+				}
+
+				out := err.Error()
+				if errorFormat == niceyaml.ErrorFormatFull {
+					out = in.name(yamlPath) + ": " + out + "\n"
+				}
+
+				_, err = lipgloss.Fprintln(cmd.OutOrStdout(), out)
+				if err != nil {
+					return fmt.Errorf("write problems: %w", err)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d files have problems", failed, len(yamlPaths))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "configuration file path ("+configFile+" or .yamllint)")
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "theme name")
+	cmd.Flags().StringVarP(&formatName, "format", "f", "full", "error format: full, compact, or github")
+	cmd.Flags().BoolVar(&strict, "strict", false, "fail on warnings too")
+	addStdinNameFlag(cmd, &stdinName)
+
+	return cmd
+}
+
+// hasLintErrors reports whether the problems in err, as returned by
+// [lint.Linter.Lint], include errors, or warnings if strict is set.
+func hasLintErrors(err error, strict bool) bool {
+	var yamlErr *niceyaml.Error
+	if !errors.As(err, &yamlErr) {
+		return true
+	}
+
+	problems := yamlErr.Errors()
+	if len(problems) == 0 {
+		problems = []*niceyaml.Error{yamlErr}
+	}
+
+	for _, p := range problems {
+		if p.Severity() == niceyaml.SeverityError || strict && p.Severity() == niceyaml.SeverityWarning {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadLintOptions returns the [lint.Option]s configured by the file at path.
+//
+// If path is empty, the configuration is the "lint" key of cfg, or a
+// yamllint configuration file in the current directory, if any.
+func loadLintOptions(path string, cfg *config) ([]lint.Option, error) {
+	if path == "" {
+		if cfg.Lint != nil {
+			return cfg.lintOptions, nil
+		}
+
+		var err error
+
+		path, err = lint.FindConfig(".")
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	if filepath.Base(path) == configFile {
+		fileCfg, err := loadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return fileCfg.lintOptions, nil
+	}
+
+	lintCfg, err := lint.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return lintCfg.Options()
+}
@@ -1,8 +1,11 @@
-// Package main provides the nyaml CLI for viewing and validating YAML files.
+// Package main provides the nyaml CLI for viewing, printing, diffing,
+// validating, linting, and formatting YAML files.
 package main
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 
 	"charm.land/fang/v2"
@@ -20,7 +23,7 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "nyaml",
 		Short: "A terminal YAML utility with syntax highlighting",
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(*cobra.Command, []string) error {
 			return p.Start()
 		},
 	}
@@ -30,12 +33,17 @@ func main() {
 	cfg.MustRegisterCompletions(rootCmd)
 
 	rootCmd.AddCommand(viewCmd())
+	rootCmd.AddCommand(diffCmd())
+	rootCmd.AddCommand(catCmd())
 	rootCmd.AddCommand(validateCmd())
+	rootCmd.AddCommand(lintCmd())
+	rootCmd.AddCommand(fmtCmd())
+	rootCmd.AddCommand(svgCmd())
 
 	styles, _ := theme.Styles("charm")
 
 	err := fang.Execute(context.Background(), rootCmd,
-		fang.WithErrorHandler(fangs.ErrorHandler),
+		fang.WithErrorHandler(errorHandler),
 		fang.WithColorSchemeFunc(fangs.ColorSchemeFunc(styles)),
 	)
 
@@ -45,6 +53,32 @@ func main() {
 	}
 
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitStatus(rootCmd, err))
 	}
 }
+
+// exitStatus returns the exit status for err. Like diff(1), the diff command
+// exits with status 1 if the files differ, and 2 for other errors. Other
+// commands exit with status 1.
+func exitStatus(rootCmd *cobra.Command, err error) int {
+	if errors.Is(err, errDifferent) {
+		return 1
+	}
+
+	cmd, _, findErr := rootCmd.Find(os.Args[1:])
+	if findErr == nil && cmd.Name() == "diff" {
+		return 2
+	}
+
+	return 1
+}
+
+// errorHandler prints errors with [fangs.ErrorHandler], except for errors that
+// are reported by the exit status only, such as [errDifferent].
+func errorHandler(w io.Writer, styles fang.Styles, err error) {
+	if errors.Is(err, errDifferent) {
+		return
+	}
+
+	fangs.ErrorHandler(w, styles, err)
+}
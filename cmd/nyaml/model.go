@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -8,6 +9,7 @@ import (
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 
 	tea "charm.land/bubbletea/v2"
 
@@ -24,17 +26,82 @@ type fileEntry struct {
 }
 
 type modelOptions struct {
+	keys        map[string][]string
 	search      string
+	theme       string
 	files       []fileEntry
 	lineNumbers bool
 }
 
+// keyMap defines the keybindings of the viewer, in addition to the
+// [yamlviewport.KeyMap] of its viewport.
+type keyMap struct {
+	Quit           key.Binding
+	Theme          key.Binding
+	Search         key.Binding
+	SearchNext     key.Binding
+	SearchPrevious key.Binding
+	ClearSearch    key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+}
+
+// defaultKeyMap returns a new [keyMap] with the default keybindings.
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Quit:           key.NewBinding(key.WithKeys("q", "ctrl+c")),
+		Theme:          key.NewBinding(key.WithKeys("t")),
+		Search:         key.NewBinding(key.WithKeys("/")),
+		SearchNext:     key.NewBinding(key.WithKeys("n")),
+		SearchPrevious: key.NewBinding(key.WithKeys("N")),
+		ClearSearch:    key.NewBinding(key.WithKeys("esc")),
+		Top:            key.NewBinding(key.WithKeys("g")),
+		Bottom:         key.NewBinding(key.WithKeys("G")),
+	}
+}
+
+// keyBindings returns the keybindings of keys and vp by their action names in
+// the configuration file. Folding to a depth is not included, since the depth
+// is given by the pressed digit.
+func keyBindings(keys *keyMap, vp *yamlviewport.KeyMap) map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":                     &keys.Quit,
+		"theme":                    &keys.Theme,
+		"search":                   &keys.Search,
+		"search-next":              &keys.SearchNext,
+		"search-previous":          &keys.SearchPrevious,
+		"clear-search":             &keys.ClearSearch,
+		"top":                      &keys.Top,
+		"bottom":                   &keys.Bottom,
+		"page-down":                &vp.PageDown,
+		"page-up":                  &vp.PageUp,
+		"half-page-down":           &vp.HalfPageDown,
+		"half-page-up":             &vp.HalfPageUp,
+		"down":                     &vp.Down,
+		"up":                       &vp.Up,
+		"left":                     &vp.Left,
+		"right":                    &vp.Right,
+		"next-revision":            &vp.NextRevision,
+		"prev-revision":            &vp.PrevRevision,
+		"toggle-diff-mode":         &vp.ToggleDiffMode,
+		"toggle-view-mode":         &vp.ToggleViewMode,
+		"toggle-word-wrap":         &vp.ToggleWordWrap,
+		"toggle-fold":              &vp.ToggleFold,
+		"unfold-all":               &vp.UnfoldAll,
+		"jump-to-anchor":           &vp.JumpToAnchor,
+		"jump-back":                &vp.JumpBack,
+		"toggle-merge-annotations": &vp.ToggleMergeAnnotations,
+	}
+}
+
 type model struct {
 	searchInput   string
 	currentTheme  string
 	previousTheme string
 	themeList     []string
 	viewport      yamlviewport.Model
+	keys          keyMap
+	syntaxErrors  []string // Summaries by revision index, see [syntaxSummary].
 	width         int
 	height        int
 	themeIndex    int
@@ -50,6 +117,9 @@ func newModel(opts *modelOptions) model {
 
 	// Default theme.
 	defaultTheme := "charm"
+	if opts.theme != "" {
+		defaultTheme = opts.theme
+	}
 
 	// Create printer with options.
 	printerOpts := buildPrinterOpts(opts.lineNumbers, defaultTheme)
@@ -65,17 +135,31 @@ func newModel(opts *modelOptions) model {
 
 	m := model{
 		viewport:     vp,
+		keys:         defaultKeyMap(),
 		themeList:    themeList,
 		themeIndex:   themeIndex,
 		currentTheme: defaultTheme,
 		lineNumbers:  opts.lineNumbers,
 	}
 
+	bindings := keyBindings(&m.keys, &m.viewport.KeyMap)
+	for name, keys := range opts.keys {
+		if binding, ok := bindings[name]; ok {
+			binding.SetKeys(keys...)
+		}
+	}
+
 	for _, f := range opts.files {
-		m.viewport.AddRevision(niceyaml.NewSourceFromString(
+		src := niceyaml.NewSourceFromString(
 			string(f.content),
 			niceyaml.WithName(filepath.Base(f.path)),
-		))
+		)
+
+		// Highlight invalid regions; the file is shown as is regardless.
+		_, err := src.ParseTolerant()
+		m.syntaxErrors = append(m.syntaxErrors, syntaxSummary(src, err))
+
+		m.viewport.AddRevision(src)
 	}
 
 	// Apply initial search if provided.
@@ -119,30 +203,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		switch {
-		case key.Matches(msg, key.NewBinding(key.WithKeys("q", "ctrl+c"))):
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("t"))):
+		case key.Matches(msg, m.keys.Theme):
 			m.themePicking = true
 			m.previousTheme = m.currentTheme
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("/"))):
+		case key.Matches(msg, m.keys.Search):
 			m.searching = true
 			m.searchInput = ""
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("n"))):
+		case key.Matches(msg, m.keys.SearchNext):
 			m.viewport.SearchNext()
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("N"))):
+		case key.Matches(msg, m.keys.SearchPrevious):
 			m.viewport.SearchPrevious()
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+		case key.Matches(msg, m.keys.ClearSearch):
 			m.viewport.ClearSearch()
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("g"))):
+		case key.Matches(msg, m.keys.Top):
 			m.viewport.GotoTop()
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("G"))):
+		case key.Matches(msg, m.keys.Bottom):
 			m.viewport.GotoBottom()
 		}
 	}
@@ -434,6 +518,10 @@ func (m *model) textLine() string {
 		{fmt.Sprintf("col %d", m.viewport.XOffset()), style.TextSubtle},
 	}
 
+	if idx := m.viewport.RevisionIndex(); idx < len(m.syntaxErrors) && m.syntaxErrors[idx] != "" {
+		swatches = append(swatches, swatch{m.syntaxErrors[idx], style.TextError})
+	}
+
 	sep := styles.Style(style.TextSubtleDim).Inline(true).Render(" · ")
 
 	var sb strings.Builder
@@ -448,6 +536,9 @@ func (m *model) textLine() string {
 
 	result := sb.String()
 
+	// Syntax errors may be longer than the terminal is wide.
+	result = ansi.Truncate(result, m.width, "…")
+
 	// Right-pad with Text style to fill width.
 	contentWidth := lipgloss.Width(result)
 	remaining := max(0, m.width-contentWidth)
@@ -455,6 +546,36 @@ func (m *model) textLine() string {
 	return result + textStyle.Render(strings.Repeat(" ", remaining))
 }
 
+// syntaxSummary returns a one-line summary of the syntax errors of src, as
+// returned by [niceyaml.Source.ParseTolerant], or an empty string if err is
+// nil.
+func syntaxSummary(src *niceyaml.Source, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var yamlErr *niceyaml.Error
+	if !errors.As(err, &yamlErr) {
+		return err.Error()
+	}
+
+	errs := yamlErr.Errors()
+	if len(errs) == 0 {
+		errs = []*niceyaml.Error{yamlErr}
+	}
+
+	summary := errs[0].Message()
+	if rng, ok := errs[0].ResolveRange(src); ok {
+		summary = fmt.Sprintf("line %d: %s", rng.Start.Line+1, summary)
+	}
+
+	if len(errs) > 1 {
+		summary += fmt.Sprintf(" (+%d more)", len(errs)-1)
+	}
+
+	return summary
+}
+
 func buildPrinterOpts(lineNumbers bool, themeName string) []niceyaml.PrinterOption {
 	var opts []niceyaml.PrinterOption
 
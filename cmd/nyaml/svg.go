@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"charm.land/lipgloss/v2"
+	"github.com/spf13/cobra"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+	"go.jacobcolvin.com/niceyaml/svg"
+)
+
+func svgCmd() *cobra.Command {
+	var (
+		output      string
+		themeName   string
+		title       string
+		lineNumbers bool
+		window      bool
+		fontSize    float64
+		stdinName   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "svg [file.yaml]",
+		Short: "Render a YAML file as an SVG image",
+		Long: "Render a YAML file as an SVG image.\nThe image is written to stdout unless --output is set.\n" +
+			"Reads stdin for \"-\", or if no file is given and stdin is piped.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && stdinPiped() {
+				return nil
+			}
+
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styles, ok := theme.Styles(themeName)
+			if !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			path := stdinArg
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			in := newInputs(cmd, stdinName)
+
+			source, err := in.source(path)
+			if err != nil {
+				return err
+			}
+
+			gutter := niceyaml.NoGutter()
+			if lineNumbers {
+				gutter = niceyaml.LineNumberGutter()
+			}
+
+			printer := niceyaml.NewPrinter(
+				niceyaml.WithStyles(styles),
+				niceyaml.WithStyle(lipgloss.NewStyle()),
+				niceyaml.WithGutter(gutter),
+			)
+
+			opts := []svg.Option{
+				svg.WithStyles(styles),
+				svg.WithFontSize(fontSize),
+			}
+
+			if window {
+				if title == "" {
+					title = filepath.Base(in.name(path))
+				}
+
+				opts = append(opts, svg.WithWindow(title))
+			}
+
+			image := svg.Render(printer.Print(source), opts...)
+
+			if output == "" {
+				_, err = fmt.Fprint(cmd.OutOrStdout(), image)
+				if err != nil {
+					return fmt.Errorf("write image: %w", err)
+				}
+
+				return nil
+			}
+
+			err = os.WriteFile(output, []byte(image), 0o644) //nolint:gosec // Images are not sensitive.
+			if err != nil {
+				return fmt.Errorf("write image: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output file path")
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "theme name")
+	cmd.Flags().BoolVarP(&lineNumbers, "line-numbers", "n", true, "show line numbers")
+	cmd.Flags().BoolVarP(&window, "window", "w", true, "draw a terminal window frame")
+	cmd.Flags().StringVar(&title, "title", "", "window title (defaults to the file name)")
+	cmd.Flags().Float64Var(&fontSize, "font-size", svg.DefaultFontSize, "font size in pixels")
+	addStdinNameFlag(cmd, &stdinName)
+
+	return cmd
+}
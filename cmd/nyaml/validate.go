@@ -10,42 +10,94 @@ import (
 	"github.com/spf13/cobra"
 
 	"go.jacobcolvin.com/niceyaml"
-	"go.jacobcolvin.com/niceyaml/internal/filepaths"
+	"go.jacobcolvin.com/niceyaml/report"
 	"go.jacobcolvin.com/niceyaml/schema/loader"
 	"go.jacobcolvin.com/niceyaml/schema/matcher"
 	"go.jacobcolvin.com/niceyaml/schema/registry"
 	"go.jacobcolvin.com/niceyaml/schema/registry/schemastore"
 )
 
+// errFormatConflict indicates that --format is set with an --output other
+// than text, which --format does not apply to.
+var errFormatConflict = errors.New("--format can only be used with --output=text")
+
 func validateCmd() *cobra.Command {
+	var stdinName string
+
 	cmd := &cobra.Command{
-		Use:   "validate file.yaml [file.yaml...]",
+		Use:   "validate [file.yaml...]",
 		Short: "Validate YAML files",
-		Long:  "Validate YAML files.\nOptionally validate against a JSON schema (local file or http/https URL).\nSupports glob patterns like *.yaml.",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Validate YAML files.\nOptionally validate against a JSON schema (local file or http/https URL).\n" +
+			"Otherwise, schemas are set by directives, by the \"schemas\" of the nearest " + configFile + ",\n" +
+			"or by SchemaStore.\n" +
+			"Reads stdin for \"-\", or if no files are given and stdin is piped.\n" +
+			"Supports glob patterns like *.yaml.",
+		Args: inputArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadProjectConfig(cmd)
+			if err != nil {
+				return err
+			}
+
 			schemaRef, err := cmd.Flags().GetString("schema")
 			if err != nil {
 				return fmt.Errorf("get schema flag: %w", err)
 			}
 
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return fmt.Errorf("get output flag: %w", err)
+			}
+
+			formatName, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return fmt.Errorf("get format flag: %w", err)
+			}
+
+			errorFormat, err := niceyaml.ParseErrorFormat(formatName)
+			if err != nil {
+				return err
+			}
+
+			var format report.Format
+			if output != "text" {
+				if cmd.Flags().Changed("format") {
+					return errFormatConflict
+				}
+
+				format, err = report.ParseFormat(output)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Expand glob patterns.
-			yamlPaths, err := filepaths.Expand(args...)
+			yamlPaths, err := expandInputs(args)
 			if err != nil {
 				return err
 			}
 
+			in := newInputs(cmd, stdinName)
+
 			// Build registry once for all files to enable cross-file schema caching.
-			reg := buildRegistry(cmd.Context(), schemaRef)
+			reg := buildRegistry(cmd.Context(), schemaRef, cfg)
+
+			if format != "" {
+				return validateReport(cmd, in, format, yamlPaths, reg)
+			}
+
+			if errorFormat != niceyaml.ErrorFormatFull {
+				return validateLines(cmd, in, errorFormat, yamlPaths, reg)
+			}
 
 			var errs []error
 
 			for _, yamlPath := range yamlPaths {
-				err := validateFile(cmd.Context(), yamlPath, reg)
+				err := validateFile(cmd.Context(), in, yamlPath, reg)
 				if err != nil {
-					errs = append(errs, fmt.Errorf("%s: %w", yamlPath, err))
+					errs = append(errs, fmt.Errorf("%s: %w", in.name(yamlPath), err))
 				} else {
-					fmt.Printf("%s: valid\n", yamlPath)
+					fmt.Printf("%s: valid\n", in.name(yamlPath))
 				}
 			}
 
@@ -54,10 +106,89 @@ func validateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("schema", "s", "", "JSON schema file path or URL")
+	cmd.Flags().StringP("output", "o", "text", "output format: text, json, sarif, junit, or checkstyle")
+	cmd.Flags().StringP("format", "f", "full", "text error format: full, compact, or github")
+	addStdinNameFlag(cmd, &stdinName)
 
 	return cmd
 }
 
+// validateReport validates the given files and writes the results to stdout in
+// the given machine-readable format.
+// Returns an error if any file has errors.
+func validateReport(
+	cmd *cobra.Command,
+	in *inputs,
+	format report.Format,
+	yamlPaths []string,
+	reg *registry.Registry,
+) error {
+	files := make([]report.File, 0, len(yamlPaths))
+	invalid := 0
+
+	for _, yamlPath := range yamlPaths {
+		f := report.NewFile(in.name(yamlPath), validateFile(cmd.Context(), in, yamlPath, reg))
+		if f.Errors() > 0 {
+			invalid++
+		}
+
+		files = append(files, f)
+	}
+
+	err := report.Encode(cmd.OutOrStdout(), format, files...)
+	if err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files invalid", invalid, len(files))
+	}
+
+	return nil
+}
+
+// validateLines validates the given files and writes their errors to stdout
+// in the given line-based error format, one line per error.
+// Returns an error if any file has errors.
+func validateLines(
+	cmd *cobra.Command,
+	in *inputs,
+	format niceyaml.ErrorFormat,
+	yamlPaths []string,
+	reg *registry.Registry,
+) error {
+	invalid := 0
+
+	for _, yamlPath := range yamlPaths {
+		err := validateFile(cmd.Context(), in, yamlPath, reg, niceyaml.WithErrorFormat(format))
+		if err == nil {
+			continue
+		}
+
+		invalid++
+
+		// Errors without a source, e.g. read errors, still need a file name.
+		var yamlErr *niceyaml.Error
+		if !errors.As(err, &yamlErr) {
+			yamlErr = niceyaml.NewErrorFrom(err,
+				niceyaml.WithErrorFormat(format),
+				niceyaml.WithSource(niceyaml.NewSourceFromString("", niceyaml.WithFilePath(in.name(yamlPath)))),
+			)
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), yamlErr.Error())
+		if err != nil {
+			return fmt.Errorf("write errors: %w", err)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files invalid", invalid, len(yamlPaths))
+	}
+
+	return nil
+}
+
 func getTerminalWidth() int {
 	width := 90
 
@@ -71,11 +202,18 @@ func getTerminalWidth() int {
 	return max(0, width-2)
 }
 
-func validateFile(ctx context.Context, yamlPath string, reg *registry.Registry) error {
-	source, err := niceyaml.NewSourceFromFile(
+func validateFile(
+	ctx context.Context,
+	in *inputs,
+	yamlPath string,
+	reg *registry.Registry,
+	errOpts ...niceyaml.ErrorOption,
+) error {
+	source, err := in.source(
 		yamlPath,
+		niceyaml.WithDuplicateKeyCheck(),
 		niceyaml.WithErrorOptions(
-			niceyaml.WithWidthFunc(getTerminalWidth),
+			append([]niceyaml.ErrorOption{niceyaml.WithWidthFunc(getTerminalWidth)}, errOpts...)...,
 		),
 	)
 	if err != nil {
@@ -84,7 +222,7 @@ func validateFile(ctx context.Context, yamlPath string, reg *registry.Registry)
 
 	decoder, err := source.Decoder()
 	if err != nil {
-		return err
+		return source.WrapError(err)
 	}
 
 	for i, doc := range decoder.Documents() {
@@ -105,8 +243,9 @@ func validateFile(ctx context.Context, yamlPath string, reg *registry.Registry)
 //
 // Otherwise, directive-based matching is enabled with per-file resolution
 // (schemas referenced in directives are resolved relative to each YAML file),
-// followed by SchemaStore automatic discovery.
-func buildRegistry(ctx context.Context, schemaRef string) *registry.Registry {
+// followed by the schema mappings of cfg (resolved relative to its file), and
+// SchemaStore automatic discovery.
+func buildRegistry(ctx context.Context, schemaRef string, cfg *config) *registry.Registry {
 	reg := registry.New()
 
 	// CLI schema flag takes precedence - register first with always-matching.
@@ -129,6 +268,9 @@ func buildRegistry(ctx context.Context, schemaRef string) *registry.Registry {
 	// Directive resolves schemas relative to each YAML file.
 	reg.Register(registry.Directive())
 
+	// Configured schema mappings, in order.
+	cfg.registerSchemas(reg)
+
 	// SchemaStore automatic discovery (best-effort).
 	store, err := schemastore.New(ctx)
 	if err != nil {
@@ -2,50 +2,83 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
 	tea "charm.land/bubbletea/v2"
 
-	"go.jacobcolvin.com/niceyaml/internal/filepaths"
+	"go.jacobcolvin.com/niceyaml/style/theme"
 )
 
 func viewCmd() *cobra.Command {
 	var (
 		lineNumbers bool
+		themeName   string
 		search      string
+		stdinName   string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "view file.yaml [pattern...]",
+		Use:   "view [file.yaml] [pattern...]",
 		Short: "View YAML files with syntax highlighting",
-		Args:  cobra.MinimumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
-			paths, err := filepaths.Expand(args...)
+		Long: "View YAML files with syntax highlighting.\n" +
+			"Reads stdin for \"-\", or if no files are given and stdin is piped,\n" +
+			"e.g. \"kubectl get deploy -o yaml | nyaml view\".\n" +
+			"Keys are configured by the \"keys\" of the nearest " + configFile + ".",
+		Args: inputArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadProjectConfig(cmd)
 			if err != nil {
 				return err
 			}
 
+			if _, ok := theme.Styles(themeName); !ok {
+				return fmt.Errorf("unknown theme %q", themeName)
+			}
+
+			paths, err := expandInputs(args)
+			if err != nil {
+				return err
+			}
+
+			in := newInputs(cmd, stdinName)
+
 			files := make([]fileEntry, 0, len(paths))
 			for _, path := range paths {
-				content, err := os.ReadFile(path) //nolint:gosec // User-provided file paths are intentional.
+				content, err := in.read(path)
 				if err != nil {
-					return fmt.Errorf("read file %s: %w", path, err)
+					return err
 				}
 
-				files = append(files, fileEntry{path: path, content: content})
+				files = append(files, fileEntry{path: in.name(path), content: content})
 			}
 
 			opts := modelOptions{
+				keys:        cfg.Keys,
 				lineNumbers: lineNumbers,
 				search:      search,
+				theme:       themeName,
 				files:       files,
 			}
 
 			m := newModel(&opts)
 
-			p := tea.NewProgram(m)
+			var programOpts []tea.ProgramOption
+
+			// Keys are read from the terminal once stdin is consumed.
+			if slices.Contains(paths, stdinArg) {
+				tty, _, err := tea.OpenTTY()
+				if err != nil {
+					return fmt.Errorf("open terminal for key input: %w", err)
+				}
+
+				defer tty.Close() //nolint:errcheck // Best-effort close.
+
+				programOpts = append(programOpts, tea.WithInput(tty))
+			}
+
+			p := tea.NewProgram(m, programOpts...)
 
 			_, err = p.Run()
 			if err != nil {
@@ -57,7 +90,9 @@ func viewCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&lineNumbers, "line-numbers", "n", true, "show line numbers")
+	cmd.Flags().StringVarP(&themeName, "theme", "t", "charm", "initial theme name")
 	cmd.Flags().StringVarP(&search, "search", "s", "", "initial search term")
+	addStdinNameFlag(cmd, &stdinName)
 
 	return cmd
 }
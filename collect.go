@@ -0,0 +1,394 @@
+package niceyaml
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// DecodeMode controls how [DocumentDecoder] reports decoding errors.
+//
+// The zero value is [DecodeFirstError].
+type DecodeMode int
+
+const (
+	// DecodeFirstError returns the first decoding error, as reported by
+	// go-yaml.
+	DecodeFirstError DecodeMode = iota
+	// DecodeAllErrors walks the whole document and collects every decoding
+	// error, such as type mismatches and unknown fields (with
+	// [yaml.DisallowUnknownField]), as well as errors returned by
+	// [Validator]. See [DocumentDecoder.SetDecodeMode].
+	DecodeAllErrors
+)
+
+// WithDecodeMode is a [SourceOption] that sets the [DecodeMode] of
+// [DocumentDecoder]s created from the [Source].
+// By default, [DecodeFirstError] is used.
+func WithDecodeMode(m DecodeMode) SourceOption {
+	return func(s *Source) {
+		s.decodeMode = m
+	}
+}
+
+// SetDecodeMode sets the [DecodeMode] of the [DocumentDecoder].
+//
+// With [DecodeAllErrors], [DocumentDecoder.Decode] and
+// [DocumentDecoder.Unmarshal] keep going after the first error. Mappings and
+// sequences are decoded entry by entry, so that each failing entry is
+// reported with its own location. [Validator] errors are collected as well,
+// but only if decoding succeeded, since a partially decoded value cannot be
+// validated meaningfully.
+//
+// A single error is returned as is. Several errors are returned as one
+// [*Error] with an error per problem, see [WithErrors]. Errors are resolved
+// against the [Source] of the [Decoder], if any.
+func (dd *DocumentDecoder) SetDecodeMode(m DecodeMode) {
+	dd.decodeMode = m
+}
+
+// unmarshalerTypes are interfaces of types that decode themselves, and are
+// therefore decoded as a whole rather than entry by entry.
+var unmarshalerTypes = []reflect.Type{
+	reflect.TypeFor[yaml.BytesUnmarshaler](),
+	reflect.TypeFor[yaml.BytesUnmarshalerContext](),
+	reflect.TypeFor[yaml.InterfaceUnmarshaler](),
+	reflect.TypeFor[yaml.InterfaceUnmarshalerContext](),
+	reflect.TypeFor[yaml.NodeUnmarshaler](),
+	reflect.TypeFor[yaml.NodeUnmarshalerContext](),
+	reflect.TypeFor[encoding.TextUnmarshaler](),
+}
+
+// errorCollector collects decoding errors of a document.
+type errorCollector struct {
+	dd      *DocumentDecoder
+	anchors []ast.Node
+	errs    []*Error
+}
+
+// decodeAll decodes the document into v, collecting all errors.
+// If validate is true and decoding succeeds, [Validator] errors of v are
+// collected too.
+func (dd *DocumentDecoder) decodeAll(ctx context.Context, v any, validate bool) error {
+	err := dd.decodeNode(ctx, v)
+	if err == nil {
+		if validator, ok := v.(Validator); ok && validate {
+			return dd.collectedError(nil, validator.Validate())
+		}
+
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return err
+	}
+
+	c := &errorCollector{dd: dd, anchors: ast.Filter(ast.AnchorType, dd.doc)}
+	c.collect(ctx, dd.doc.Body, rv.Type().Elem(), err)
+
+	return dd.collectedError(c.errs, nil)
+}
+
+// collectedError combines the given errors and validation error into the
+// error returned in [DecodeAllErrors] mode.
+func (dd *DocumentDecoder) collectedError(errs []*Error, validationErr error) error {
+	if validationErr != nil {
+		var yamlErr *Error
+		if !errors.As(validationErr, &yamlErr) {
+			yamlErr = NewErrorFrom(validationErr)
+		}
+
+		errs = append(errs, flattenErrors(yamlErr)...)
+	}
+
+	var result *Error
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		result = errs[0]
+	default:
+		result = NewError(
+			fmt.Sprintf("found %d errors", len(errs)),
+			WithErrors(errs...),
+		)
+	}
+
+	if dd.source != nil {
+		result.SetOption(WithSource(dd.source))
+	}
+
+	return result
+}
+
+// flattenErrors returns the nested errors of e if it has no location of its
+// own, or e otherwise.
+func flattenErrors(e *Error) []*Error {
+	if e.token != nil || e.path != nil || len(e.errors) == 0 {
+		return []*Error{e}
+	}
+
+	var errs []*Error
+
+	for _, nested := range e.errors {
+		if nested != nil && nested.err != nil {
+			errs = append(errs, flattenErrors(nested)...)
+		}
+	}
+
+	return errs
+}
+
+// collect collects the errors of decoding node into a value of type t.
+//
+// err is the error of decoding node as a whole. If node is a mapping or
+// sequence decoded into a struct, map, slice, or array, its entries are
+// decoded one by one to find all errors. Otherwise, or if no entry fails, err
+// itself is collected.
+func (c *errorCollector) collect(ctx context.Context, node ast.Node, t reflect.Type, err error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	n := len(c.errs)
+
+	if !isUnmarshaler(t) {
+		switch node := unwrapNode(node).(type) {
+		case *ast.MappingNode:
+			c.collectMapping(ctx, node, t)
+		case *ast.MappingValueNode:
+			c.collectMapping(ctx, ast.Mapping(node.GetToken(), false, node), t)
+		case *ast.SequenceNode:
+			c.collectSequence(ctx, node, t)
+		}
+	}
+
+	if len(c.errs) == n {
+		c.add(err, node)
+	}
+}
+
+// collectMapping decodes the entries of node into a struct or map of type t.
+func (c *errorCollector) collectMapping(ctx context.Context, node *ast.MappingNode, t reflect.Type) {
+	var fields map[string]structField
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields = structFields(t)
+	case reflect.Map:
+	default:
+		return
+	}
+
+	for _, mv := range node.Values {
+		if mv.Key == nil || mv.Key.IsMergeKey() {
+			continue
+		}
+
+		entry := ast.Mapping(mv.GetToken(), false, mv)
+
+		err := c.decode(ctx, entry, t)
+		if err == nil {
+			continue
+		}
+
+		var valueType reflect.Type
+
+		if fields == nil {
+			valueType = t.Elem()
+		} else {
+			f, ok := fields[mapKeyString(mv.Key)]
+			if !ok {
+				// Unknown field.
+				c.add(err, mv)
+
+				continue
+			}
+
+			valueType = f.typ
+		}
+
+		valueErr := c.decode(ctx, mv.Value, valueType)
+		if valueErr == nil {
+			// The key itself failed to decode.
+			c.add(err, mv.Key)
+
+			continue
+		}
+
+		// Report the error of the entry, which names the struct field, unless
+		// errors are found within the value.
+		c.collect(ctx, mv.Value, valueType, err)
+	}
+}
+
+// collectSequence decodes the entries of node into a slice or array of type t.
+func (c *errorCollector) collectSequence(ctx context.Context, node *ast.SequenceNode, t reflect.Type) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return
+	}
+
+	for _, value := range node.Values {
+		err := c.decode(ctx, value, t.Elem())
+		if err != nil {
+			c.collect(ctx, value, t.Elem(), err)
+		}
+	}
+}
+
+// decode decodes node into a new value of type t.
+func (c *errorCollector) decode(ctx context.Context, node ast.Node, t reflect.Type) error {
+	// Decoding into a pointer to a nil pointer silently succeeds.
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(""), c.dd.decodeOpts...)
+
+	// Register the anchors before node, so that its aliases and merge keys
+	// resolve as they do when decoding the whole document.
+	start := position.NewFromToken(node.GetToken())
+
+	for _, anchor := range c.anchors {
+		if !positionBefore(position.NewFromToken(anchor.GetToken()), start) {
+			break
+		}
+
+		var discard any
+
+		//nolint:errcheck // Errors of anchors are collected at their own entries.
+		_ = dec.DecodeFromNodeContext(ctx, anchor, &discard)
+	}
+
+	//nolint:wrapcheck // Converted by [errorCollector.add].
+	return dec.DecodeFromNodeContext(ctx, node, reflect.New(t).Interface())
+}
+
+// add converts err to an [*Error] and collects it.
+// Errors without a token, such as errors of [encoding.TextUnmarshaler]s, are
+// located at node.
+func (c *errorCollector) add(err error, node ast.Node) {
+	var yamlErr yaml.Error
+	if errors.As(err, &yamlErr) {
+		c.errs = append(c.errs, NewError(
+			yamlErr.GetMessage(),
+			WithErrorToken(yamlErr.GetToken()),
+		))
+
+		return
+	}
+
+	var opts []ErrorOption
+	if node != nil {
+		opts = append(opts, WithErrorToken(node.GetToken()))
+	}
+
+	c.errs = append(c.errs, NewErrorFrom(err, opts...))
+}
+
+// isUnmarshaler reports whether values of type t decode themselves.
+func isUnmarshaler(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	for _, u := range unmarshalerTypes {
+		if t.Implements(u) || pt.Implements(u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapKeyString returns the string value of a mapping key.
+func mapKeyString(key ast.MapKeyNode) string {
+	if scalar, ok := unwrapNode(key).(ast.ScalarNode); ok {
+		if v := scalar.GetValue(); v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+
+	return key.String()
+}
+
+// structField is a field of a struct type, see [structFields].
+type structField struct {
+	typ reflect.Type
+	// Go field path relative to the struct, e.g. "Meta.Name" for a field of
+	// an inlined struct field.
+	goPath string
+}
+
+// structFields returns the fields of struct type t, keyed by their YAML names.
+// Fields of inlined structs are included.
+//
+// Field names follow the rules of go-yaml: the name from the "yaml" tag, or
+// the "json" tag, or the lowercased field name.
+func structFields(t reflect.Type) map[string]structField {
+	fields := map[string]structField{}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("yaml")
+		if tag == "" {
+			tag = f.Tag.Get("json")
+		}
+
+		if tag == "-" || f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && hasOption(opts, "inline") {
+			for k, v := range structFields(ft) {
+				if _, ok := fields[k]; ok {
+					continue
+				}
+
+				// Fields of embedded structs are promoted.
+				if !f.Anonymous {
+					v.goPath = f.Name + "." + v.goPath
+				}
+
+				fields[k] = v
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		fields[name] = structField{typ: f.Type, goPath: f.Name}
+	}
+
+	return fields
+}
+
+// hasOption reports whether the comma-separated struct tag options include
+// opt.
+func hasOption(opts, opt string) bool {
+	for o := range strings.SplitSeq(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,311 @@
+package niceyaml_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/paths"
+)
+
+type collectContainer struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+	Port  int    `yaml:"port"`
+}
+
+type collectMeta struct {
+	Labels map[string]int `yaml:"labels"`
+}
+
+type collectSpec struct {
+	Meta collectMeta `yaml:",inline"`
+
+	Containers []collectContainer `yaml:"containers"`
+	Timeout    time.Duration      `yaml:"timeout"`
+	Replicas   int                `yaml:"replicas"`
+	Enabled    bool               `yaml:"enabled"`
+}
+
+type collectConfig struct {
+	Spec *collectSpec `yaml:"spec"`
+	Name string       `yaml:"name"`
+}
+
+type validatedConfig struct {
+	Name string `yaml:"name"`
+}
+
+func (c *validatedConfig) Validate() error {
+	return niceyaml.NewError("invalid config", niceyaml.WithErrors(
+		niceyaml.NewError("name is reserved", niceyaml.WithPath(paths.Root().Child("name").Value())),
+		niceyaml.NewError("description is required"),
+	))
+}
+
+// compactMessages returns the compact rendering of err, one line per error.
+func compactMessages(t *testing.T, err error) string {
+	t.Helper()
+
+	var yamlErr *niceyaml.Error
+
+	require.ErrorAs(t, err, &yamlErr)
+	yamlErr.SetOption(niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact))
+
+	return yamlErr.Error()
+}
+
+func TestDocumentDecoder_DecodeAllErrors(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+		  enabled: maybe
+		  timeout: soon
+		  extra: true
+		  labels:
+		    tier: web
+		    zone: 2
+		  containers:
+		    - name: web
+		      port: http
+		    - name: sidecar
+		      image: [envoy]
+		      port: 9901
+	`)
+
+	tcs := map[string]struct {
+		opts []niceyaml.SourceOption
+		want string
+	}{
+		"type mismatches": {
+			want: stringtest.JoinLF(
+				"test.yaml:3:13: error: cannot unmarshal string into Go struct field collectSpec.Replicas of type int",
+				"test.yaml:4:12: error: cannot unmarshal string into Go struct field collectSpec.Enabled of type bool",
+				"test.yaml:5:12: error: time: invalid duration \"soon\"",
+				"test.yaml:8:11: error: cannot unmarshal string into Go value of type int",
+				"test.yaml:12:13: error: cannot unmarshal string into Go struct field collectContainer.Port of type int",
+				"test.yaml:14:14: error: cannot unmarshal []interface {} into Go struct field collectContainer.Image of type string",
+			),
+		},
+		"unknown fields": {
+			opts: []niceyaml.SourceOption{
+				niceyaml.WithDecodeOptions(yaml.DisallowUnknownField()),
+			},
+			want: stringtest.JoinLF(
+				"test.yaml:3:13: error: cannot unmarshal string into Go struct field collectSpec.Replicas of type int",
+				"test.yaml:4:12: error: cannot unmarshal string into Go struct field collectSpec.Enabled of type bool",
+				"test.yaml:5:12: error: time: invalid duration \"soon\"",
+				"test.yaml:6:3: error: unknown field \"extra\"",
+				"test.yaml:8:11: error: cannot unmarshal string into Go value of type int",
+				"test.yaml:12:13: error: cannot unmarshal string into Go struct field collectContainer.Port of type int",
+				"test.yaml:14:14: error: cannot unmarshal []interface {} into Go struct field collectContainer.Image of type string",
+			),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]niceyaml.SourceOption{
+				niceyaml.WithName("test.yaml"),
+				niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors),
+			}, tc.opts...)
+
+			source := niceyaml.NewSourceFromString(input, opts...)
+			dec, err := source.Decoder()
+			require.NoError(t, err)
+
+			for _, doc := range dec.Documents() {
+				var cfg collectConfig
+
+				err := doc.Decode(&cfg)
+				require.Error(t, err)
+				assert.Equal(t, tc.want, compactMessages(t, err))
+			}
+		})
+	}
+}
+
+func TestDocumentDecoder_DecodeAllErrors_Aliases(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Base  map[string]int `yaml:"base"`
+		Copy  map[string]int `yaml:"copy"`
+		Other map[string]int `yaml:"other"`
+		Port  int            `yaml:"port"`
+	}
+
+	input := stringtest.Input(`
+		base: &b {a: 1}
+		copy: *b
+		port: abc
+		other:
+		  <<: *b
+	`)
+
+	source := niceyaml.NewSourceFromString(input,
+		niceyaml.WithName("test.yaml"),
+		niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors),
+	)
+	dec, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range dec.Documents() {
+		var cfg config
+
+		err := doc.Decode(&cfg)
+		require.Error(t, err)
+		assert.Equal(t,
+			"test.yaml:3:7: error: cannot unmarshal string into Go struct field config.Port of type int",
+			compactMessages(t, err),
+		)
+	}
+}
+
+func TestDocumentDecoder_DecodeAllErrors_Render(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		replicas: three
+		enabled: maybe
+	`), niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors))
+
+	dec, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range dec.Documents() {
+		var spec collectSpec
+
+		err := doc.Decode(&spec)
+		require.Error(t, err)
+
+		var yamlErr *niceyaml.Error
+
+		require.ErrorAs(t, err, &yamlErr)
+		require.Len(t, yamlErr.Errors(), 2)
+		assert.Same(t, source, yamlErr.Source())
+
+		yamlErr.SetOption(niceyaml.WithPrinter(newPlainPrinter()))
+
+		want := stringtest.JoinLF(
+			"found 2 errors:",
+			"",
+			"   1  replicas: three",
+			"                ^ cannot unmarshal string into Go struct field collectSpec.Replicas of type int",
+			"   2  enabled: maybe",
+			"               ^ cannot unmarshal string into Go struct field collectSpec.Enabled of type bool",
+		)
+		assert.Equal(t, want, trimLines(yamlErr.Error()))
+	}
+}
+
+func TestDocumentDecoder_SetDecodeMode(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+		  enabled: maybe
+	`)
+
+	t.Run("first error by default", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(input)
+		dec, err := source.Decoder()
+		require.NoError(t, err)
+
+		for _, doc := range dec.Documents() {
+			var cfg collectConfig
+
+			err := doc.Decode(&cfg)
+
+			var yamlErr *niceyaml.Error
+
+			require.ErrorAs(t, err, &yamlErr)
+			assert.Empty(t, yamlErr.Errors())
+
+			doc.SetDecodeMode(niceyaml.DecodeAllErrors)
+
+			err = doc.Decode(&cfg)
+
+			require.ErrorAs(t, err, &yamlErr)
+			assert.Len(t, yamlErr.Errors(), 2)
+		}
+	})
+
+	t.Run("single error is returned as is", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("replicas: three\n", niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors))
+		dec, err := source.Decoder()
+		require.NoError(t, err)
+
+		for _, doc := range dec.Documents() {
+			var spec collectSpec
+
+			err := doc.Decode(&spec)
+
+			var yamlErr *niceyaml.Error
+
+			require.ErrorAs(t, err, &yamlErr)
+			assert.Empty(t, yamlErr.Errors())
+			assert.Equal(t, "1:11: error: cannot unmarshal string into Go struct field collectSpec.Replicas of type int",
+				compactMessages(t, err))
+		}
+	})
+
+	t.Run("valid document", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("replicas: 3\n", niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors))
+		dec, err := source.Decoder()
+		require.NoError(t, err)
+
+		for _, doc := range dec.Documents() {
+			var spec collectSpec
+
+			require.NoError(t, doc.Decode(&spec))
+			assert.Equal(t, 3, spec.Replicas)
+		}
+	})
+}
+
+func TestDocumentDecoder_UnmarshalAllErrors_Validator(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("name: default\n",
+		niceyaml.WithName("test.yaml"),
+		niceyaml.WithDecodeMode(niceyaml.DecodeAllErrors),
+	)
+
+	dec, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range dec.Documents() {
+		var cfg validatedConfig
+
+		err := doc.Unmarshal(&cfg)
+		require.Error(t, err)
+
+		want := stringtest.JoinLF(
+			"test.yaml:1:7: error: name is reserved [$.name]",
+			"test.yaml: error: description is required",
+		)
+		assert.Equal(t, want, compactMessages(t, err))
+
+		// Decode skips validation.
+		require.NoError(t, doc.Decode(&cfg))
+	}
+}
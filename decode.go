@@ -63,13 +63,21 @@ type Decoder struct {
 
 // NewDecoder creates a new [*Decoder] for the given [*Source].
 //
-// Returns an error if the source cannot be parsed.
+// Returns an error if the source cannot be parsed, or if it has duplicate keys
+// and was created with [WithDuplicateKeyCheck].
 func NewDecoder(s *Source) (*Decoder, error) {
 	f, err := s.File()
 	if err != nil {
 		return nil, err
 	}
 
+	if s.checkDuplicateKeys {
+		err = s.CheckDuplicateKeys(s.duplicateKeyOpts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Decoder{source: s, file: f}, nil
 }
 
@@ -116,6 +124,8 @@ func (d *Decoder) Documents() iter.Seq2[int, *DocumentDecoder] {
 				tokens:     tks,
 				filePath:   filePath,
 				decodeOpts: d.source.decodeOpts,
+				source:     d.source,
+				decodeMode: d.source.decodeMode,
 			}
 
 			if !yield(i, dd) {
@@ -156,6 +166,8 @@ type DocumentDecoder struct {
 	tokens     token.Tokens
 	decodeOpts []yaml.DecodeOption
 	index      int
+	source     *Source
+	decodeMode DecodeMode
 }
 
 // NewDocumentDecoder creates a new [*DocumentDecoder] for the given
@@ -292,7 +304,14 @@ func (dd *DocumentDecoder) Decode(v any) error {
 
 // DecodeContext decodes the document into v with [context.Context].
 // YAML decoding errors are converted to [Error] with source annotations.
+//
+// With [DecodeAllErrors], all decoding errors are collected, see
+// [DocumentDecoder.SetDecodeMode].
 func (dd *DocumentDecoder) DecodeContext(ctx context.Context, v any) error {
+	if dd.decodeMode == DecodeAllErrors {
+		return dd.decodeAll(ctx, v, false)
+	}
+
 	return dd.decodeNode(ctx, v)
 }
 
@@ -312,6 +331,9 @@ func (dd *DocumentDecoder) Unmarshal(v any) error {
 //
 // If v implements [SchemaValidator], ValidateSchema is called before decoding.
 // If v implements [Validator], Validate is called after successful decoding.
+//
+// With [DecodeAllErrors], decoding and [Validator] errors are collected, see
+// [DocumentDecoder.SetDecodeMode].
 func (dd *DocumentDecoder) UnmarshalContext(ctx context.Context, v any) error {
 	// Validate if type provides schema validation.
 	if sv, ok := v.(SchemaValidator); ok {
@@ -321,6 +343,10 @@ func (dd *DocumentDecoder) UnmarshalContext(ctx context.Context, v any) error {
 		}
 	}
 
+	if dd.decodeMode == DecodeAllErrors {
+		return dd.decodeAll(ctx, v, true)
+	}
+
 	// Decode to typed struct.
 	err := dd.DecodeContext(ctx, v)
 	if err != nil {
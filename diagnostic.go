@@ -0,0 +1,316 @@
+package niceyaml
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"charm.land/lipgloss/v2"
+
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+// ErrOverlappingSuggestions indicates that suggestions to apply overlap.
+var ErrOverlappingSuggestions = errors.New("overlapping suggestions")
+
+// Severity is the severity of an [Error].
+//
+// The zero value is [SeverityError].
+type Severity int
+
+const (
+	// SeverityError indicates a problem that must be fixed.
+	SeverityError Severity = iota
+	// SeverityWarning indicates a likely problem.
+	SeverityWarning
+	// SeverityInfo indicates information about the document.
+	SeverityInfo
+	// SeverityHint indicates a suggestion for improvement.
+	SeverityHint
+)
+
+// String returns the lowercase name of the severity, e.g. "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	case SeverityHint:
+		return "hint"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Style returns the [style.Style] used to render the severity:
+// [style.TextError], [style.TextWarn], [style.TextAccent], or
+// [style.TextSubtle].
+func (s Severity) Style() style.Style {
+	switch s {
+	case SeverityWarning:
+		return style.TextWarn
+	case SeverityInfo:
+		return style.TextAccent
+	case SeverityHint:
+		return style.TextSubtle
+	default:
+		return style.TextError
+	}
+}
+
+// Suggestion is a machine-applicable fix for an [Error]: the text in Range
+// is replaced by Replacement.
+//
+// An empty Range inserts Replacement at its start, and an empty Replacement
+// removes the text in Range.
+//
+// Apply suggestions with [Source.ApplySuggestions].
+type Suggestion struct {
+	// Message optionally describes the fix.
+	Message string
+	// Replacement is the text replacing Range.
+	Replacement string
+	// Range is the range of the source to replace.
+	Range position.Range
+}
+
+// String describes the suggestion, e.g. `use a number: replace 3:13-3:18
+// with "3"`.
+func (s Suggestion) String() string {
+	var action string
+
+	switch {
+	case s.Range.Start == s.Range.End:
+		action = fmt.Sprintf("insert %q at %s", s.Replacement, s.Range.Start.String())
+	case s.Replacement == "":
+		action = "remove " + s.Range.String()
+	default:
+		action = fmt.Sprintf("replace %s with %q", s.Range.String(), s.Replacement)
+	}
+
+	if s.Message == "" {
+		return action
+	}
+
+	return s.Message + ": " + action
+}
+
+// WithSeverity is an [ErrorOption] that sets the [Severity] of the error.
+// By default, [SeverityError] is used.
+func WithSeverity(s Severity) ErrorOption {
+	return func(e *Error) {
+		e.severity = s
+	}
+}
+
+// WithCode is an [ErrorOption] that sets a stable code identifying the kind
+// of error, e.g. "NY0012".
+func WithCode(code string) ErrorOption {
+	return func(e *Error) {
+		e.code = code
+	}
+}
+
+// WithHelp is an [ErrorOption] that sets a help message, explaining how to
+// resolve the error.
+func WithHelp(help string) ErrorOption {
+	return func(e *Error) {
+		e.help = help
+	}
+}
+
+// WithDocsURL is an [ErrorOption] that sets the URL of documentation about
+// the error.
+func WithDocsURL(url string) ErrorOption {
+	return func(e *Error) {
+		e.docsURL = url
+	}
+}
+
+// WithSuggestions is an [ErrorOption] that adds [Suggestion]s to fix the
+// error.
+func WithSuggestions(suggestions ...Suggestion) ErrorOption {
+	return func(e *Error) {
+		e.suggestions = append(e.suggestions, suggestions...)
+	}
+}
+
+// Severity returns the [Severity] of the [Error].
+func (e *Error) Severity() Severity {
+	return e.severity
+}
+
+// Code returns the code of the [Error], or an empty string if none was set.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Help returns the help message of the [Error], or an empty string if none
+// was set.
+func (e *Error) Help() string {
+	return e.help
+}
+
+// DocsURL returns the documentation URL of the [Error], or an empty string if
+// none was set.
+func (e *Error) DocsURL() string {
+	return e.docsURL
+}
+
+// Suggestions returns the [Suggestion]s of the [Error].
+func (e *Error) Suggestions() []Suggestion {
+	return e.suggestions
+}
+
+// diagnosticPrefix returns the severity and code shown before the error
+// message, e.g. "warning[NY0012]: ", rendered with styles if non-nil.
+//
+// The prefix is omitted for errors with [SeverityError] and no code, so that
+// plain errors render as before.
+func (e *Error) diagnosticPrefix(styles StyleGetter) string {
+	if e.severity == SeverityError && e.code == "" {
+		return ""
+	}
+
+	prefix := e.severity.String()
+	if e.code != "" {
+		prefix += "[" + e.code + "]"
+	}
+
+	return renderStyled(styles, e.severity.Style(), prefix) + ": "
+}
+
+// diagnosticFooter returns the help, documentation URL, and suggestions shown
+// after the error, one per line, rendered with styles if non-nil.
+// Returns an empty string if there are none.
+func (e *Error) diagnosticFooter(styles StyleGetter) string {
+	var lines []string
+
+	if e.help != "" {
+		lines = append(lines, renderStyled(styles, style.TextAccent, "help")+": "+e.help)
+	}
+
+	if e.docsURL != "" {
+		lines = append(lines, renderStyled(styles, style.TextSubtle, "docs")+": "+e.docsURL)
+	}
+
+	for _, s := range e.suggestions {
+		lines = append(lines, renderStyled(styles, style.TextOK, "suggestion")+": "+s.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// styles returns the [StyleGetter] of the error's printer, or nil if the
+// printer does not provide styles.
+func (e *Error) styles() StyleGetter {
+	if sg, ok := e.getPrinter().(StyleGetter); ok {
+		return sg
+	}
+
+	return nil
+}
+
+// renderStyled renders s with the given style from styles, or returns s
+// unchanged if styles is nil.
+func renderStyled(styles StyleGetter, st style.Style, s string) string {
+	if styles == nil {
+		return s
+	}
+
+	ls := styles.Style(st)
+	if ls == nil {
+		return s
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(ls.GetForeground()).
+		Bold(ls.GetBold()).
+		Render(s)
+}
+
+// ApplySuggestions returns a new [*Source] with the given [Suggestion]s
+// applied to the content of s.
+//
+// The result has the same name, file path, and options as s.
+// Returns [ErrOverlappingSuggestions] if any suggestions overlap, or an error
+// if a suggestion is out of range.
+func (s *Source) ApplySuggestions(suggestions ...Suggestion) (*Source, error) {
+	content := s.Content()
+
+	// Byte offsets of line starts.
+	lineStarts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	offset := func(pos position.Position) (int, error) {
+		if pos.Line < 0 || pos.Line >= len(lineStarts) || pos.Col < 0 {
+			return 0, fmt.Errorf("position %s: out of range", pos.String())
+		}
+
+		i := lineStarts[pos.Line]
+		for col := 0; col < pos.Col; col++ {
+			if i >= len(content) || content[i] == '\n' {
+				return 0, fmt.Errorf("position %s: out of range", pos.String())
+			}
+
+			_, size := utf8.DecodeRuneInString(content[i:])
+			i += size
+		}
+
+		return i, nil
+	}
+
+	type edit struct {
+		text       string
+		start, end int
+	}
+
+	edits := make([]edit, 0, len(suggestions))
+
+	for _, sg := range suggestions {
+		start, err := offset(sg.Range.Start)
+		if err != nil {
+			return nil, fmt.Errorf("apply suggestion: %w", err)
+		}
+
+		end, err := offset(sg.Range.End)
+		if err != nil {
+			return nil, fmt.Errorf("apply suggestion: %w", err)
+		}
+
+		edits = append(edits, edit{text: sg.Replacement, start: start, end: max(start, end)})
+	}
+
+	slices.SortStableFunc(edits, func(a, b edit) int { return a.start - b.start })
+
+	var sb strings.Builder
+
+	last := 0
+
+	for i, ed := range edits {
+		if ed.start < last || i > 0 && ed.start == edits[i-1].start && ed.start == ed.end {
+			return nil, ErrOverlappingSuggestions
+		}
+
+		sb.WriteString(content[last:ed.start])
+		sb.WriteString(ed.text)
+		last = ed.end
+	}
+
+	sb.WriteString(content[last:])
+
+	applied := NewSourceFromString(sb.String())
+	s.copyOptions(applied)
+
+	return applied, nil
+}
@@ -0,0 +1,258 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[niceyaml.Severity]struct {
+		name  string
+		style style.Style
+	}{
+		niceyaml.SeverityError:   {name: "error", style: style.TextError},
+		niceyaml.SeverityWarning: {name: "warning", style: style.TextWarn},
+		niceyaml.SeverityInfo:    {name: "info", style: style.TextAccent},
+		niceyaml.SeverityHint:    {name: "hint", style: style.TextSubtle},
+	}
+
+	for sev, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.name, sev.String())
+			assert.Equal(t, tc.style, sev.Style())
+		})
+	}
+
+	assert.Equal(t, "Severity(9)", niceyaml.Severity(9).String())
+}
+
+func TestSuggestion_String(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		want       string
+		suggestion niceyaml.Suggestion
+	}{
+		"replace": {
+			suggestion: niceyaml.Suggestion{
+				Message:     "use a number",
+				Range:       labelRange(2, 12, 2, 17),
+				Replacement: "3",
+			},
+			want: `use a number: replace 3:13-3:18 with "3"`,
+		},
+		"insert": {
+			suggestion: niceyaml.Suggestion{Range: labelRange(0, 4, 0, 4), Replacement: " "},
+			want:       `insert " " at 1:5`,
+		},
+		"remove": {
+			suggestion: niceyaml.Suggestion{Range: labelRange(0, 0, 0, 4)},
+			want:       "remove 1:1-1:5",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tc.suggestion.String())
+		})
+	}
+}
+
+func TestError_Diagnostics(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+	`)
+
+	suggestion := niceyaml.Suggestion{
+		Message:     "use a number",
+		Range:       labelRange(2, 12, 2, 17),
+		Replacement: "3",
+	}
+
+	tcs := map[string]struct {
+		opts []niceyaml.ErrorOption
+		want string
+	}{
+		"default severity without code": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithPath(paths.Root().Child("spec", "replicas").Value()),
+			},
+			want: stringtest.JoinLF(
+				"[3:13] invalid replicas:",
+				"",
+				"   1  name: app",
+				"   2  spec:",
+				"   3    replicas: three",
+			),
+		},
+		"all diagnostics": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithPath(paths.Root().Child("spec", "replicas").Value()),
+				niceyaml.WithSeverity(niceyaml.SeverityWarning),
+				niceyaml.WithCode("NY0012"),
+				niceyaml.WithHelp("replicas must be an integer"),
+				niceyaml.WithDocsURL("https://example.com/NY0012"),
+				niceyaml.WithSuggestions(suggestion),
+			},
+			want: stringtest.JoinLF(
+				"warning[NY0012]: [3:13] invalid replicas:",
+				"",
+				"   1  name: app",
+				"   2  spec:",
+				"   3    replicas: three",
+				"",
+				"help: replicas must be an integer",
+				"docs: https://example.com/NY0012",
+				`suggestion: use a number: replace 3:13-3:18 with "3"`,
+			),
+		},
+		"severity without code": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithPath(paths.Root().Child("name").Key()),
+				niceyaml.WithSourceLines(0),
+				niceyaml.WithSeverity(niceyaml.SeverityHint),
+			},
+			want: stringtest.JoinLF(
+				"hint: [1:1] invalid replicas:",
+				"",
+				"   1  name: app",
+			),
+		},
+		"unresolved path": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithPath(paths.Root().Child("missing").Value()),
+				niceyaml.WithCode("NY0001"),
+				niceyaml.WithHelp("check the key"),
+			},
+			want: stringtest.JoinLF(
+				"error[NY0001]: at $.missing: invalid replicas",
+				"help: check the key",
+			),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]niceyaml.ErrorOption{
+				niceyaml.WithSource(niceyaml.NewSourceFromString(input)),
+				niceyaml.WithPrinter(newPlainPrinter()),
+			}, tc.opts...)
+
+			err := niceyaml.NewError("invalid replicas", opts...)
+
+			assert.Equal(t, tc.want, trimLines(err.Error()))
+		})
+	}
+}
+
+func TestError_Diagnostics_Accessors(t *testing.T) {
+	t.Parallel()
+
+	suggestion := niceyaml.Suggestion{Range: labelRange(0, 0, 0, 4), Replacement: "key"}
+
+	err := niceyaml.NewError("invalid",
+		niceyaml.WithSeverity(niceyaml.SeverityInfo),
+		niceyaml.WithCode("NY0002"),
+		niceyaml.WithHelp("help"),
+		niceyaml.WithDocsURL("https://example.com"),
+		niceyaml.WithSuggestions(suggestion),
+	)
+
+	assert.Equal(t, niceyaml.SeverityInfo, err.Severity())
+	assert.Equal(t, "NY0002", err.Code())
+	assert.Equal(t, "help", err.Help())
+	assert.Equal(t, "https://example.com", err.DocsURL())
+	assert.Equal(t, []niceyaml.Suggestion{suggestion}, err.Suggestions())
+
+	plain := niceyaml.NewError("invalid")
+	assert.Equal(t, niceyaml.SeverityError, plain.Severity())
+	assert.Empty(t, plain.Code())
+	assert.Empty(t, plain.Suggestions())
+}
+
+func TestSource_ApplySuggestions(t *testing.T) {
+	t.Parallel()
+
+	input := "name: app\nspec:\n  replicas: three\n  image: 日本\n"
+
+	tcs := map[string]struct {
+		err         error
+		want        string
+		suggestions []niceyaml.Suggestion
+		wantErr     bool
+	}{
+		"none": {
+			want: "name: app\nspec:\n  replicas: three\n  image: 日本",
+		},
+		"replace, insert, and remove": {
+			suggestions: []niceyaml.Suggestion{
+				{Range: labelRange(2, 12, 2, 17), Replacement: "3"},
+				{Range: labelRange(0, 6, 0, 6), Replacement: "my-"},
+				{Range: labelRange(3, 10, 3, 11)},
+			},
+			want: "name: my-app\nspec:\n  replicas: 3\n  image: 日",
+		},
+		"across lines": {
+			suggestions: []niceyaml.Suggestion{
+				{Range: labelRange(0, 9, 1, 5), Replacement: ""},
+			},
+			want: "name: app\n  replicas: three\n  image: 日本",
+		},
+		"overlapping": {
+			suggestions: []niceyaml.Suggestion{
+				{Range: labelRange(2, 12, 2, 17), Replacement: "3"},
+				{Range: labelRange(2, 14, 2, 15), Replacement: "x"},
+			},
+			err:     niceyaml.ErrOverlappingSuggestions,
+			wantErr: true,
+		},
+		"out of range": {
+			suggestions: []niceyaml.Suggestion{
+				{Range: labelRange(1, 9, 1, 10), Replacement: "x"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(input, niceyaml.WithName("test.yaml"))
+
+			got, err := source.ApplySuggestions(tc.suggestions...)
+			if tc.wantErr {
+				require.Error(t, err)
+
+				if tc.err != nil {
+					require.ErrorIs(t, err, tc.err)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.Content())
+			assert.Equal(t, "test.yaml", got.Name())
+		})
+	}
+}
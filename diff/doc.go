@@ -18,6 +18,10 @@
 //
 // This is particularly important when comparing large YAML documents.
 //
+// [Patience] matches lines that occur once in both sequences first, such as
+// distinct mapping keys, and falls back to [Hirschberg] between them. Its
+// edit sequences may be longer, but are often easier to read.
+//
 // # Usage
 //
 // Create a [Hirschberg] instance once and reuse it for multiple comparisons.
@@ -0,0 +1,197 @@
+package diff
+
+// Patience implements [Algorithm] using patience diff.
+//
+// Lines that occur exactly once in both sequences are matched first, in the
+// longest order-preserving run, and the ranges between them are diffed
+// recursively. Ranges without unique lines fall back to [Hirschberg].
+//
+// Anchoring on unique lines, such as distinct mapping keys, keeps changes to
+// repeated lines, such as closing values or blank lines, from being aligned
+// with unrelated lines, which often gives more readable diffs than a minimal
+// edit sequence.
+//
+// Time complexity: O(n log n) for the unique lines, plus the [Hirschberg]
+// fallback for ranges without them.
+//
+// Create instances with [NewPatience].
+type Patience struct {
+	fallback *Hirschberg
+
+	// Accumulated diff operations.
+	ops []Op
+}
+
+// NewPatience creates a new [*Patience].
+func NewPatience() *Patience {
+	return &Patience{fallback: NewHirschberg()}
+}
+
+// Init prepares buffers for inputs of the given sizes.
+//
+// Calling Init is optional but improves performance when the input sizes are
+// known in advance.
+func (p *Patience) Init(beforeLen, afterLen int) {
+	p.fallback.Init(beforeLen, afterLen)
+
+	opsCapacity := beforeLen + afterLen
+	if opsCapacity > cap(p.ops) {
+		p.ops = make([]Op, 0, opsCapacity)
+	}
+}
+
+// Diff returns operations transforming before into after.
+//
+// The operations follow the same conventions as [Hirschberg.Diff].
+func (p *Patience) Diff(before, after []string) []Op {
+	p.ops = p.ops[:0]
+
+	p.recurse(before, after, 0, len(before), 0, len(after))
+
+	return p.ops
+}
+
+// recurse diffs before[bStart:bEnd] and after[aStart:aEnd].
+func (p *Patience) recurse(before, after []string, bStart, bEnd, aStart, aEnd int) {
+	// Common prefix.
+	for bStart < bEnd && aStart < aEnd && before[bStart] == after[aStart] {
+		p.ops = append(p.ops, Op{Kind: OpEqual, Index: aStart})
+		bStart++
+		aStart++
+	}
+
+	// Common suffix, appended after the middle.
+	suffix := 0
+	for bStart < bEnd-suffix && aStart < aEnd-suffix && before[bEnd-suffix-1] == after[aEnd-suffix-1] {
+		suffix++
+	}
+
+	bEnd -= suffix
+	aEnd -= suffix
+
+	anchors := uniqueAnchors(before, after, bStart, bEnd, aStart, aEnd)
+	if len(anchors) == 0 {
+		p.diffFallback(before, after, bStart, bEnd, aStart, aEnd)
+	} else {
+		for _, a := range anchors {
+			p.recurse(before, after, bStart, a.before, aStart, a.after)
+			p.ops = append(p.ops, Op{Kind: OpEqual, Index: a.after})
+			bStart, aStart = a.before+1, a.after+1
+		}
+
+		p.recurse(before, after, bStart, bEnd, aStart, aEnd)
+	}
+
+	for j := aEnd; j < aEnd+suffix; j++ {
+		p.ops = append(p.ops, Op{Kind: OpEqual, Index: j})
+	}
+}
+
+// diffFallback diffs before[bStart:bEnd] and after[aStart:aEnd] with
+// [Hirschberg].
+func (p *Patience) diffFallback(before, after []string, bStart, bEnd, aStart, aEnd int) {
+	if bStart == bEnd && aStart == aEnd {
+		return
+	}
+
+	for _, op := range p.fallback.Diff(before[bStart:bEnd], after[aStart:aEnd]) {
+		if op.Kind == OpDelete {
+			op.Index += bStart
+		} else {
+			op.Index += aStart
+		}
+
+		p.ops = append(p.ops, op)
+	}
+}
+
+// anchor is a pair of matching lines.
+type anchor struct {
+	before, after int
+}
+
+// uniqueAnchors returns the longest run of lines that occur exactly once in
+// both before[bStart:bEnd] and after[aStart:aEnd], in increasing order on
+// both sides.
+func uniqueAnchors(before, after []string, bStart, bEnd, aStart, aEnd int) []anchor {
+	type occurrence struct {
+		after       int // Index of the last occurrence in after.
+		beforeCount int
+		afterCount  int
+	}
+
+	seen := make(map[string]*occurrence)
+
+	for i := bStart; i < bEnd; i++ {
+		o, ok := seen[before[i]]
+		if !ok {
+			o = &occurrence{}
+			seen[before[i]] = o
+		}
+
+		o.beforeCount++
+	}
+
+	for j := aStart; j < aEnd; j++ {
+		if o, ok := seen[after[j]]; ok {
+			o.after = j
+			o.afterCount++
+		}
+	}
+
+	// Candidates in before order.
+	var candidates []anchor
+
+	for i := bStart; i < bEnd; i++ {
+		if o := seen[before[i]]; o.beforeCount == 1 && o.afterCount == 1 {
+			candidates = append(candidates, anchor{before: i, after: o.after})
+		}
+	}
+
+	return longestIncreasing(candidates)
+}
+
+// longestIncreasing returns the longest subsequence of candidates, which are
+// sorted by before index, that is increasing by after index, using patience
+// sorting.
+func longestIncreasing(candidates []anchor) []anchor {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// tops[k] is the index of the candidate on top of pile k, and prev links
+	// each candidate to the top of the previous pile when it was placed.
+	var tops []int
+
+	prev := make([]int, len(candidates))
+
+	for i, c := range candidates {
+		lo, hi := 0, len(tops)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if candidates[tops[mid]].after < c.after {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+
+		prev[i] = -1
+		if lo > 0 {
+			prev[i] = tops[lo-1]
+		}
+
+		if lo == len(tops) {
+			tops = append(tops, i)
+		} else {
+			tops[lo] = i
+		}
+	}
+
+	result := make([]anchor, len(tops))
+	for k, i := len(tops)-1, tops[len(tops)-1]; k >= 0; k, i = k-1, prev[i] {
+		result[k] = candidates[i]
+	}
+
+	return result
+}
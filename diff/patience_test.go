@@ -0,0 +1,145 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.jacobcolvin.com/niceyaml/diff"
+)
+
+func TestPatience_Diff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		before []string
+		after  []string
+		want   []diff.Op
+	}{
+		"empty_both": {
+			before: []string{},
+			after:  []string{},
+			want:   nil,
+		},
+		"empty_before": {
+			before: []string{},
+			after:  []string{"a", "b"},
+			want: []diff.Op{
+				{Kind: diff.OpInsert, Index: 0},
+				{Kind: diff.OpInsert, Index: 1},
+			},
+		},
+		"empty_after": {
+			before: []string{"a", "b"},
+			after:  []string{},
+			want: []diff.Op{
+				{Kind: diff.OpDelete, Index: 0},
+				{Kind: diff.OpDelete, Index: 1},
+			},
+		},
+		"identical": {
+			before: []string{"a", "b", "c"},
+			after:  []string{"a", "b", "c"},
+			want: []diff.Op{
+				{Kind: diff.OpEqual, Index: 0},
+				{Kind: diff.OpEqual, Index: 1},
+				{Kind: diff.OpEqual, Index: 2},
+			},
+		},
+		"replace_middle": {
+			before: []string{"a", "b", "c"},
+			after:  []string{"a", "x", "c"},
+			want: []diff.Op{
+				{Kind: diff.OpEqual, Index: 0},
+				{Kind: diff.OpDelete, Index: 1},
+				{Kind: diff.OpInsert, Index: 1},
+				{Kind: diff.OpEqual, Index: 2},
+			},
+		},
+		"anchored_on_unique_lines": {
+			// The unique "b" line is matched before the repeated "}" lines.
+			before: []string{"a", "}", "b", "}"},
+			after:  []string{"b", "}", "c", "}"},
+			want: []diff.Op{
+				{Kind: diff.OpDelete, Index: 0},
+				{Kind: diff.OpDelete, Index: 1},
+				{Kind: diff.OpEqual, Index: 0},
+				{Kind: diff.OpInsert, Index: 1},
+				{Kind: diff.OpInsert, Index: 2},
+				{Kind: diff.OpEqual, Index: 3},
+			},
+		},
+		"moved_lines": {
+			before: []string{"a", "b", "c"},
+			after:  []string{"c", "a", "b"},
+			want: []diff.Op{
+				{Kind: diff.OpInsert, Index: 0},
+				{Kind: diff.OpEqual, Index: 1},
+				{Kind: diff.OpEqual, Index: 2},
+				{Kind: diff.OpDelete, Index: 2},
+			},
+		},
+		"no_unique_lines": {
+			before: []string{"x", "a", "a", "y"},
+			after:  []string{"x", "b", "a", "a", "y"},
+			want: []diff.Op{
+				{Kind: diff.OpEqual, Index: 0},
+				{Kind: diff.OpInsert, Index: 1},
+				{Kind: diff.OpEqual, Index: 2},
+				{Kind: diff.OpEqual, Index: 3},
+				{Kind: diff.OpEqual, Index: 4},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := diff.NewPatience()
+			p.Init(len(tc.before), len(tc.after))
+
+			got := p.Diff(tc.before, tc.after)
+			if len(tc.want) == 0 {
+				assert.Empty(t, got)
+
+				return
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPatience_Reconstructs(t *testing.T) {
+	t.Parallel()
+
+	p := diff.NewPatience()
+
+	inputs := [][2][]string{
+		{{"a", "b", "a", "c", "b"}, {"b", "a", "c", "a", "d"}},
+		{{"key: 1", "", "other: 2", ""}, {"", "key: 2", "", "other: 2"}},
+		{{"a", "x", "b", "x", "c"}, {"c", "x", "b", "x", "a"}},
+	}
+
+	for _, in := range inputs {
+		before, after := in[0], in[1]
+
+		var gotBefore, gotAfter []string
+
+		for _, op := range p.Diff(before, after) {
+			switch op.Kind {
+			case diff.OpEqual:
+				gotBefore = append(gotBefore, after[op.Index])
+				gotAfter = append(gotAfter, after[op.Index])
+			case diff.OpDelete:
+				gotBefore = append(gotBefore, before[op.Index])
+			case diff.OpInsert:
+				gotAfter = append(gotAfter, after[op.Index])
+			}
+		}
+
+		assert.Equal(t, before, gotBefore)
+		assert.Equal(t, after, gotAfter)
+	}
+}
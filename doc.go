@@ -133,4 +133,35 @@
 //	for _, rng := range finder.Find("search term") {
 //		source.AddOverlay(style.GenericInserted, rng)
 //	}
+//
+// # Outline
+//
+// [Source.Outline] returns an [outline.Outline]: a tree of mapping keys and
+// sequence items per document, each with its line span, path, and value kind.
+// It backs structural features such as folding and breadcrumbs:
+//
+//	o, err := source.Outline()
+//	if err != nil {
+//		return err
+//	}
+//	for _, n := range o.Enclosing(lineIdx) {
+//		fmt.Print(n.Label(), " > ")
+//	}
+//
+// # Anchors and Aliases
+//
+// [Source.Anchors] lists each anchor with the aliases that reference it.
+// [Source.ResolveAlias] finds the anchor for an alias at a position, and
+// [Source.AliasesOf] lists all uses of an anchor. [Source.AnnotateMerges]
+// annotates merge keys with the entries they merge.
+//
+// [Source.Expand] derives a [Source] with aliases and merge keys resolved.
+// Each line keeps its provenance, so positions in the expanded view map back
+// to the original with [Source.Origin] and [Source.OriginRange]:
+//
+//	expanded, err := source.Expand()
+//	if err != nil {
+//		return err
+//	}
+//	orig, ok := expanded.Origin(pos)
 package niceyaml
@@ -0,0 +1,213 @@
+package niceyaml
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// ErrDuplicateKey indicates that a mapping defines a key more than once.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicateKeyOption configures [Source.CheckDuplicateKeys].
+//
+// Available options:
+//   - [WithMergeOverrides]
+type DuplicateKeyOption func(*duplicateKeyChecker)
+
+// WithMergeOverrides is a [DuplicateKeyOption] that also reports keys defined
+// more than once after merge keys ("<<") are expanded: keys that override a
+// key merged from an anchored mapping, and keys merged from several mappings.
+//
+// Overriding merged keys is valid YAML, so these are not reported by default.
+func WithMergeOverrides() DuplicateKeyOption {
+	return func(c *duplicateKeyChecker) {
+		c.mergeOverrides = true
+	}
+}
+
+// WithDuplicateKeyCheck is a [SourceOption] that checks the [Source] for
+// duplicate keys with [Source.CheckDuplicateKeys] before decoding, see
+// [NewDecoder].
+//
+// go-yaml rejects duplicate keys while parsing, with an error at the
+// duplicate only. With this option, the [Source] is parsed with
+// [parser.AllowDuplicateMapKey], so that duplicates are reported with both
+// locations instead.
+func WithDuplicateKeyCheck(opts ...DuplicateKeyOption) SourceOption {
+	return func(s *Source) {
+		s.duplicateKeyOpts = append([]DuplicateKeyOption{}, opts...)
+		s.checkDuplicateKeys = true
+	}
+}
+
+// CheckDuplicateKeys returns an error for the keys that are defined more than
+// once in a mapping of s, or nil if there are none. Merge keys are not
+// duplicates of each other; see [WithMergeOverrides] for keys merged with
+// them.
+//
+// Each duplicate is reported as an [*Error] wrapping [ErrDuplicateKey], with
+// a primary [Label] at the duplicate key, and a secondary [Label] at its first
+// definition. A single duplicate is returned as is. Several duplicates are
+// returned as one [*Error] with an error per duplicate, see [WithErrors].
+//
+// The [Source] is parsed with [parser.AllowDuplicateMapKey], regardless of
+// its parser options. Returns an error if s cannot be parsed.
+func (s *Source) CheckDuplicateKeys(opts ...DuplicateKeyOption) error {
+	file, err := s.parse(parser.AllowDuplicateMapKey())
+	if err != nil {
+		return s.WrapError(err)
+	}
+
+	c := &duplicateKeyChecker{source: s}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, doc := range file.Docs {
+		c.anchors = ast.Filter(ast.AnchorType, doc)
+
+		for _, n := range ast.Filter(ast.MappingType, doc) {
+			if m, ok := n.(*ast.MappingNode); ok {
+				c.check(m)
+			}
+		}
+	}
+
+	slices.SortStableFunc(c.errs, func(a, b *Error) int {
+		pa, pb := a.labels[0].Range.Start, b.labels[0].Range.Start
+
+		return cmp.Or(cmp.Compare(pa.Line, pb.Line), cmp.Compare(pa.Col, pb.Col))
+	})
+
+	switch len(c.errs) {
+	case 0:
+		return nil
+	case 1:
+		return s.WrapError(c.errs[0])
+	default:
+		return s.WrapError(NewError(
+			fmt.Sprintf("found %d duplicate keys", len(c.errs)),
+			WithErrors(c.errs...),
+		))
+	}
+}
+
+// duplicateKeyChecker collects the duplicate keys of a [Source].
+type duplicateKeyChecker struct {
+	source         *Source
+	anchors        []ast.Node
+	errs           []*Error
+	mergeOverrides bool
+}
+
+// keyDefinition is the definition of a mapping key.
+type keyDefinition struct {
+	token  *token.Token
+	text   string
+	merged bool
+}
+
+// check reports the duplicate keys of m.
+func (c *duplicateKeyChecker) check(m *ast.MappingNode) {
+	defined := map[string]keyDefinition{}
+
+	for _, mv := range m.Values {
+		if _, ok := mv.Key.(*ast.MergeKeyNode); ok || mv.Key == nil {
+			continue
+		}
+
+		key := keyID(mv.Key)
+		def := keyDefinition{token: mv.Key.GetToken(), text: keyText(mv.Key)}
+
+		if first, ok := defined[key]; ok {
+			c.report(first, def)
+
+			continue
+		}
+
+		defined[key] = def
+	}
+
+	if !c.mergeOverrides {
+		return
+	}
+
+	// Merged keys are compared after explicit keys, which take precedence
+	// wherever they are defined in the mapping.
+	for _, mv := range m.Values {
+		if _, ok := mv.Key.(*ast.MergeKeyNode); !ok {
+			continue
+		}
+
+		e := &expander{anchors: c.anchors, active: map[*ast.AnchorNode]bool{}}
+
+		for _, entries := range e.mergeSources(mv.Value) {
+			for _, sub := range entries {
+				key := keyID(sub.Key)
+				def := keyDefinition{token: sub.Key.GetToken(), text: keyText(sub.Key), merged: true}
+
+				first, ok := defined[key]
+				switch {
+				case !ok:
+					defined[key] = def
+				case first.merged:
+					c.report(first, def)
+				default:
+					// The explicit key overrides the merged key.
+					c.report(def, first)
+				}
+			}
+		}
+	}
+}
+
+// report records that dup duplicates the key first.
+func (c *duplicateKeyChecker) report(first, dup keyDefinition) {
+	firstMsg := "first defined here"
+	if first.merged {
+		firstMsg = "first merged from here"
+	}
+
+	dupMsg := "duplicate key"
+	if dup.merged {
+		dupMsg = "merged again from here"
+	}
+
+	c.errs = append(c.errs, NewErrorFrom(
+		fmt.Errorf("%w %s", ErrDuplicateKey, dup.text),
+		WithLabels(
+			NewPrimaryLabel(c.keyRange(dup.token), dupMsg),
+			NewSecondaryLabel(c.keyRange(first.token), firstMsg),
+		),
+	))
+}
+
+// keyID returns the identity of a mapping key: the value of string keys, so
+// that quoted and plain keys are equal, and the text of other keys.
+func keyID(key ast.MapKeyNode) string {
+	if s, ok := unwrapNode(key).(*ast.StringNode); ok {
+		return s.Value
+	}
+
+	return keyText(key)
+}
+
+// keyRange returns the range of the content of the key token tk.
+func (c *duplicateKeyChecker) keyRange(tk *token.Token) position.Range {
+	start := position.NewFromToken(tk)
+
+	ranges := c.source.ContentPositionRanges(start)
+	if len(ranges) == 0 {
+		return position.NewRange(start, start)
+	}
+
+	return position.NewRange(ranges[0].Start, ranges[len(ranges)-1].End)
+}
@@ -0,0 +1,155 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+func TestSource_CheckDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("a: 1\nb:\n  a: 2\n")
+		require.NoError(t, source.CheckDuplicateKeys())
+	})
+
+	t.Run("both locations", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			name: app
+			spec:
+			  replicas: 1
+			name: other
+		`), niceyaml.WithErrorOptions(niceyaml.WithPrinter(newPlainPrinter())))
+
+		err := source.CheckDuplicateKeys()
+		require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+		assert.Equal(t, stringtest.JoinLF(
+			"[4:1] duplicate key name:",
+			"",
+			"   1  name: app",
+			"      ---- first defined here",
+			"   2  spec:",
+			"   3    replicas: 1",
+			"   4  name: other",
+			"      ^^^^ duplicate key",
+		), trimLines(err.Error()))
+	})
+
+	t.Run("several duplicates render both locations", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			a: 1
+			b:
+			  c: 1
+			  c: 2
+			a: 3
+		`), niceyaml.WithErrorOptions(niceyaml.WithPrinter(newPlainPrinter())))
+
+		err := source.CheckDuplicateKeys()
+		require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+		assert.Equal(t, stringtest.JoinLF(
+			"found 2 duplicate keys:",
+			"",
+			"   1  a: 1",
+			"      - first defined here",
+			"   2  b:",
+			"   3    c: 1",
+			"        - first defined here",
+			"   4    c: 2",
+			"        ^ duplicate key c",
+			"   5  a: 3",
+			"      ^ duplicate key a",
+		), trimLines(err.Error()))
+	})
+
+	t.Run("several duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			a: 1
+			b:
+			  c: 1
+			  "c": 2
+			a: 3
+			d: [{e: 1, e: 2}]
+		`))
+
+		err := source.CheckDuplicateKeys()
+		require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+		assert.Equal(t, stringtest.JoinLF(
+			`4:3: error: duplicate key "c"`,
+			"5:1: error: duplicate key a",
+			"6:12: error: duplicate key e",
+		), compactMessages(t, err))
+	})
+
+	t.Run("merge keys", func(t *testing.T) {
+		t.Parallel()
+
+		input := stringtest.Input(`
+			base: &base
+			  name: base
+			  port: 80
+			extra: &extra
+			  port: 8080
+			app:
+			  <<: [*base, *extra]
+			  name: app
+		`)
+
+		source := niceyaml.NewSourceFromString(input)
+		require.NoError(t, source.CheckDuplicateKeys())
+
+		err := source.CheckDuplicateKeys(niceyaml.WithMergeOverrides())
+		require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+		assert.Equal(t, stringtest.JoinLF(
+			"5:3: error: duplicate key port",
+			"8:3: error: duplicate key name",
+		), compactMessages(t, err))
+
+		var yamlErr *niceyaml.Error
+		require.ErrorAs(t, err, &yamlErr)
+
+		labels := yamlErr.Errors()[0].Labels()
+		require.Len(t, labels, 2)
+		assert.Equal(t, "merged again from here", labels[0].Message)
+		assert.Equal(t, "first merged from here", labels[1].Message)
+		assert.Equal(t, "3:3-3:7", labels[1].Range.String())
+	})
+}
+
+func TestWithDuplicateKeyCheck(t *testing.T) {
+	t.Parallel()
+
+	input := "a: 1\nb: 2\na: 3\n"
+
+	_, err := niceyaml.NewSourceFromString(input).Decoder()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, niceyaml.ErrDuplicateKey)
+
+	_, err = niceyaml.NewSourceFromString(input, niceyaml.WithDuplicateKeyCheck()).Decoder()
+	require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+	assert.Equal(t, "3:1: error: duplicate key a", compactMessages(t, err))
+
+	source := niceyaml.NewSourceFromString("a: 1\nb: 2\n", niceyaml.WithDuplicateKeyCheck())
+
+	decoder, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range decoder.Documents() {
+		var v map[string]int
+
+		require.NoError(t, doc.Decode(&v))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, v)
+	}
+}
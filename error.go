@@ -45,6 +45,11 @@ var (
 // path-aware, and thus should use [WithPath], but it will likely not have
 // access to the [Source].
 //
+// Use [WithLabels] to mark additional ranges of the source, each with its own
+// message and style, in the style of compiler diagnostics. Diagnostics may
+// also carry a [Severity], a code, a help message, a documentation URL, and
+// [Suggestion]s to fix them; see [WithSeverity] and [WithSuggestions].
+//
 // For convenience, [Source.WrapError] can be used if you only need to add the
 // [Source] without any other [ErrorOption] values.
 //
@@ -60,8 +65,19 @@ type Error struct {
 	token       *token.Token
 	widthFunc   func() int
 	errors      []*Error
+	labels      []Label
 	sourceLines int
 	width       int
+	severity    Severity
+	code        string
+	help        string
+	docsURL     string
+	suggestions []Suggestion
+	format      ErrorFormat
+	grouping    ErrorGrouping
+	dedupe      bool
+	sortErrors  bool
+	maxErrors   int
 }
 
 // NewError creates a new [*Error] with the given message.
@@ -92,6 +108,17 @@ func NewErrorFrom(err error, opts ...ErrorOption) *Error {
 //   - [WithSource]
 //   - [WithWidthFunc]
 //   - [WithErrors]
+//   - [WithLabels]
+//   - [WithSeverity]
+//   - [WithCode]
+//   - [WithHelp]
+//   - [WithDocsURL]
+//   - [WithSuggestions]
+//   - [WithErrorFormat]
+//   - [WithErrorGrouping]
+//   - [WithErrorDeduplication]
+//   - [WithSortedErrors]
+//   - [WithMaxErrors]
 type ErrorOption func(e *Error)
 
 // WithSourceLines is an [ErrorOption] that sets the number of context lines to
@@ -147,19 +174,43 @@ func WithWidthFunc(fn func() int) ErrorOption {
 // WithErrors is an [ErrorOption] that adds nested errors to the [Error].
 //
 // Each nested error has its own YAML path or token and is rendered as an
-// annotation below its resolved line.
+// annotation below its resolved line. See [WithErrorGrouping],
+// [WithErrorDeduplication], [WithSortedErrors], and [WithMaxErrors] to control
+// how many nested errors are rendered.
 func WithErrors(errs ...*Error) ErrorOption {
 	return func(e *Error) {
 		e.errors = append(e.errors, errs...)
 	}
 }
 
+// WithLabels is an [ErrorOption] that adds [Label]s to the [Error].
+//
+// Each label highlights its range and is rendered with its message below the
+// range. Since labels refer to positions, they require the source to be
+// available, see [WithSource] and [WithErrorToken].
+func WithLabels(labels ...Label) ErrorOption {
+	return func(e *Error) {
+		e.labels = append(e.labels, labels...)
+	}
+}
+
 // Error returns the error message with source annotation if available.
+//
+// The output depends on the [ErrorFormat], see [WithErrorFormat].
 func (e Error) Error() string {
 	if e.err == nil {
 		return ""
 	}
 
+	switch e.format {
+	case ErrorFormatCompact:
+		return e.formatCompact()
+	case ErrorFormatGitHub:
+		return e.formatGitHub()
+	case ErrorFormatFull:
+		// Rendered below.
+	}
+
 	// Try to resolve main token for position display.
 	mainToken, err := e.resolveMainToken()
 	if err != nil {
@@ -173,30 +224,52 @@ func (e Error) Error() string {
 			slog.Any("error", err),
 		)
 
-		// Check if we can still render via nested errors (nested-only case).
-		if e.source == nil || !e.hasResolvableNestedErrors() {
+		// Check if we can still render via nested errors or labels.
+		if e.source == nil || (!e.hasResolvableNestedErrors() && len(e.labels) == 0) {
 			if pathStr != "" {
-				return fmt.Sprintf("at %s: %v", pathStr, e.err)
+				return e.withDiagnostics(fmt.Sprintf("at %s: %v", pathStr, e.err), nil)
 			}
 
-			return e.formatPlainError()
+			return e.withDiagnostics(e.formatPlainError(), nil)
 		}
 
 		// Proceed with nested-only rendering (mainToken stays nil).
 	}
 
+	styles := e.styles()
+
 	// Build the error header.
-	var header string
+	header := e.diagnosticPrefix(styles)
 
 	if mainToken != nil {
 		pos := position.NewFromToken(mainToken)
-		header = fmt.Sprintf("[%s] %v:\n", pos.String(), e.err)
+		header += fmt.Sprintf("[%s] %v:\n", pos.String(), e.err)
+	} else if primary, ok := e.primaryLabel(); ok {
+		header += fmt.Sprintf("[%s] %v:\n", primary.Range.Start.String(), e.err)
 	} else {
-		header = fmt.Sprintf("%v:\n", e.err)
+		header += fmt.Sprintf("%v:\n", e.err)
 	}
 
 	// Render the source with error positions highlighted.
-	return header + "\n" + e.renderErrorSource(mainToken)
+	out := header + "\n" + e.renderErrorSource(mainToken)
+
+	if footer := e.diagnosticFooter(styles); footer != "" {
+		out += "\n\n" + footer
+	}
+
+	return out
+}
+
+// withDiagnostics adds the severity, code, help, documentation URL, and
+// suggestions of the error to msg, which is rendered without source.
+func (e Error) withDiagnostics(msg string, styles StyleGetter) string {
+	out := e.diagnosticPrefix(styles) + msg
+
+	if footer := e.diagnosticFooter(styles); footer != "" {
+		out += "\n" + footer
+	}
+
+	return out
 }
 
 // formatPlainError formats the error without source annotation.
@@ -210,11 +283,14 @@ func (e Error) formatPlainError() string {
 
 	sb.WriteString(e.err.Error())
 
-	for _, nested := range e.errors {
-		if nested != nil && nested.err != nil {
-			sb.WriteString("\n  • ")
-			sb.WriteString(nested.err.Error())
-		}
+	nested, omitted := e.shownErrors(nil)
+	for _, n := range nested {
+		sb.WriteString("\n  • ")
+		sb.WriteString(n.err.Error())
+	}
+
+	if omitted > 0 {
+		sb.WriteString("\n  " + moreErrorsMessage(omitted))
 	}
 
 	return sb.String()
@@ -241,10 +317,15 @@ func (e *Error) resolveMainToken() (*token.Token, error) {
 	return resolveToken(file, nil, e.path)
 }
 
-// hasResolvableNestedErrors checks if any nested error has a path or token.
+// hasResolvableNestedErrors checks if any nested error has a path, token, or
+// primary [Label].
 func (e *Error) hasResolvableNestedErrors() bool {
 	for _, nested := range e.errors {
-		if nested != nil && (nested.token != nil || nested.path != nil) {
+		if nested == nil {
+			continue
+		}
+
+		if _, ok := nested.primaryLabel(); ok || nested.token != nil || nested.path != nil {
 			return true
 		}
 	}
@@ -252,6 +333,87 @@ func (e *Error) hasResolvableNestedErrors() bool {
 	return false
 }
 
+// primaryLabel returns the first primary [Label], if any.
+func (e *Error) primaryLabel() (Label, bool) {
+	for _, l := range e.labels {
+		if l.Primary {
+			return l, true
+		}
+	}
+
+	return Label{}, false
+}
+
+// Labels returns the [Label]s of the [Error].
+func (e *Error) Labels() []Label {
+	return e.labels
+}
+
+// Message returns the message of the underlying error, without any source
+// annotation.
+func (e *Error) Message() string {
+	if e.err == nil {
+		return ""
+	}
+
+	return e.err.Error()
+}
+
+// Source returns the [*Source] of the [Error], or nil if none was set.
+func (e *Error) Source() *Source {
+	return e.source
+}
+
+// Errors returns the nested errors added with [WithErrors].
+func (e *Error) Errors() []*Error {
+	return e.errors
+}
+
+// ResolveRange returns the range of the source where the error occurred,
+// resolved from its token, path, or primary [Label].
+//
+// Paths are resolved in the source of the [Error]. Nested errors usually have
+// no source of their own and are resolved in their parent's source, so src is
+// used when the [Error] has none. Returns false if the range cannot be
+// resolved.
+func (e *Error) ResolveRange(src *Source) (position.Range, bool) {
+	if e.source != nil {
+		src = e.source
+	}
+
+	tk := e.token
+	if tk == nil && e.path != nil && src != nil {
+		file, err := src.File()
+		if err == nil {
+			tk, err = resolveToken(file, nil, e.path)
+		}
+
+		if err != nil {
+			slog.Debug("resolve error range", slog.Any("error", err))
+		}
+	}
+
+	if tk != nil && tk.Position != nil {
+		t := src
+		if t == nil {
+			t = NewSourceFromToken(tk)
+		}
+
+		pos := position.NewFromToken(tk)
+		if ranges := t.ContentPositionRanges(pos); len(ranges) > 0 {
+			return position.NewRange(ranges[0].Start, ranges[len(ranges)-1].End), true
+		}
+
+		return position.NewRange(pos, pos), true
+	}
+
+	if primary, ok := e.primaryLabel(); ok {
+		return primary.Range, true
+	}
+
+	return position.Range{}, false
+}
+
 // SetOption applies the provided [ErrorOption] values to the [Error].
 func (e *Error) SetOption(opts ...ErrorOption) {
 	for _, opt := range opts {
@@ -333,6 +495,7 @@ type errorPosition struct {
 	message string            // Error message for annotation (empty for main error).
 	ranges  []position.Range  // Ranges for this error's highlighting.
 	pos     position.Position // Position for highlighting and annotation placement.
+	labels  []Label           // Labels of a nested error, other than the one at pos.
 }
 
 // buildHunkSpans groups error line indices into spans based on proximity.
@@ -392,8 +555,31 @@ func prepareLineAnnotations(positions []errorPosition) map[int]line.Annotation {
 	return result
 }
 
-// resolveNestedError resolves a single nested error's path or token.
+// resolveNestedError resolves a single nested error's path, token, or primary
+// [Label].
 func (e *Error) resolveNestedError(t *Source, nested *Error) (errorPosition, error) {
+	if primary, ok := nested.primaryLabel(); ok && nested.token == nil && nested.path == nil {
+		if primary.Range.Start.Line >= t.Len() {
+			return errorPosition{}, ErrTokenNotFound
+		}
+
+		// The primary label is shown by the error's own annotation.
+		var labels []Label
+
+		for _, l := range nested.labels {
+			if !l.Primary {
+				labels = append(labels, l)
+			}
+		}
+
+		return errorPosition{
+			message: nested.err.Error(),
+			pos:     primary.Range.Start,
+			ranges:  primary.Range.SliceLines(),
+			labels:  labels,
+		}, nil
+	}
+
 	file, err := t.File()
 	if err != nil {
 		return errorPosition{}, fmt.Errorf("parse source: %w", err)
@@ -416,14 +602,16 @@ func (e *Error) resolveNestedError(t *Source, nested *Error) (errorPosition, err
 	return errorPosition{
 		message: nested.err.Error(),
 		pos:     pos,
+		labels:  nested.labels,
 	}, nil
 }
 
 // collectErrorPositions collects all error positions (main and nested) into a
-// unified slice.
+// unified slice, and returns the number of nested errors omitted, see
+// [WithMaxErrors].
 // If mainToken is provided, it becomes the first position without a message.
 // Nested errors are appended with their messages.
-func (e *Error) collectErrorPositions(t *Source, mainToken *token.Token) []errorPosition {
+func (e *Error) collectErrorPositions(t *Source, mainToken *token.Token) ([]errorPosition, int) {
 	positions := make([]errorPosition, 0, 1+len(e.errors))
 
 	// Add main error position if token is provided.
@@ -438,11 +626,8 @@ func (e *Error) collectErrorPositions(t *Source, mainToken *token.Token) []error
 	}
 
 	// Add nested error positions.
-	for _, nested := range e.errors {
-		if nested == nil || nested.err == nil {
-			continue
-		}
-
+	shown, omitted := e.shownErrors(t)
+	for _, nested := range shown {
 		r, resolveErr := e.resolveNestedError(t, nested)
 		if resolveErr != nil {
 			slog.Debug("resolve nested error",
@@ -452,11 +637,14 @@ func (e *Error) collectErrorPositions(t *Source, mainToken *token.Token) []error
 			continue
 		}
 
-		r.ranges = t.ContentPositionRanges(r.pos)
+		if r.ranges == nil {
+			r.ranges = t.ContentPositionRanges(r.pos)
+		}
+
 		positions = append(positions, r)
 	}
 
-	return positions
+	return positions, omitted
 }
 
 // renderErrorSource renders the error source with all error positions highlighted.
@@ -471,11 +659,12 @@ func (e *Error) renderErrorSource(mainToken *token.Token) string {
 		// Create Source from token to ensure position alignment.
 		t = NewSourceFromToken(mainToken)
 	} else {
-		// Nested-only case: use existing source directly.
-		t = e.source
+		// Nested-only case: render a copy of the source, so that its overlays
+		// and annotations are not changed.
+		t = NewSourceFromTokens(e.source.Tokens())
 	}
 
-	positions := e.collectErrorPositions(t, mainToken)
+	positions, omitted := e.collectErrorPositions(t, mainToken)
 
 	// Collect all ranges from positions and apply overlays directly.
 	var allRanges position.Ranges
@@ -492,6 +681,26 @@ func (e *Error) renderErrorSource(mainToken *token.Token) string {
 		t.Line(lineIdx).AddAnnotation(annotation)
 	}
 
+	// Apply labels, including those of nested errors, and include their lines
+	// in the hunks.
+	labels := slices.Clone(e.labels)
+	for _, pos := range positions {
+		labels = append(labels, pos.labels...)
+	}
+
+	for _, l := range labels {
+		if l.Range.Start.Line < 0 || l.Range.End.Line >= t.Len() || l.Range.End.Line < l.Range.Start.Line {
+			slog.Debug("label out of range", slog.String("range", l.Range.String()))
+
+			continue
+		}
+
+		t.AddOverlay(l.Style, l.Range)
+		t.Line(l.Range.End.Line).AddAnnotation(l.annotation())
+
+		allRanges = append(allRanges, l.Range)
+	}
+
 	// Build hunk spans from all line indices covered by error ranges.
 	hunkSpans := e.buildHunkSpans(allRanges.LineIndices(), t.Len())
 
@@ -506,7 +715,13 @@ func (e *Error) renderErrorSource(mainToken *token.Token) string {
 	}
 
 	// Print source with all hunk spans.
-	return p.Print(t, hunkSpans...)
+	out := p.Print(t, hunkSpans...)
+
+	if omitted > 0 {
+		out += "\n\n" + moreErrorsMessage(omitted)
+	}
+
+	return out
 }
 
 // resolveToken resolves a token from either a direct token or path.
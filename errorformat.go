@@ -0,0 +1,237 @@
+package niceyaml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// ErrUnknownErrorFormat indicates that an [ErrorFormat] name is not known.
+var ErrUnknownErrorFormat = errors.New("unknown error format")
+
+// ErrorFormat selects how [Error.Error] renders an [Error].
+//
+// The zero value is [ErrorFormatFull].
+type ErrorFormat int
+
+const (
+	// ErrorFormatFull renders the error message followed by an annotated
+	// excerpt of the source.
+	ErrorFormatFull ErrorFormat = iota
+	// ErrorFormatCompact renders one line per error, in the form
+	// "file:line:col: severity: message [path]", as understood by Vim's
+	// quickfix list and Emacs' compilation mode.
+	ErrorFormatCompact
+	// ErrorFormatGitHub renders one GitHub Actions workflow command per error,
+	// e.g. "::error file=a.yaml,line=3,col=13::message", so that errors are
+	// shown as annotations on pull requests.
+	ErrorFormatGitHub
+)
+
+// String returns the name of the format: "full", "compact", or "github".
+func (f ErrorFormat) String() string {
+	switch f {
+	case ErrorFormatFull:
+		return "full"
+	case ErrorFormatCompact:
+		return "compact"
+	case ErrorFormatGitHub:
+		return "github"
+	default:
+		return fmt.Sprintf("ErrorFormat(%d)", int(f))
+	}
+}
+
+// ParseErrorFormat returns the [ErrorFormat] with the given name, as returned
+// by [ErrorFormat.String].
+// Returns [ErrUnknownErrorFormat] if the name is not known.
+func ParseErrorFormat(name string) (ErrorFormat, error) {
+	for _, f := range []ErrorFormat{ErrorFormatFull, ErrorFormatCompact, ErrorFormatGitHub} {
+		if strings.EqualFold(name, f.String()) {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownErrorFormat, name)
+}
+
+// WithErrorFormat is an [ErrorOption] that sets the [ErrorFormat] used by
+// [Error.Error]. By default, [ErrorFormatFull] is used.
+func WithErrorFormat(f ErrorFormat) ErrorOption {
+	return func(e *Error) {
+		e.format = f
+	}
+}
+
+// errorLine is a single error rendered by the line-based [ErrorFormat]s.
+//
+// A line without an error notes the number of errors omitted, see
+// [WithMaxErrors].
+type errorLine struct {
+	err      *Error
+	file     string
+	rng      position.Range
+	hasRange bool
+	omitted  int
+}
+
+// errorLines flattens e and its nested errors into [errorLine]s, see
+// [Error.Flatten].
+func (e *Error) errorLines() []errorLine {
+	var lines []errorLine
+
+	e.flatten(nil, func(err *Error, src *Source, omitted int) bool {
+		if err == nil {
+			lines = append(lines, errorLine{omitted: omitted})
+
+			return true
+		}
+
+		l := errorLine{err: err}
+		l.rng, l.hasRange = err.ResolveRange(src)
+
+		if src != nil {
+			l.file = src.FilePath()
+			if l.file == "" {
+				l.file = src.Name()
+			}
+		}
+
+		lines = append(lines, l)
+
+		return true
+	})
+
+	return lines
+}
+
+// message returns the message of l, followed by its YAML path in brackets if
+// it has one.
+func (l errorLine) message() string {
+	msg := l.err.Message()
+	if p := l.err.Path(); p != "" {
+		msg += " [" + p + "]"
+	}
+
+	return msg
+}
+
+// formatCompact renders e with [ErrorFormatCompact].
+func (e *Error) formatCompact() string {
+	lines := e.errorLines()
+	out := make([]string, 0, len(lines))
+
+	for _, l := range lines {
+		if l.err == nil {
+			out = append(out, moreErrorsMessage(l.omitted))
+
+			continue
+		}
+
+		var sb strings.Builder
+
+		if l.file != "" {
+			sb.WriteString(l.file + ":")
+		}
+
+		if l.hasRange {
+			fmt.Fprintf(&sb, "%d:%d:", l.rng.Start.Line+1, l.rng.Start.Col+1)
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+
+		sb.WriteString(l.err.severity.String() + ": " + l.message())
+		out = append(out, sb.String())
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// formatGitHub renders e with [ErrorFormatGitHub].
+func (e *Error) formatGitHub() string {
+	lines := e.errorLines()
+	out := make([]string, 0, len(lines))
+
+	for _, l := range lines {
+		if l.err == nil {
+			out = append(out, "::notice::"+escapeGitHubData(moreErrorsMessage(l.omitted)))
+
+			continue
+		}
+
+		var props []string
+
+		if l.file != "" {
+			props = append(props, "file="+escapeGitHubProperty(l.file))
+		}
+
+		if l.hasRange {
+			props = append(props,
+				fmt.Sprintf("line=%d", l.rng.Start.Line+1),
+				fmt.Sprintf("col=%d", l.rng.Start.Col+1),
+			)
+
+			// GitHub end columns are inclusive.
+			if l.rng.End.Line > l.rng.Start.Line || l.rng.End.Col > l.rng.Start.Col {
+				props = append(props,
+					fmt.Sprintf("endLine=%d", l.rng.End.Line+1),
+					fmt.Sprintf("endColumn=%d", l.rng.End.Col),
+				)
+			}
+		}
+
+		if l.err.code != "" {
+			props = append(props, "title="+escapeGitHubProperty(l.err.code))
+		}
+
+		cmd := "::" + githubCommand(l.err.severity)
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+
+		out = append(out, cmd+"::"+escapeGitHubData(l.message()))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// githubCommand returns the workflow command for the given [Severity].
+func githubCommand(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo, SeverityHint:
+		return "notice"
+	default:
+		return "error"
+	}
+}
+
+var (
+	githubDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	githubPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+// escapeGitHubData escapes the message of a workflow command.
+func escapeGitHubData(s string) string {
+	return githubDataEscaper.Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return githubPropertyEscaper.Replace(s)
+}
@@ -0,0 +1,132 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/paths"
+)
+
+func TestError_Format(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+		  image: nginx
+	`)
+
+	nested := func() niceyaml.ErrorOption {
+		return niceyaml.WithErrors(
+			niceyaml.NewError("expected integer",
+				niceyaml.WithPath(paths.Root().Child("spec", "replicas").Value()),
+				niceyaml.WithCode("NY0012"),
+			),
+			niceyaml.NewError("should be pinned, e.g. nginx:1.27",
+				niceyaml.WithPath(paths.Root().Child("spec", "image").Value()),
+				niceyaml.WithSeverity(niceyaml.SeverityWarning),
+			),
+		)
+	}
+
+	tcs := map[string]struct {
+		source *niceyaml.Source
+		want   string
+		opts   []niceyaml.ErrorOption
+	}{
+		"compact with path": {
+			source: niceyaml.NewSourceFromString(input, niceyaml.WithFilePath("deploy.yaml")),
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact),
+				niceyaml.WithPath(paths.Root().Child("name").Key()),
+				niceyaml.WithSeverity(niceyaml.SeverityHint),
+			},
+			want: "deploy.yaml:1:1: hint: invalid [$.name]",
+		},
+		"compact nested errors": {
+			source: niceyaml.NewSourceFromString(input, niceyaml.WithName("deploy.yaml")),
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact),
+				nested(),
+			},
+			want: stringtest.JoinLF(
+				"deploy.yaml:3:13: error: expected integer [$.spec.replicas]",
+				"deploy.yaml:4:10: warning: should be pinned, e.g. nginx:1.27 [$.spec.image]",
+			),
+		},
+		"compact without source": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact),
+			},
+			want: "error: invalid",
+		},
+		"github nested errors": {
+			source: niceyaml.NewSourceFromString(input, niceyaml.WithFilePath("deploy.yaml")),
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorFormat(niceyaml.ErrorFormatGitHub),
+				nested(),
+			},
+			want: stringtest.JoinLF(
+				"::error file=deploy.yaml,line=3,col=13,endLine=3,endColumn=17,title=NY0012::"+
+					"expected integer [$.spec.replicas]",
+				"::warning file=deploy.yaml,line=4,col=10,endLine=4,endColumn=14::"+
+					"should be pinned, e.g. nginx:1.27 [$.spec.image]",
+			),
+		},
+		"github escaping": {
+			source: niceyaml.NewSourceFromString(input, niceyaml.WithFilePath("a,b:c.yaml")),
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorFormat(niceyaml.ErrorFormatGitHub),
+				niceyaml.WithSeverity(niceyaml.SeverityInfo),
+			},
+			want: "::notice file=a%2Cb%3Ac.yaml::invalid",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := tc.opts
+			if tc.source != nil {
+				opts = append([]niceyaml.ErrorOption{niceyaml.WithSource(tc.source)}, opts...)
+			}
+
+			err := niceyaml.NewError("invalid", opts...)
+
+			assert.Equal(t, tc.want, err.Error())
+		})
+	}
+}
+
+func TestError_Format_MultilineMessage(t *testing.T) {
+	t.Parallel()
+
+	err := niceyaml.NewError("100% broken\nsee above",
+		niceyaml.WithErrorFormat(niceyaml.ErrorFormatGitHub),
+	)
+
+	assert.Equal(t, "::error::100%25 broken%0Asee above", err.Error())
+}
+
+func TestParseErrorFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []niceyaml.ErrorFormat{
+		niceyaml.ErrorFormatFull,
+		niceyaml.ErrorFormatCompact,
+		niceyaml.ErrorFormatGitHub,
+	} {
+		got, err := niceyaml.ParseErrorFormat(f.String())
+		require.NoError(t, err)
+		assert.Equal(t, f, got)
+	}
+
+	_, err := niceyaml.ParseErrorFormat("vim")
+	require.ErrorIs(t, err, niceyaml.ErrUnknownErrorFormat)
+}
@@ -0,0 +1,281 @@
+package niceyaml
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// ErrorGrouping controls how the nested errors of an [Error] are grouped when
+// rendered, see [WithErrorGrouping].
+//
+// The zero value is [ErrorGroupingNone].
+type ErrorGrouping int
+
+const (
+	// ErrorGroupingNone renders each nested error separately.
+	ErrorGroupingNone ErrorGrouping = iota
+	// ErrorGroupingLine combines nested errors on the same line into one.
+	ErrorGroupingLine
+	// ErrorGroupingPath combines nested errors with the same YAML path into
+	// one.
+	ErrorGroupingPath
+)
+
+// WithErrorGrouping is an [ErrorOption] that combines nested errors on the
+// same line or with the same YAML path into a single error, whose message
+// joins their messages with "; ". The combined error is located at the first
+// error of the group, and has the highest [Severity] of the group.
+//
+// Nested errors without a location are never grouped.
+func WithErrorGrouping(g ErrorGrouping) ErrorOption {
+	return func(e *Error) {
+		e.grouping = g
+	}
+}
+
+// WithErrorDeduplication is an [ErrorOption] that renders nested errors with
+// the same message and location only once.
+func WithErrorDeduplication() ErrorOption {
+	return func(e *Error) {
+		e.dedupe = true
+	}
+}
+
+// WithSortedErrors is an [ErrorOption] that renders nested errors in order of
+// their position in the source. Nested errors without a position are rendered
+// last, in their original order.
+func WithSortedErrors() ErrorOption {
+	return func(e *Error) {
+		e.sortErrors = true
+	}
+}
+
+// WithMaxErrors is an [ErrorOption] that renders at most n nested errors,
+// followed by a note such as "and 3 more errors". Zero or a negative n
+// renders all nested errors.
+//
+// The limit applies after deduplication and grouping, see
+// [WithErrorDeduplication] and [WithErrorGrouping].
+func WithMaxErrors(n int) ErrorOption {
+	return func(e *Error) {
+		e.maxErrors = n
+	}
+}
+
+// shownError is a nested error with its resolved range.
+type shownError struct {
+	err      *Error
+	rng      position.Range
+	hasRange bool
+}
+
+// shownErrors returns the nested errors of e to render, after deduplication,
+// sorting, grouping, and truncation, along with the number of errors omitted
+// by truncation. Ranges are resolved in src, unless e has its own source.
+//
+// These options only change how e is rendered: [Error.Errors] and
+// [Error.Unwrap] always return all nested errors.
+func (e *Error) shownErrors(src *Source) ([]*Error, int) {
+	var nested []*Error
+
+	for _, n := range e.errors {
+		if n != nil && n.err != nil {
+			nested = append(nested, n)
+		}
+	}
+
+	if !e.dedupe && !e.sortErrors && e.grouping == ErrorGroupingNone {
+		return truncateErrors(nested, e.maxErrors)
+	}
+
+	if e.source != nil {
+		src = e.source
+	}
+
+	shown := make([]shownError, 0, len(nested))
+	seen := map[string]bool{}
+
+	for _, n := range nested {
+		se := shownError{err: n}
+		se.rng, se.hasRange = n.ResolveRange(src)
+
+		if e.dedupe {
+			key := se.locationKey() + "\x00" + n.Message()
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+		}
+
+		shown = append(shown, se)
+	}
+
+	if e.sortErrors {
+		slices.SortStableFunc(shown, func(a, b shownError) int {
+			switch {
+			case a.hasRange && b.hasRange:
+				return cmp.Or(
+					cmp.Compare(a.rng.Start.Line, b.rng.Start.Line),
+					cmp.Compare(a.rng.Start.Col, b.rng.Start.Col),
+				)
+			case a.hasRange:
+				return -1
+			case b.hasRange:
+				return 1
+			default:
+				return 0
+			}
+		})
+	}
+
+	errs := make([]*Error, 0, len(shown))
+	for _, se := range groupErrors(shown, e.grouping) {
+		errs = append(errs, se.err)
+	}
+
+	return truncateErrors(errs, e.maxErrors)
+}
+
+// locationKey identifies the location of the error for deduplication.
+func (se shownError) locationKey() string {
+	if se.hasRange {
+		return se.rng.String()
+	}
+
+	return se.err.Path()
+}
+
+// groupKey returns the key of the error's group, or an empty string if the
+// error is not grouped.
+func (se shownError) groupKey(g ErrorGrouping) string {
+	switch g {
+	case ErrorGroupingLine:
+		if se.hasRange {
+			return strconv.Itoa(se.rng.Start.Line)
+		}
+	case ErrorGroupingPath:
+		return se.err.Path()
+	case ErrorGroupingNone:
+	}
+
+	return ""
+}
+
+// groupErrors combines errors with the same group key, in order of the first
+// error of each group.
+func groupErrors(errs []shownError, g ErrorGrouping) []shownError {
+	if g == ErrorGroupingNone {
+		return errs
+	}
+
+	var (
+		result  []shownError
+		members = map[string][]*Error{}
+		indices = map[string]int{}
+	)
+
+	for _, se := range errs {
+		key := se.groupKey(g)
+		if key == "" {
+			result = append(result, se)
+
+			continue
+		}
+
+		if _, ok := indices[key]; !ok {
+			indices[key] = len(result)
+			result = append(result, se)
+		}
+
+		members[key] = append(members[key], se.err)
+	}
+
+	for key, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+
+		msgs := make([]string, 0, len(group))
+		combined := *group[0]
+
+		for _, n := range group {
+			msgs = append(msgs, n.Message())
+			combined.severity = min(combined.severity, n.severity)
+		}
+
+		combined.err = errors.New(strings.Join(msgs, "; "))
+		result[indices[key]].err = &combined
+	}
+
+	return result
+}
+
+// truncateErrors returns the first max errors, and the number of errors
+// omitted. If max is not positive, all errors are returned.
+func truncateErrors(errs []*Error, maxErrors int) ([]*Error, int) {
+	if maxErrors <= 0 || len(errs) <= maxErrors {
+		return errs, 0
+	}
+
+	return errs[:maxErrors], len(errs) - maxErrors
+}
+
+// Flatten returns an iterator over e and its nested errors, in the order they
+// are rendered, along with the [*Source] each is resolved in.
+//
+// Nested errors are resolved in the source of their parent, after
+// deduplication, sorting, grouping, and truncation, see [WithMaxErrors]. An
+// error with nested errors is only included itself if its location is known,
+// since its nested errors usually describe the same problem in more detail.
+func (e *Error) Flatten() iter.Seq2[*Error, *Source] {
+	return func(yield func(*Error, *Source) bool) {
+		e.flatten(nil, func(err *Error, src *Source, _ int) bool {
+			return err == nil || yield(err, src)
+		})
+	}
+}
+
+// flatten calls yield for e and its nested errors as described in
+// [Error.Flatten], resolving them in src unless they have their own source.
+// Nested errors omitted by truncation are yielded as a nil error with their
+// count.
+// Returns false if yield stopped the iteration.
+func (e *Error) flatten(src *Source, yield func(err *Error, src *Source, omitted int) bool) bool {
+	if e.source != nil {
+		src = e.source
+	}
+
+	nested, omitted := e.shownErrors(src)
+
+	if _, ok := e.ResolveRange(src); ok || len(nested)+omitted == 0 {
+		if !yield(e, src, 0) {
+			return false
+		}
+	}
+
+	for _, n := range nested {
+		if !n.flatten(src, yield) {
+			return false
+		}
+	}
+
+	return omitted == 0 || yield(nil, src, omitted)
+}
+
+// moreErrorsMessage returns the note shown in place of omitted errors, e.g.
+// "and 3 more errors".
+func moreErrorsMessage(omitted int) string {
+	if omitted == 1 {
+		return "and 1 more error"
+	}
+
+	return fmt.Sprintf("and %d more errors", omitted)
+}
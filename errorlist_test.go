@@ -0,0 +1,236 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/paths"
+)
+
+func TestError_NestedErrorOptions(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		a: 1
+		b: 2
+		c: 3
+	`), niceyaml.WithName("test.yaml"))
+
+	at := func(key, msg string, opts ...niceyaml.ErrorOption) *niceyaml.Error {
+		return niceyaml.NewError(msg, append(opts, niceyaml.WithPath(paths.Root().Child(key).Value()))...)
+	}
+
+	nested := func() []*niceyaml.Error {
+		return []*niceyaml.Error{
+			at("c", "too big"),
+			at("a", "wrong type"),
+			at("c", "too big"),
+			at("a", "not allowed", niceyaml.WithSeverity(niceyaml.SeverityWarning)),
+			niceyaml.NewError("no location"),
+			at("b", "wrong type"),
+		}
+	}
+
+	tcs := map[string]struct {
+		want string
+		opts []niceyaml.ErrorOption
+	}{
+		"default": {
+			want: stringtest.JoinLF(
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:1:4: error: wrong type [$.a]",
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:1:4: warning: not allowed [$.a]",
+				"test.yaml: error: no location",
+				"test.yaml:2:4: error: wrong type [$.b]",
+			),
+		},
+		"deduplicated": {
+			opts: []niceyaml.ErrorOption{niceyaml.WithErrorDeduplication()},
+			want: stringtest.JoinLF(
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:1:4: error: wrong type [$.a]",
+				"test.yaml:1:4: warning: not allowed [$.a]",
+				"test.yaml: error: no location",
+				"test.yaml:2:4: error: wrong type [$.b]",
+			),
+		},
+		"sorted": {
+			opts: []niceyaml.ErrorOption{niceyaml.WithSortedErrors()},
+			want: stringtest.JoinLF(
+				"test.yaml:1:4: error: wrong type [$.a]",
+				"test.yaml:1:4: warning: not allowed [$.a]",
+				"test.yaml:2:4: error: wrong type [$.b]",
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml: error: no location",
+			),
+		},
+		"grouped by line": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorGrouping(niceyaml.ErrorGroupingLine),
+				niceyaml.WithErrorDeduplication(),
+			},
+			want: stringtest.JoinLF(
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:1:4: error: wrong type; not allowed [$.a]",
+				"test.yaml: error: no location",
+				"test.yaml:2:4: error: wrong type [$.b]",
+			),
+		},
+		"grouped by path": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorGrouping(niceyaml.ErrorGroupingPath),
+				niceyaml.WithSortedErrors(),
+			},
+			want: stringtest.JoinLF(
+				"test.yaml:1:4: error: wrong type; not allowed [$.a]",
+				"test.yaml:2:4: error: wrong type [$.b]",
+				"test.yaml:3:4: error: too big; too big [$.c]",
+				"test.yaml: error: no location",
+			),
+		},
+		"truncated": {
+			opts: []niceyaml.ErrorOption{niceyaml.WithMaxErrors(2)},
+			want: stringtest.JoinLF(
+				"test.yaml:3:4: error: too big [$.c]",
+				"test.yaml:1:4: error: wrong type [$.a]",
+				"and 4 more errors",
+			),
+		},
+		"all options": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithErrorDeduplication(),
+				niceyaml.WithSortedErrors(),
+				niceyaml.WithErrorGrouping(niceyaml.ErrorGroupingLine),
+				niceyaml.WithMaxErrors(3),
+			},
+			want: stringtest.JoinLF(
+				"test.yaml:1:4: error: wrong type; not allowed [$.a]",
+				"test.yaml:2:4: error: wrong type [$.b]",
+				"test.yaml:3:4: error: too big [$.c]",
+				"and 1 more error",
+			),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := nested()
+			err := niceyaml.NewError("found 6 errors", append(tc.opts,
+				niceyaml.WithErrors(errs...),
+				niceyaml.WithSource(source),
+			)...)
+
+			assert.Equal(t, tc.want, compactMessages(t, err))
+
+			// All nested errors remain available.
+			assert.Len(t, err.Errors(), len(errs))
+			assert.Len(t, err.Unwrap(), len(errs)+1)
+
+			for _, nestedErr := range errs {
+				assert.ErrorIs(t, err, nestedErr)
+			}
+		})
+	}
+}
+
+func TestError_MaxErrors_Rendering(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		a: 1
+		b: 2
+		c: 3
+	`))
+
+	nested := []*niceyaml.Error{
+		niceyaml.NewError("first", niceyaml.WithPath(paths.Root().Child("a").Value())),
+		niceyaml.NewError("second", niceyaml.WithPath(paths.Root().Child("b").Value())),
+		niceyaml.NewError("third", niceyaml.WithPath(paths.Root().Child("c").Value())),
+	}
+
+	t.Run("full", func(t *testing.T) {
+		t.Parallel()
+
+		err := niceyaml.NewError("found 3 errors",
+			niceyaml.WithErrors(nested...),
+			niceyaml.WithSource(source),
+			niceyaml.WithPrinter(newPlainPrinter()),
+			niceyaml.WithMaxErrors(1),
+		)
+
+		got := err.Error()
+		assert.Contains(t, got, "first")
+		assert.NotContains(t, got, "second")
+		assert.NotContains(t, got, "third")
+		assert.Contains(t, got, "\n\nand 2 more errors")
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		err := niceyaml.NewError("found 3 errors",
+			niceyaml.WithErrors(nested...),
+			niceyaml.WithMaxErrors(2),
+		)
+
+		assert.Equal(t, stringtest.JoinLF(
+			"found 3 errors",
+			"  • first",
+			"  • second",
+			"  and 1 more error",
+		), err.Error())
+	})
+
+	t.Run("github", func(t *testing.T) {
+		t.Parallel()
+
+		err := niceyaml.NewError("found 3 errors",
+			niceyaml.WithErrors(nested...),
+			niceyaml.WithSource(source),
+			niceyaml.WithMaxErrors(2),
+			niceyaml.WithErrorFormat(niceyaml.ErrorFormatGitHub),
+		)
+
+		assert.Contains(t, err.Error(), "\n::notice::and 1 more error")
+		assert.ErrorIs(t, err, nested[2])
+	})
+}
+
+func TestError_Flatten(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("a: 1\nb: 2\nc: 3\n")
+
+	nested := []*niceyaml.Error{
+		niceyaml.NewError("first", niceyaml.WithPath(paths.Root().Child("a").Value())),
+		niceyaml.NewError("second", niceyaml.WithErrors(
+			niceyaml.NewError("inner", niceyaml.WithPath(paths.Root().Child("b").Value())),
+		)),
+		niceyaml.NewError("third", niceyaml.WithPath(paths.Root().Child("c").Value())),
+	}
+
+	err := niceyaml.NewError("found 3 errors",
+		niceyaml.WithErrors(nested...),
+		niceyaml.WithSource(source),
+		niceyaml.WithMaxErrors(2),
+	)
+
+	var got []string
+
+	for e, src := range err.Flatten() {
+		assert.Same(t, source, src)
+
+		got = append(got, e.Message())
+	}
+
+	// Errors without a location are replaced by their nested errors, and
+	// omitted errors are skipped.
+	assert.Equal(t, []string{"first", "inner"}, got)
+}
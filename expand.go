@@ -0,0 +1,505 @@
+package niceyaml
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// Expand returns a derived [*Source] showing the documents of s as they
+// resolve: each alias is replaced by a copy of its anchored value, and each
+// merge key ("<<") is replaced by the entries it merges.
+//
+// Merge keys follow YAML merge semantics: entries defined explicitly in a
+// mapping take precedence over merged entries, and earlier merge sources take
+// precedence over later ones. Merged entries are placed at the position of
+// the merge key.
+//
+// The result is re-emitted in block style, without anchors or comments. Each
+// line keeps its provenance, so positions in the expanded [Source] (such as
+// error locations or search matches) can be mapped back with [Source.Origin]
+// and [Source.OriginRange]. Expanded lines map to the anchor definitions they
+// were copied from.
+//
+// Recursive aliases are left unexpanded.
+//
+// The expanded [Source] has the same name, file path, and options as s.
+// Returns an error if s cannot be parsed.
+func (s *Source) Expand() (*Source, error) {
+	file, err := s.File()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &expander{active: map[*ast.AnchorNode]bool{}}
+
+	for i, doc := range file.Docs {
+		e.anchors = ast.Filter(ast.AnchorType, doc)
+
+		if i > 0 || doc.Start != nil {
+			tk := doc.Start
+			if tk == nil && doc.Body != nil {
+				tk = doc.Body.GetToken()
+			}
+
+			e.emit("---", 0, tk)
+		}
+
+		if doc.Body == nil {
+			continue
+		}
+
+		text, block := e.inline(doc.Body)
+		if lit, ok := doc.Body.(*ast.LiteralNode); ok {
+			// The parent of a document's content is at column -1.
+			text = blockScalarHeader(lit, 1)
+		}
+
+		if text != "" {
+			e.emit(text, 0, doc.Body.GetToken())
+		}
+
+		if block != nil {
+			e.block(block, 0)
+		}
+	}
+
+	content := ""
+	if len(e.lines) > 0 {
+		content = strings.Join(e.lines, "\n") + "\n"
+	}
+
+	expanded := NewSourceFromString(content)
+	s.copyOptions(expanded)
+	expanded.from = s
+	expanded.origins = e.origins
+
+	return expanded, nil
+}
+
+// ExpandedFrom returns the [*Source] that s was expanded from by
+// [Source.Expand], or nil if s was not created by [Source.Expand].
+func (s *Source) ExpandedFrom() *Source {
+	return s.from
+}
+
+// Origin maps a position in a [Source] created by [Source.Expand] to the
+// corresponding position in the original [Source].
+//
+// Columns are mapped relative to the start of the node that produced the
+// line, so positions within a key or single-line scalar map exactly. Lines of
+// block scalars map to their original lines.
+// Returns false if s was not created by [Source.Expand], or if pos is out of
+// range.
+func (s *Source) Origin(pos position.Position) (position.Position, bool) {
+	if s.from == nil || pos.Line < 0 || pos.Line >= len(s.origins) {
+		return position.Position{}, false
+	}
+
+	o := s.origins[pos.Line]
+
+	return position.New(o.pos.Line, max(0, o.pos.Col+pos.Col-o.col)), true
+}
+
+// OriginRange maps a range in a [Source] created by [Source.Expand] to the
+// corresponding range in the original [Source].
+//
+// Single-line ranges keep their width. The ends of multi-line ranges are
+// mapped separately with [Source.Origin].
+// Returns false if the start of r cannot be mapped.
+func (s *Source) OriginRange(r position.Range) (position.Range, bool) {
+	start, ok := s.Origin(r.Start)
+	if !ok {
+		return position.Range{}, false
+	}
+
+	end := position.New(start.Line, start.Col+max(0, r.End.Col-r.Start.Col))
+	if r.End.Line != r.Start.Line {
+		if mapped, ok := s.Origin(r.End); ok {
+			end = mapped
+		}
+	}
+
+	return position.NewRange(start, end), true
+}
+
+// lineOrigin records the original position of the node that produced a line
+// of an expanded [Source], and the column at which that node starts in the
+// expanded line.
+type lineOrigin struct {
+	pos position.Position
+	col int
+}
+
+// expander emits the expanded lines of a document for [Source.Expand].
+type expander struct {
+	// Active holds the anchors being expanded, to detect recursion.
+	active  map[*ast.AnchorNode]bool
+	anchors []ast.Node
+	lines   []string
+	origins []lineOrigin
+}
+
+// emit appends a line with the given text at indent, produced by tk.
+func (e *expander) emit(text string, indent int, tk *token.Token) {
+	var pos position.Position
+	if tk != nil {
+		pos = position.NewFromToken(tk)
+	}
+
+	e.lines = append(e.lines, strings.Repeat(" ", indent)+text)
+	e.origins = append(e.origins, lineOrigin{pos: pos, col: indent})
+}
+
+// inline returns the text representing n on the line of its key or sequence
+// entry, and the node whose contents follow on subsequent lines, if any.
+func (e *expander) inline(n ast.Node) (string, ast.Node) {
+	switch v := n.(type) {
+	case nil:
+		return "", nil
+
+	case *ast.AnchorNode:
+		return e.inline(v.Value)
+
+	case *ast.AliasNode:
+		anchor := findAnchor(e.anchors, v)
+		if anchor == nil || e.active[anchor] {
+			return "*" + v.Value.GetToken().Value, nil
+		}
+
+		e.active[anchor] = true
+		defer delete(e.active, anchor)
+
+		text, block := e.inline(anchor.Value)
+		if block != nil {
+			// Expand now, while the anchor is active.
+			return text, e.detach(block)
+		}
+
+		return text, nil
+
+	case *ast.TagNode:
+		text, block := e.inline(v.Value)
+
+		return strings.TrimSpace(v.Start.Value + " " + text), block
+
+	case *ast.MappingNode:
+		if len(e.entries(v)) == 0 {
+			return "{}", nil
+		}
+
+		return "", v
+
+	case *ast.MappingValueNode:
+		return "", v
+
+	case *ast.SequenceNode:
+		if len(v.Values) == 0 {
+			return "[]", nil
+		}
+
+		return "", v
+
+	case *ast.LiteralNode:
+		// Content is emitted 2 columns right of the key or sequence entry.
+		return blockScalarHeader(v, 2), v
+
+	default:
+		return scalarText(n), nil
+	}
+}
+
+// block emits the lines of a mapping, sequence, or block scalar at indent.
+func (e *expander) block(n ast.Node, indent int) {
+	switch v := n.(type) {
+	case *detached:
+		e.lines = append(e.lines, indentLines(v.lines, indent)...)
+
+		for _, o := range v.origins {
+			e.origins = append(e.origins, lineOrigin{pos: o.pos, col: o.col + indent})
+		}
+
+	case *ast.MappingNode, *ast.MappingValueNode:
+		for _, mv := range e.entries(v) {
+			text, block := e.inline(mv.Value)
+
+			line := e.keyText(mv.Key) + ":"
+			if text != "" {
+				line += " " + text
+			}
+
+			e.emit(line, indent, mv.Key.GetToken())
+
+			if block != nil {
+				e.block(block, indent+2)
+			}
+		}
+
+	case *ast.SequenceNode:
+		for _, item := range v.Values {
+			text, block := e.inline(item)
+
+			if text != "" || block == nil {
+				e.emit("- "+text, indent, item.GetToken())
+				e.origins[len(e.origins)-1].col += 2
+
+				if block != nil {
+					e.block(block, indent+2)
+				}
+
+				continue
+			}
+
+			// Put the first line of the nested collection on the entry line.
+			first := len(e.lines)
+			e.block(block, indent+2)
+
+			if first < len(e.lines) {
+				e.lines[first] = strings.Repeat(" ", indent) + "- " + e.lines[first][indent+2:]
+			}
+		}
+
+	case *ast.LiteralNode:
+		lines := blockScalarLines(v)
+		base := blockScalarIndent(v, lines)
+		start := position.NewFromToken(v.Start)
+
+		for i, text := range lines {
+			pos := position.New(start.Line+1+i, base)
+			if strings.TrimSpace(text) == "" {
+				e.lines = append(e.lines, "")
+				e.origins = append(e.origins, lineOrigin{pos: pos})
+
+				continue
+			}
+
+			e.lines = append(e.lines, strings.Repeat(" ", indent)+text[min(base, len(text)):])
+			e.origins = append(e.origins, lineOrigin{pos: pos, col: indent})
+		}
+	}
+}
+
+// blockScalarLines returns the source lines of the content of a block scalar.
+// Trailing empty lines are only kept with the "+" chomping indicator.
+func blockScalarLines(v *ast.LiteralNode) []string {
+	if v.Value == nil || v.Value.GetToken() == nil {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(v.Value.GetToken().Origin, "\n"), "\n")
+	if !strings.Contains(v.Start.Value, "+") {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+
+	return lines
+}
+
+// blockScalarIndent returns the indentation of the content of a block scalar
+// with the given source lines: the number of leading spaces of its first
+// non-empty line that are not part of its value. The first line may be
+// indented further than the content, with an indentation indicator.
+func blockScalarIndent(v *ast.LiteralNode, lines []string) int {
+	value := strings.TrimLeft(v.Value.Value, "\n")
+
+	for _, text := range lines {
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+
+		indent := len(text) - len(trimmed)
+		for base := range indent {
+			if strings.HasPrefix(value, text[base:]) {
+				return base
+			}
+		}
+
+		return indent
+	}
+
+	return 0
+}
+
+// blockScalarHeader returns the header of a block scalar, such as "|" or
+// ">-". An indentation indicator is replaced by offset, the indentation of
+// the emitted content relative to its parent node.
+func blockScalarHeader(v *ast.LiteralNode, offset int) string {
+	header := v.Start.Value
+
+	i := strings.IndexAny(header, "123456789")
+	if i < 0 {
+		return header
+	}
+
+	return header[:i] + strconv.Itoa(offset) + header[i+1:]
+}
+
+// detached holds lines emitted at indent 0, to be indented when placed.
+// It is used to expand an aliased collection while its anchor is active.
+type detached struct {
+	ast.Node
+
+	lines   []string
+	origins []lineOrigin
+}
+
+// detach emits the block n at indent 0 into a [detached] node.
+func (e *expander) detach(n ast.Node) *detached {
+	lines, origins := e.lines, e.origins
+	e.lines, e.origins = nil, nil
+
+	e.block(n, 0)
+
+	d := &detached{Node: n, lines: e.lines, origins: e.origins}
+	e.lines, e.origins = lines, origins
+
+	return d
+}
+
+// entries returns the entries of a mapping with merge keys resolved.
+func (e *expander) entries(n ast.Node) []*ast.MappingValueNode {
+	var values []*ast.MappingValueNode
+
+	switch v := n.(type) {
+	case *ast.MappingNode:
+		values = v.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{v}
+	default:
+		return nil
+	}
+
+	explicit := map[string]bool{}
+
+	for _, mv := range values {
+		if _, ok := mv.Key.(*ast.MergeKeyNode); !ok {
+			explicit[e.keyText(mv.Key)] = true
+		}
+	}
+
+	var (
+		result []*ast.MappingValueNode
+		seen   = map[string]bool{}
+	)
+
+	for _, mv := range values {
+		if _, ok := mv.Key.(*ast.MergeKeyNode); !ok {
+			seen[e.keyText(mv.Key)] = true
+			result = append(result, mv)
+
+			continue
+		}
+
+		for _, merged := range e.mergeSources(mv.Value) {
+			for _, sub := range merged {
+				key := e.keyText(sub.Key)
+				if explicit[key] || seen[key] {
+					continue
+				}
+
+				seen[key] = true
+				result = append(result, sub)
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeSources returns the resolved entries of each mapping merged by a merge
+// key with the given value: an alias, a mapping, or a sequence of either.
+func (e *expander) mergeSources(value ast.Node) [][]*ast.MappingValueNode {
+	switch v := value.(type) {
+	case *ast.AliasNode:
+		anchor := findAnchor(e.anchors, v)
+		if anchor == nil || e.active[anchor] {
+			return nil
+		}
+
+		e.active[anchor] = true
+		defer delete(e.active, anchor)
+
+		return e.mergeSources(anchor.Value)
+
+	case *ast.AnchorNode:
+		return e.mergeSources(v.Value)
+
+	case *ast.TagNode:
+		return e.mergeSources(v.Value)
+
+	case *ast.SequenceNode:
+		var sources [][]*ast.MappingValueNode
+		for _, item := range v.Values {
+			sources = append(sources, e.mergeSources(item)...)
+		}
+
+		return sources
+
+	default:
+		return [][]*ast.MappingValueNode{e.entries(v)}
+	}
+}
+
+// keyText returns the text of a mapping key, with an alias replaced by its
+// anchored scalar.
+func (e *expander) keyText(key ast.MapKeyNode) string {
+	if alias, ok := key.(*ast.AliasNode); ok {
+		if text, block := e.inline(alias); text != "" && block == nil {
+			return text
+		}
+	}
+
+	return keyText(key)
+}
+
+// keyText returns the text of a mapping key.
+func keyText(key ast.MapKeyNode) string {
+	switch k := key.(type) {
+	case *ast.MappingKeyNode:
+		return strings.TrimSpace(key.String())
+	case *ast.AliasNode:
+		return "*" + k.Value.GetToken().Value
+	}
+
+	return scalarText(key)
+}
+
+// scalarText returns the source text of a scalar node, quoting multi-line
+// strings so that they fit on one line.
+func scalarText(n ast.Node) string {
+	tk := n.GetToken()
+	if tk == nil {
+		return ""
+	}
+
+	text := strings.TrimSpace(tk.Origin)
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+
+	if s, ok := n.(*ast.StringNode); ok {
+		return strconv.Quote(s.Value)
+	}
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// indentLines returns lines indented by n spaces, leaving empty lines empty.
+func indentLines(lines []string, n int) []string {
+	prefix := strings.Repeat(" ", n)
+	out := make([]string, len(lines))
+
+	for i, l := range lines {
+		if l != "" {
+			out[i] = prefix + l
+		}
+	}
+
+	return out
+}
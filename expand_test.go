@@ -0,0 +1,270 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+func TestSource_Expand(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		input string
+		want  string
+	}{
+		"alias": {
+			input: stringtest.Input(`
+				base: &base
+				  x: 1
+				  y: [a, b]
+				copy: *base
+			`),
+			want: stringtest.Input(`
+				base:
+				  x: 1
+				  y:
+				    - a
+				    - b
+				copy:
+				  x: 1
+				  y:
+				    - a
+				    - b
+			`),
+		},
+		"merge keys": {
+			input: stringtest.Input(`
+				a: &a {x: 1, y: 1}
+				b: &b {y: 2, z: 2}
+				merged:
+				  <<: [*a, *b]
+				  x: 3
+			`),
+			want: stringtest.Input(`
+				a:
+				  x: 1
+				  y: 1
+				b:
+				  y: 2
+				  z: 2
+				merged:
+				  y: 1
+				  z: 2
+				  x: 3
+			`),
+		},
+		"nested merges": {
+			input: stringtest.Input(`
+				base: &base
+				  x: 1
+				mid: &mid
+				  <<: *base
+				  y: 2
+				top:
+				  <<: *mid
+			`),
+			want: stringtest.Input(`
+				base:
+				  x: 1
+				mid:
+				  x: 1
+				  y: 2
+				top:
+				  x: 1
+				  y: 2
+			`),
+		},
+		"sequences and scalars": {
+			input: stringtest.Input(`
+				item: &item
+				  name: a
+				  tags: !set {}
+				list:
+				  - *item
+				  - 'quoted'
+				  - - nested
+				script: |
+				  echo hi
+			`),
+			want: stringtest.Input(`
+				item:
+				  name: a
+				  tags: !set {}
+				list:
+				  - name: a
+				    tags: !set {}
+				  - 'quoted'
+				  - - nested
+				script: |
+				  echo hi
+			`),
+		},
+		"alias keys": {
+			input: stringtest.Input(`
+				keys:
+				  - &k name
+				map:
+				  *k : 1
+			`),
+			want: stringtest.Input(`
+				keys:
+				  - name
+				map:
+				  name: 1
+			`),
+		},
+		"multiple documents": {
+			input: stringtest.Input(`
+				a: &v 1
+				b: *v
+				---
+				c: 2
+			`),
+			want: stringtest.Input(`
+				a: 1
+				b: 1
+				---
+				c: 2
+			`),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(tc.input)
+
+			expanded, err := source.Expand()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, expanded.Content())
+			assert.Same(t, source, expanded.ExpandedFrom())
+			require.NoError(t, expanded.Validate())
+
+			_, err = expanded.File()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSource_Expand_BlockScalars(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		base: &base
+		  indented: |2
+		      first
+		    rest
+		copy: *base
+		folded: >
+		  one
+
+		  two
+	`)
+
+	source := niceyaml.NewSourceFromString(input)
+
+	expanded, err := source.Expand()
+	require.NoError(t, err)
+
+	assert.Equal(t, stringtest.Input(`
+		base:
+		  indented: |2
+		      first
+		    rest
+		copy:
+		  indented: |2
+		      first
+		    rest
+		folded: >
+		  one
+
+		  two
+	`), expanded.Content())
+
+	var want, got map[string]any
+
+	require.NoError(t, yaml.Unmarshal([]byte(input), &want))
+	require.NoError(t, yaml.Unmarshal([]byte(expanded.Content()), &got))
+	assert.Equal(t, want, got)
+	assert.Equal(t, "one\ntwo", got["folded"])
+
+	// Lines of block scalars map to their original lines.
+	pos, ok := expanded.Origin(position.New(6, 6))
+	require.True(t, ok)
+	assert.Equal(t, position.New(2, 6), pos)
+}
+
+func TestSource_Expand_Origin(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		base: &base
+		  name: app
+		items:
+		  - <<: *base
+		    port: 80
+	`))
+
+	expanded, err := source.Expand()
+	require.NoError(t, err)
+
+	require.Equal(t, stringtest.JoinLF(
+		"base:",
+		"  name: app",
+		"items:",
+		"  - name: app",
+		"    port: 80",
+	), expanded.Content())
+
+	tcs := map[string]struct {
+		pos  position.Position
+		want position.Position
+	}{
+		"key line":                {pos: position.New(0, 0), want: position.New(0, 0)},
+		"scalar value":            {pos: position.New(1, 8), want: position.New(1, 8)},
+		"merged entry":            {pos: position.New(3, 4), want: position.New(1, 2)},
+		"merged entry value":      {pos: position.New(3, 10), want: position.New(1, 8)},
+		"explicit after merge":    {pos: position.New(4, 4), want: position.New(4, 4)},
+		"before node on the line": {pos: position.New(4, 0), want: position.New(4, 0)},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := expanded.Origin(tc.pos)
+			require.True(t, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	rng, ok := expanded.OriginRange(position.NewRange(position.New(3, 10), position.New(3, 13)))
+	require.True(t, ok)
+	assert.Equal(t, position.NewRange(position.New(1, 8), position.New(1, 11)), rng)
+
+	_, ok = expanded.Origin(position.New(10, 0))
+	assert.False(t, ok)
+
+	_, ok = source.Origin(position.New(0, 0))
+	assert.False(t, ok, "source was not expanded")
+}
+
+func TestSource_Expand_Invalid(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("key: [unclosed\n")
+
+	_, err := source.Expand()
+
+	var yamlErr *niceyaml.Error
+	require.ErrorAs(t, err, &yamlErr)
+}
@@ -0,0 +1,184 @@
+package niceyaml
+
+import (
+	"cmp"
+	"slices"
+
+	"go.jacobcolvin.com/niceyaml/outline"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// Folds tracks which [*outline.Node]s of a [Source] are collapsed.
+//
+// When set on a [Printer] via [WithFolds] or [Printer.SetFolds], each folded
+// node is rendered as its first line followed by a placeholder such as
+// "… (42 lines)", and the remaining lines of the node are skipped. Line
+// numbers in [GutterContext] always refer to the original lines, so the gutter
+// shows a jump across each fold.
+//
+// Nodes are compared by pointer, so fold state is tied to the
+// [*outline.Outline] returned by [Source.Outline] for one [Source]. Printing a
+// different [Source] with the same Folds produces undefined results.
+//
+//	o, _ := source.Outline()
+//	folds := niceyaml.NewFolds()
+//	folds.Fold(o.Lookup("$.spec"))
+//	printer := niceyaml.NewPrinter(niceyaml.WithFolds(folds))
+//	fmt.Println(printer.Print(source))
+//
+// Create instances with [NewFolds].
+type Folds struct {
+	nodes map[*outline.Node]struct{}
+}
+
+// NewFolds creates a new [*Folds] with no folded nodes.
+func NewFolds() *Folds {
+	return &Folds{nodes: map[*outline.Node]struct{}{}}
+}
+
+// Fold collapses the given node.
+// Document roots and nodes spanning a single line cannot be folded and are
+// ignored.
+func (f *Folds) Fold(n *outline.Node) {
+	if n == nil || n.IsDocument() || !n.IsFoldable() {
+		return
+	}
+
+	f.nodes[n] = struct{}{}
+}
+
+// Unfold expands the given node.
+// Folded descendants of the node remain folded.
+func (f *Folds) Unfold(n *outline.Node) {
+	delete(f.nodes, n)
+}
+
+// Toggle folds the node if it is expanded, or unfolds it if it is folded.
+// Returns true if the node is folded afterwards.
+func (f *Folds) Toggle(n *outline.Node) bool {
+	if f.IsFolded(n) {
+		f.Unfold(n)
+
+		return false
+	}
+
+	f.Fold(n)
+
+	return f.IsFolded(n)
+}
+
+// IsFolded reports whether the given node is folded.
+func (f *Folds) IsFolded(n *outline.Node) bool {
+	_, ok := f.nodes[n]
+
+	return ok
+}
+
+// FoldToDepth unfolds all nodes, then folds every foldable node in o at the
+// given depth or deeper, leaving only the first depth-1 levels of nesting
+// expanded. A depth of 1 collapses every top-level entry.
+//
+// A depth less than 1 unfolds all nodes.
+func (f *Folds) FoldToDepth(o *outline.Outline, depth int) {
+	f.UnfoldAll()
+
+	if o == nil || depth < 1 {
+		return
+	}
+
+	for n := range o.All() {
+		if n.Depth >= depth {
+			f.Fold(n)
+		}
+	}
+}
+
+// UnfoldAll expands all nodes.
+func (f *Folds) UnfoldAll() {
+	clear(f.nodes)
+}
+
+// Reveal unfolds every folded node that hides the given 0-indexed line, so
+// that the line becomes visible.
+// Returns true if any node was unfolded.
+func (f *Folds) Reveal(lineIdx int) bool {
+	var changed bool
+
+	for n := range f.nodes {
+		if n.Span.Contains(lineIdx) && n.Span.Start != lineIdx {
+			delete(f.nodes, n)
+
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// Len returns the number of folded nodes.
+func (f *Folds) Len() int {
+	return len(f.nodes)
+}
+
+// Spans returns the line spans of the outermost folded nodes, sorted by start
+// line.
+//
+// Folded nodes nested within another folded node are omitted, since they are
+// hidden entirely. The first line of each span is rendered; the rest are
+// hidden.
+func (f *Folds) Spans() position.Spans {
+	if len(f.nodes) == 0 {
+		return nil
+	}
+
+	all := make(position.Spans, 0, len(f.nodes))
+	for n := range f.nodes {
+		all = append(all, n.Span)
+	}
+
+	// Sort by start, then longest first so that outer spans precede nested
+	// spans sharing the same first line.
+	slices.SortFunc(all, func(a, b position.Span) int {
+		if c := cmp.Compare(a.Start, b.Start); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b.End, a.End)
+	})
+
+	spans := all[:0]
+	end := -1
+
+	for _, s := range all {
+		if s.Start < end {
+			continue
+		}
+
+		spans = append(spans, s)
+		end = s.End
+	}
+
+	return spans
+}
+
+// VisibleLines returns the 0-indexed line indices that remain visible in a
+// collection of n lines after folding, in ascending order.
+func (f *Folds) VisibleLines(n int) []int {
+	visible := make([]int, 0, n)
+	next := 0
+
+	for _, s := range f.Spans() {
+		// Lines up to and including the first line of the fold are visible.
+		for i := next; i <= s.Start && i < n; i++ {
+			visible = append(visible, i)
+		}
+
+		next = s.End
+	}
+
+	for i := next; i < n; i++ {
+		visible = append(visible, i)
+	}
+
+	return visible
+}
@@ -0,0 +1,169 @@
+package niceyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/outline"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+const foldInput = `
+	metadata:
+	  name: app
+	  labels:
+	    a: b
+	    c: d
+	spec:
+	  replicas: 1
+`
+
+func foldOutline(t *testing.T) (*niceyaml.Source, *outline.Outline) {
+	t.Helper()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(foldInput))
+
+	o, err := source.Outline()
+	require.NoError(t, err)
+
+	return source, o
+}
+
+func TestFolds(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fold and unfold", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+		metadata := o.Lookup("$.metadata")
+
+		folds.Fold(metadata)
+		assert.True(t, folds.IsFolded(metadata))
+		assert.Equal(t, position.Spans{position.NewSpan(0, 5)}, folds.Spans())
+
+		folds.Unfold(metadata)
+		assert.False(t, folds.IsFolded(metadata))
+		assert.Empty(t, folds.Spans())
+	})
+
+	t.Run("toggle", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+		spec := o.Lookup("$.spec")
+
+		assert.True(t, folds.Toggle(spec))
+		assert.False(t, folds.Toggle(spec))
+		assert.Equal(t, 0, folds.Len())
+	})
+
+	t.Run("ignores unfoldable nodes", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+
+		folds.Fold(o.Lookup("$.metadata.name"))
+		folds.Fold(o.Documents()[0])
+		folds.Fold(nil)
+
+		assert.Equal(t, 0, folds.Len())
+	})
+
+	t.Run("nested folds are hidden", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+
+		folds.Fold(o.Lookup("$.metadata.labels"))
+		folds.Fold(o.Lookup("$.metadata"))
+
+		assert.Equal(t, position.Spans{position.NewSpan(0, 5)}, folds.Spans())
+		assert.Equal(t, []int{0, 5, 6}, folds.VisibleLines(7))
+	})
+
+	t.Run("fold to depth", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+
+		folds.FoldToDepth(o, 2)
+		assert.Equal(t, position.Spans{position.NewSpan(2, 5)}, folds.Spans())
+		assert.Equal(t, []int{0, 1, 2, 5, 6}, folds.VisibleLines(7))
+
+		folds.FoldToDepth(o, 1)
+		assert.Equal(t, []int{0, 5}, folds.VisibleLines(7))
+
+		folds.FoldToDepth(o, 0)
+		assert.Equal(t, 0, folds.Len())
+	})
+
+	t.Run("reveal", func(t *testing.T) {
+		t.Parallel()
+
+		_, o := foldOutline(t)
+		folds := niceyaml.NewFolds()
+
+		folds.FoldToDepth(o, 1)
+
+		assert.False(t, folds.Reveal(0), "first line of a fold is visible")
+		assert.True(t, folds.Reveal(3))
+		assert.False(t, folds.IsFolded(o.Lookup("$.metadata")))
+		assert.False(t, folds.IsFolded(o.Lookup("$.metadata.labels")))
+		assert.True(t, folds.IsFolded(o.Lookup("$.spec")))
+
+		folds.UnfoldAll()
+		assert.Equal(t, 0, folds.Len())
+	})
+}
+
+func TestPrinter_Folds(t *testing.T) {
+	t.Parallel()
+
+	source, o := foldOutline(t)
+	folds := niceyaml.NewFolds()
+	folds.Fold(o.Lookup("$.metadata.labels"))
+
+	p := testPrinterWithGutter(niceyaml.LineNumberGutter())
+	p.SetFolds(folds)
+
+	want := stringtest.JoinLF(
+		"   1 metadata:",
+		"   2   name: app",
+		"   3   labels: … (3 lines)",
+		"   6 spec:",
+		"   7   replicas: 1",
+	)
+	assert.Equal(t, want, p.Print(source))
+
+	// Spans starting inside a fold skip the hidden lines.
+	want = stringtest.JoinLF(
+		"   6 spec:",
+	)
+	assert.Equal(t, want, p.Print(source, position.NewSpan(3, 6)))
+
+	var folded []int
+
+	p = niceyaml.NewPrinter(
+		niceyaml.WithFolds(folds),
+		niceyaml.WithGutter(func(ctx niceyaml.GutterContext) string {
+			if ctx.Folded {
+				folded = append(folded, ctx.Number)
+			}
+
+			return ""
+		}),
+	)
+	p.Print(source)
+
+	assert.Equal(t, []int{3}, folded)
+}
@@ -0,0 +1,46 @@
+// Package format reformats YAML sources while preserving their comments,
+// anchors, tags, and scalar content.
+//
+// A [Formatter] works on the token stream of a [niceyaml.Source] rather than
+// on its decoded data, so that nothing but layout changes:
+//
+//	f := format.New(format.WithIndent(2), format.WithQuoteStyle(format.QuoteDouble))
+//	formatted, err := f.Format(source)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(formatted.Content())
+//
+// # Layout
+//
+// Block collections are re-indented by [WithIndent] spaces per level.
+// Sequences nested in mappings are indented under their key, or aligned with
+// it, see [WithIndentSequences]. Comment lines follow the lines they
+// annotate. Multi-line scalars, block scalars, and multi-line flow
+// collections move with the line they start on, so that their content is
+// unchanged.
+//
+// Quoted scalars are converted to the [QuoteStyle] of [WithQuoteStyle] where
+// that does not change their value. The document start marker ("---") of
+// the first document is added or removed according to [WithDocumentStart].
+// Runs of blank lines are limited by [WithMaxBlankLines], leading and
+// trailing blank lines are removed, trailing whitespace is trimmed, and the
+// output ends with a single newline.
+//
+// With [WithKeyOrder], the keys of block mappings are sorted first, e.g. by
+// the property order of a JSON schema, see [niceyaml.Source.SortKeys].
+//
+// # Check Mode
+//
+// [Formatter.Check] returns a [*niceyaml.DiffResult] between a source and its
+// formatted form, which renders what would change with [niceyaml.Printer]:
+//
+//	result, err := f.Check(source)
+//	if err != nil {
+//		return err
+//	}
+//	if added, removed := result.Stats(); added+removed > 0 {
+//		hunks, spans := result.Hunks(3)
+//		fmt.Println(printer.Print(hunks, spans...))
+//	}
+package format
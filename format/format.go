@@ -0,0 +1,817 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/lexer"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+var (
+	// ErrUnknownQuoteStyle indicates that a [QuoteStyle] name is not known.
+	ErrUnknownQuoteStyle = errors.New("unknown quote style")
+
+	// ErrUnknownDocumentStart indicates that a [DocumentStart] name is not
+	// known.
+	ErrUnknownDocumentStart = errors.New("unknown document start")
+
+	// ErrContentChanged indicates that formatting a source would change its
+	// content rather than only its layout.
+	ErrContentChanged = errors.New("formatting would change the content")
+)
+
+// QuoteStyle selects the quotes of quoted scalars.
+//
+// The zero value is [QuotePreserve].
+type QuoteStyle int
+
+const (
+	// QuotePreserve keeps the quotes of each scalar.
+	QuotePreserve QuoteStyle = iota
+	// QuoteSingle prefers single quotes.
+	QuoteSingle
+	// QuoteDouble prefers double quotes.
+	QuoteDouble
+)
+
+// String returns the name of the style: "preserve", "single", or "double".
+func (q QuoteStyle) String() string {
+	switch q {
+	case QuotePreserve:
+		return "preserve"
+	case QuoteSingle:
+		return "single"
+	case QuoteDouble:
+		return "double"
+	default:
+		return fmt.Sprintf("QuoteStyle(%d)", int(q))
+	}
+}
+
+// ParseQuoteStyle returns the [QuoteStyle] with the given name, as returned
+// by [QuoteStyle.String].
+// Returns [ErrUnknownQuoteStyle] if the name is not known.
+func ParseQuoteStyle(name string) (QuoteStyle, error) {
+	for _, q := range []QuoteStyle{QuotePreserve, QuoteSingle, QuoteDouble} {
+		if strings.EqualFold(name, q.String()) {
+			return q, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownQuoteStyle, name)
+}
+
+// DocumentStart selects whether the first document starts with a document
+// start marker ("---"). Later documents always need one.
+//
+// The zero value is [DocumentStartPreserve].
+type DocumentStart int
+
+const (
+	// DocumentStartPreserve keeps the marker of the first document as is.
+	DocumentStartPreserve DocumentStart = iota
+	// DocumentStartRequire adds the marker to the first document.
+	DocumentStartRequire
+	// DocumentStartForbid removes the marker of the first document, unless
+	// it follows comments or a directive, or is followed by content on the
+	// same line.
+	DocumentStartForbid
+)
+
+// String returns the name of the policy: "preserve", "require", or "forbid".
+func (d DocumentStart) String() string {
+	switch d {
+	case DocumentStartPreserve:
+		return "preserve"
+	case DocumentStartRequire:
+		return "require"
+	case DocumentStartForbid:
+		return "forbid"
+	default:
+		return fmt.Sprintf("DocumentStart(%d)", int(d))
+	}
+}
+
+// ParseDocumentStart returns the [DocumentStart] with the given name, as
+// returned by [DocumentStart.String].
+// Returns [ErrUnknownDocumentStart] if the name is not known.
+func ParseDocumentStart(name string) (DocumentStart, error) {
+	for _, d := range []DocumentStart{DocumentStartPreserve, DocumentStartRequire, DocumentStartForbid} {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownDocumentStart, name)
+}
+
+// Formatter reformats YAML sources, see the package documentation.
+//
+// Create instances with [New].
+type Formatter struct {
+	indent          int
+	indentSequences bool
+	quoteStyle      QuoteStyle
+	documentStart   DocumentStart
+	keyOrder        niceyaml.KeyOrder
+	maxBlankLines   int
+}
+
+// Option configures a [Formatter].
+//
+// Available options:
+//   - [WithIndent]
+//   - [WithIndentSequences]
+//   - [WithQuoteStyle]
+//   - [WithDocumentStart]
+//   - [WithMaxBlankLines]
+//   - [WithKeyOrder]
+type Option func(*Formatter)
+
+// WithIndent is an [Option] that sets the number of spaces per indentation
+// level. Values below 1 are ignored. The default is 2.
+func WithIndent(n int) Option {
+	return func(f *Formatter) {
+		if n > 0 {
+			f.indent = n
+		}
+	}
+}
+
+// WithIndentSequences is an [Option] that sets whether sequences nested in
+// mappings are indented under their key:
+//
+//	key:
+//	  - item
+//
+// Otherwise, they are aligned with it:
+//
+//	key:
+//	- item
+//
+// The default is true.
+func WithIndentSequences(indent bool) Option {
+	return func(f *Formatter) {
+		f.indentSequences = indent
+	}
+}
+
+// WithQuoteStyle is an [Option] that sets the [QuoteStyle] of quoted scalars.
+//
+// Single-line scalars are converted to the style unless their value needs
+// escapes that single quotes cannot express, or contains the preferred quote
+// but not the other one. The default is [QuotePreserve].
+func WithQuoteStyle(q QuoteStyle) Option {
+	return func(f *Formatter) {
+		f.quoteStyle = q
+	}
+}
+
+// WithDocumentStart is an [Option] that sets the [DocumentStart] policy. The
+// default is [DocumentStartPreserve].
+func WithDocumentStart(d DocumentStart) Option {
+	return func(f *Formatter) {
+		f.documentStart = d
+	}
+}
+
+// WithMaxBlankLines is an [Option] that sets the maximum number of
+// consecutive blank lines. Blank lines within block scalars are kept. The
+// default is 1.
+func WithMaxBlankLines(n int) Option {
+	return func(f *Formatter) {
+		f.maxBlankLines = max(n, 0)
+	}
+}
+
+// WithKeyOrder is an [Option] that sorts the keys of block mappings by order
+// before formatting, with [niceyaml.Source.SortKeys]. By default, keys keep
+// their order.
+func WithKeyOrder(order niceyaml.KeyOrder) Option {
+	return func(f *Formatter) {
+		f.keyOrder = order
+	}
+}
+
+// New creates a new [*Formatter] with the given options.
+func New(opts ...Option) *Formatter {
+	f := &Formatter{
+		indent:          2,
+		indentSequences: true,
+		maxBlankLines:   1,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Format returns the formatted form of src, as a new [*niceyaml.Source] with
+// the name and file path of src. Line endings are normalized to "\n".
+//
+// The formatted source is checked to have the same tokens as src, apart from
+// comments, document start markers, and quotes, and the same structure.
+// Returns an error if src cannot
+// be parsed, or [ErrContentChanged] if the check fails, e.g. for content that
+// the lexer cannot reproduce.
+func (f *Formatter) Format(src *niceyaml.Source) (*niceyaml.Source, error) {
+	file, err := src.File()
+	if err != nil {
+		return nil, src.WrapError(err)
+	}
+
+	if f.keyOrder != nil {
+		src, err = src.SortKeys(f.keyOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err = src.File()
+		if err != nil {
+			return nil, src.WrapError(err)
+		}
+	}
+
+	out := f.format(src.Content())
+
+	formatted := niceyaml.NewSourceFromString(out,
+		niceyaml.WithName(src.Name()),
+		niceyaml.WithFilePath(src.FilePath()),
+	)
+
+	formattedFile, err := formatted.File()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrContentChanged, err)
+	}
+
+	tk, ok := sameContent(src.Tokens(), formatted.Tokens())
+	if !ok {
+		return nil, src.WrapError(niceyaml.NewErrorFrom(ErrContentChanged, niceyaml.WithErrorToken(tk)))
+	}
+
+	if !slices.Equal(nodePaths(file), nodePaths(formattedFile)) {
+		return nil, src.WrapError(niceyaml.NewErrorFrom(ErrContentChanged))
+	}
+
+	return formatted, nil
+}
+
+// Check returns the difference between src and its formatted form, see
+// [Formatter.Format]. The lines of src are formatted when
+// [niceyaml.DiffResult.Stats] reports no added or removed lines.
+//
+// A [niceyaml.Source] does not keep the final newline, CRLF line endings, or
+// trailing spaces of its content, so Check cannot report them. To check a
+// file, compare its bytes with the content of the formatted source instead.
+func (f *Formatter) Check(src *niceyaml.Source) (*niceyaml.DiffResult, error) {
+	formatted, err := f.Format(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return niceyaml.Diff(niceyaml.NewRevision(src), niceyaml.NewRevision(formatted)), nil
+}
+
+// sameContent reports whether the tokens a and b have the same types and
+// values, apart from comments, document start markers, and quotes. If not, it
+// returns the first token of a that differs, or its last token.
+func sameContent(a, b token.Tokens) (*token.Token, bool) {
+	a, b = contentTokens(a), contentTokens(b)
+
+	for i, tk := range a {
+		if i >= len(b) || tk.Value != b[i].Value || tk.Type != b[i].Type && !(isQuoted(tk) && isQuoted(b[i])) {
+			return tk, false
+		}
+	}
+
+	if len(a) != len(b) {
+		if len(a) == 0 {
+			return nil, false
+		}
+
+		return a[len(a)-1], false
+	}
+
+	return nil, true
+}
+
+// contentTokens returns tks without comments and document start markers.
+func contentTokens(tks token.Tokens) token.Tokens {
+	var content token.Tokens
+
+	for _, tk := range tks {
+		if tk.Type != token.CommentType && tk.Type != token.DocumentHeaderType {
+			content = append(content, tk)
+		}
+	}
+
+	return content
+}
+
+// isQuoted reports whether tk is a quoted scalar.
+func isQuoted(tk *token.Token) bool {
+	return tk.Type == token.SingleQuoteType || tk.Type == token.DoubleQuoteType
+}
+
+// nodePaths returns the path and type of each node of file, except comments,
+// in depth-first order.
+func nodePaths(file *ast.File) []string {
+	var paths []string
+
+	for _, doc := range file.Docs {
+		ast.Walk(pathVisitor{paths: &paths}, doc)
+	}
+
+	return paths
+}
+
+// pathVisitor is an [ast.Visitor] collecting the paths of visited nodes.
+type pathVisitor struct {
+	paths *[]string
+}
+
+// Visit implements [ast.Visitor].
+func (v pathVisitor) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+
+	if node.Type() != ast.CommentType {
+		*v.paths = append(*v.paths, node.GetPath()+" "+node.Type().String())
+	}
+
+	return v
+}
+
+// lineKind classifies the lines of a source.
+type lineKind int
+
+const (
+	// lineBlank is a line without tokens.
+	lineBlank lineKind = iota
+	// lineContent is a line starting with a token other than a comment.
+	lineContent
+	// lineComment is a line with only a comment.
+	lineComment
+	// lineContinued is a line within a token or flow collection that starts
+	// on an earlier line, its owner.
+	lineContinued
+)
+
+// layout is the line structure of a source.
+type layout struct {
+	lines  []string
+	kinds  []lineKind
+	owners []int
+	tokens [][]*token.Token // Tokens starting on each content line.
+	quoted [][]*token.Token // Quoted scalars starting on each line.
+}
+
+// newLayout returns the layout of content.
+func newLayout(content string) *layout {
+	lines := strings.Split(content, "\n")
+	l := &layout{
+		lines:  lines,
+		kinds:  make([]lineKind, len(lines)),
+		owners: make([]int, len(lines)),
+		tokens: make([][]*token.Token, len(lines)),
+		quoted: make([][]*token.Token, len(lines)),
+	}
+
+	var (
+		flowDepth int
+		flowLine  int
+	)
+
+	tks := lexer.Tokenize(content)
+	for i, tk := range tks {
+		if tk.Position == nil {
+			continue
+		}
+
+		start := tk.Position.Line - 1
+		if isQuoted(tk) {
+			l.quoted[start] = append(l.quoted[start], tk)
+		}
+
+		switch {
+		case i > 0 && isBlockHeader(tks[i-1]):
+			// The positions of block scalar content are unreliable, so its
+			// lines are counted from its header instead.
+			head := tks[i-1].Position.Line - 1
+			l.continueLines(l.owner(head), head+1, head+blockLines(tk, tks[i-1]))
+
+			continue
+		case flowDepth > 0 && start != flowLine:
+			l.continueLines(l.owner(flowLine), start, start+tokenLines(tk)-1)
+		case l.kinds[start] == lineContinued:
+			l.continueLines(l.owners[start], start, start+tokenLines(tk)-1)
+		default:
+			if l.kinds[start] == lineBlank {
+				l.kinds[start] = lineContent
+				if tk.Type == token.CommentType {
+					l.kinds[start] = lineComment
+				}
+			}
+
+			l.tokens[start] = append(l.tokens[start], tk)
+			l.continueLines(start, start+1, start+tokenLines(tk)-1)
+		}
+
+		switch tk.Type {
+		case token.SequenceStartType, token.MappingStartType:
+			if flowDepth == 0 {
+				flowLine = start
+			}
+
+			flowDepth++
+		case token.SequenceEndType, token.MappingEndType:
+			flowDepth = max(flowDepth-1, 0)
+		default:
+		}
+	}
+
+	return l
+}
+
+// owner returns the line that line i belongs to: its owner if it is
+// continued, or itself.
+func (l *layout) owner(i int) int {
+	if l.kinds[i] == lineContinued {
+		return l.owners[i]
+	}
+
+	return i
+}
+
+// continueLines marks the lines from start to end as continuations of owner.
+func (l *layout) continueLines(owner, start, end int) {
+	for i := max(start, 0); i <= end && i < len(l.lines); i++ {
+		if i == owner {
+			continue
+		}
+
+		l.kinds[i] = lineContinued
+		l.owners[i] = owner
+	}
+}
+
+// isBlockHeader reports whether tk is a block scalar header, e.g. "|-".
+func isBlockHeader(tk *token.Token) bool {
+	return tk.Type == token.LiteralType || tk.Type == token.FoldedType
+}
+
+// tokenLines returns the number of lines spanned by tk.
+func tokenLines(tk *token.Token) int {
+	return strings.Count(strings.TrimSpace(tk.Origin), "\n") + 1
+}
+
+// blockLines returns the number of lines of the block scalar content tk with
+// the given header. Trailing blank lines are only counted if the header keeps
+// them ("+"), since they are not part of the content otherwise.
+func blockLines(tk, header *token.Token) int {
+	lines := strings.Split(strings.TrimSuffix(tk.Origin, "\n"), "\n")
+	if !strings.Contains(header.Value, "+") {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+
+	return len(lines)
+}
+
+// frame is an open block collection entry: a line, or a node started after
+// an indicator ("- " or "? ") on a line. Lines indented more than a frame are
+// nested in it.
+type frame struct {
+	orig        int  // Original column.
+	col         int  // Formatted column.
+	dash        bool // Sequence entry.
+	seqUnderKey bool // Sequence entry aligned with the key it belongs to.
+}
+
+// format returns the formatted form of content.
+func (f *Formatter) format(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	l := newLayout(content)
+
+	lines := make([]string, len(l.lines))
+	for i, text := range l.lines {
+		lines[i] = f.requote(text, l.quoted[i])
+	}
+
+	cols := f.indentation(l)
+	deltas := make([]int, len(lines))
+
+	for i, text := range lines {
+		switch l.kinds[i] {
+		case lineContent, lineComment:
+			trimmed := strings.TrimLeft(text, " ")
+			deltas[i] = cols[i] - (len(text) - len(trimmed))
+			if l.kinds[i] == lineContent {
+				trimmed = compactIndicators(trimmed, l.tokens[i])
+			}
+
+			lines[i] = strings.Repeat(" ", cols[i]) + strings.TrimRight(trimmed, " \t")
+		case lineContinued:
+			lines[i] = shift(text, deltas[l.owners[i]])
+		case lineBlank:
+			lines[i] = ""
+		}
+	}
+
+	return f.assemble(l, lines)
+}
+
+// indentation returns the formatted column of each content and comment line
+// of l.
+func (f *Formatter) indentation(l *layout) []int {
+	var (
+		cols    = make([]int, len(l.lines))
+		stack   []frame
+		pending []int // Comment lines before the next content line.
+	)
+
+	resolve := func(next, nextOrig, nextCol int, frames []frame) {
+		for _, i := range pending {
+			orig := len(l.lines[i]) - len(strings.TrimLeft(l.lines[i], " "))
+
+			cols[i] = max(orig+nextCol-nextOrig, 0)
+			if next < 0 {
+				cols[i] = orig
+			}
+
+			if orig == nextOrig && next >= 0 {
+				cols[i] = nextCol
+
+				continue
+			}
+
+			for _, fr := range frames {
+				if fr.orig == orig {
+					cols[i] = fr.col
+				}
+			}
+		}
+
+		pending = pending[:0]
+	}
+
+	for i, kind := range l.kinds {
+		switch kind {
+		case lineComment:
+			pending = append(pending, i)
+
+			continue
+		case lineContent:
+		default:
+			continue
+		}
+
+		tks := l.tokens[i]
+		orig := tks[0].Position.Column - 1
+
+		switch tks[0].Type {
+		case token.DocumentHeaderType, token.DocumentEndType, token.DirectiveType:
+			resolve(i, orig, 0, stack)
+
+			stack = stack[:0]
+
+			continue
+		default:
+		}
+
+		frames := append([]frame{}, stack...)
+
+		stack, cols[i] = f.place(stack, orig, tks[0].Type == token.SequenceEntryType)
+		resolve(i, orig, cols[i], frames)
+
+		// Nodes after "- " or "? " open frames one space after the indicator,
+		// see [compactIndicators].
+		col := cols[i]
+
+		for j := 1; j < len(tks) && isIndicator(tks[j-1]) && tks[j].Type != token.CommentType; j++ {
+			col += 2
+			stack = append(stack, frame{
+				orig: tks[j].Position.Column - 1,
+				col:  col,
+				dash: tks[j].Type == token.SequenceEntryType,
+			})
+		}
+	}
+
+	resolve(-1, 0, 0, stack)
+
+	return cols
+}
+
+// place returns the formatted column of a line at the original column orig,
+// and the stack of frames with the line pushed.
+func (f *Formatter) place(stack []frame, orig int, dash bool) ([]frame, int) {
+	for len(stack) > 0 && stack[len(stack)-1].orig > orig {
+		stack = stack[:len(stack)-1]
+	}
+
+	if len(stack) == 0 {
+		return append(stack, frame{orig: orig, dash: dash}), 0
+	}
+
+	top := stack[len(stack)-1]
+
+	if top.orig == orig {
+		switch {
+		case top.seqUnderKey && !dash:
+			// The sequence has ended; the line is a sibling of its key.
+			return f.place(stack[:len(stack)-1], orig, dash)
+		case dash && !top.dash:
+			// A sequence aligned with its key.
+			col := top.col
+			if f.indentSequences {
+				col += f.indent
+			}
+
+			return append(stack, frame{orig: orig, col: col, dash: true, seqUnderKey: true}), col
+		default:
+			stack[len(stack)-1] = frame{orig: orig, col: top.col, dash: dash, seqUnderKey: top.seqUnderKey}
+
+			return stack, top.col
+		}
+	}
+
+	col := top.col + f.indent
+	if dash && !top.dash && !f.indentSequences {
+		col = top.col
+	}
+
+	return append(stack, frame{orig: orig, col: col, dash: dash}), col
+}
+
+// assemble joins the formatted lines, applying the blank line and document
+// start policies.
+func (f *Formatter) assemble(l *layout, lines []string) string {
+	var (
+		first    *token.Token // First token of the first content line.
+		sb       strings.Builder
+		blanks   int
+		wrote    bool
+		addStart bool
+	)
+
+	for i, kind := range l.kinds {
+		if kind == lineContent {
+			first = l.tokens[i][0]
+			addStart = f.documentStart == DocumentStartRequire &&
+				first.Type != token.DocumentHeaderType && first.Type != token.DirectiveType
+
+			break
+		}
+	}
+
+	write := func(text string) {
+		if wrote {
+			for range min(blanks, f.maxBlankLines) {
+				sb.WriteString("\n")
+			}
+		}
+
+		blanks = 0
+		wrote = true
+
+		sb.WriteString(text)
+		sb.WriteString("\n")
+	}
+
+	for i, text := range lines {
+		if l.kinds[i] == lineBlank {
+			blanks++
+
+			continue
+		}
+
+		switch {
+		case addStart:
+			// The marker goes before any leading comments, which would
+			// otherwise form a document of their own.
+			write("---")
+
+			addStart = false
+		case !wrote && f.documentStart == DocumentStartForbid &&
+			l.kinds[i] == lineContent && l.tokens[i][0] == first &&
+			first.Type == token.DocumentHeaderType && len(l.tokens[i]) == 1:
+			continue
+		default:
+		}
+
+		write(text)
+	}
+
+	return sb.String()
+}
+
+// isIndicator reports whether tk is a block sequence entry ("-") or mapping
+// key ("?") indicator.
+func isIndicator(tk *token.Token) bool {
+	return tk.Type == token.SequenceEntryType || tk.Type == token.MappingKeyType
+}
+
+// compactIndicators returns the unindented text of a line with a single space
+// after each of its leading indicators followed by a node, e.g. "- a" for
+// "-   a". tks are the tokens starting on the line.
+func compactIndicators(text string, tks []*token.Token) string {
+	var sb strings.Builder
+
+	for j := 1; j < len(tks) && isIndicator(tks[j-1]) && tks[j].Type != token.CommentType; j++ {
+		indicator, rest, ok := strings.Cut(text, " ")
+		if !ok {
+			break
+		}
+
+		sb.WriteString(indicator + " ")
+		text = strings.TrimLeft(rest, " ")
+	}
+
+	return sb.String() + text
+}
+
+// shift returns text with its indentation changed by delta spaces. Blank
+// lines are kept as is.
+func shift(text string, delta int) string {
+	switch {
+	case delta > 0 && text != "":
+		return strings.Repeat(" ", delta) + text
+	case delta < 0:
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+
+		return text[min(-delta, indent):]
+	default:
+		return text
+	}
+}
+
+// requote returns text with the single-line quoted scalars tks, which start
+// on it, converted to the [QuoteStyle] of f.
+func (f *Formatter) requote(text string, tks []*token.Token) string {
+	if f.quoteStyle == QuotePreserve {
+		return text
+	}
+
+	runes := []rune(text)
+
+	// Replace from the end so that earlier columns stay valid.
+	for i := len(tks) - 1; i >= 0; i-- {
+		tk := tks[i]
+		quoted := []rune(strings.TrimSpace(tk.Origin))
+		start := tk.Position.Column - 1
+		end := start + len(quoted)
+
+		if end > len(runes) || string(runes[start:end]) != string(quoted) {
+			// Multi-line scalars are left as is.
+			continue
+		}
+
+		replacement := []rune(f.quote(tk))
+		runes = append(runes[:start], append(replacement, runes[end:]...)...)
+	}
+
+	return string(runes)
+}
+
+// quote returns the quoted scalar tk in the [QuoteStyle] of f, or as is if
+// the style does not suit its value.
+func (f *Formatter) quote(tk *token.Token) string {
+	v := tk.Value
+	original := strings.TrimSpace(tk.Origin)
+
+	style := f.quoteStyle
+	switch {
+	case style == QuoteSingle && strings.Contains(v, "'") && !strings.Contains(v, `"`):
+		style = QuoteDouble
+	case style == QuoteDouble && strings.Contains(v, `"`) && !strings.Contains(v, "'"):
+		style = QuoteSingle
+	default:
+	}
+
+	switch {
+	case style == QuoteSingle && tk.Type == token.DoubleQuoteType:
+		for _, r := range v {
+			if !unicode.IsPrint(r) {
+				return original
+			}
+		}
+
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case style == QuoteDouble && tk.Type == token.SingleQuoteType:
+		return strconv.Quote(v)
+	default:
+		return original
+	}
+}
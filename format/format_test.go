@@ -0,0 +1,241 @@
+package format_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/format"
+)
+
+func TestFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		input string
+		want  string
+		opts  []format.Option
+	}{
+		"indentation": {
+			input: stringtest.Input(`
+				a:
+				    b: 1
+				    c:
+				        d: 2
+				e:
+				 - f
+				 -   g: 3
+				     h: 4
+			`),
+			want: stringtest.Input(`
+				a:
+				  b: 1
+				  c:
+				    d: 2
+				e:
+				  - f
+				  - g: 3
+				    h: 4
+			`),
+		},
+		"indent width": {
+			input: "a:\n  b:\n    - c\n",
+			want:  "a:\n    b:\n        - c\n",
+			opts:  []format.Option{format.WithIndent(4)},
+		},
+		"indented sequences": {
+			input: stringtest.Input(`
+				a:
+				- b
+				- c:
+				  - d
+				e: 1
+			`),
+			want: stringtest.Input(`
+				a:
+				  - b
+				  - c:
+				      - d
+				e: 1
+			`),
+		},
+		"aligned sequences": {
+			input: stringtest.Input(`
+				a:
+				    - b
+				    - c:
+				        - d
+				e: 1
+			`),
+			want: stringtest.Input(`
+				a:
+				- b
+				- c:
+				  - d
+				e: 1
+			`),
+			opts: []format.Option{format.WithIndentSequences(false)},
+		},
+		"nested sequences": {
+			input: "- - a\n  - b\n-   - c\n",
+			want:  "- - a\n  - b\n- - c\n",
+		},
+		"comments and anchors": {
+			input: stringtest.Input(`
+				# head
+				base: &base # trailing
+				    # about name
+				    name: app
+				    port: 80
+				    # after port
+				# about other
+				other:
+				    <<: *base
+			`),
+			want: stringtest.Input(`
+				# head
+				base: &base # trailing
+				  # about name
+				  name: app
+				  port: 80
+				  # after port
+				# about other
+				other:
+				  <<: *base
+			`),
+		},
+		"block scalars": {
+			input: "a:\n    b: |\n        x\n\n          y\n    c: >-\n        z\n",
+			want:  "a:\n  b: |\n      x\n\n        y\n  c: >-\n      z\n",
+		},
+		"multi-line scalars": {
+			input: "a:\n    b: plain\n      text\n    c: \"quoted\n      text\"\n",
+			want:  "a:\n  b: plain\n    text\n  c: \"quoted\n    text\"\n",
+		},
+		"flow collections": {
+			input: "a:\n    b: [1,\n        2]\n    c: {d: 1}\n",
+			want:  "a:\n  b: [1,\n      2]\n  c: {d: 1}\n",
+		},
+		"double quotes": {
+			input: "a: 'x'\nb: 'it''s'\nc: \"y\"\nd: ['z', \"w\"]\n",
+			want:  "a: \"x\"\nb: \"it's\"\nc: \"y\"\nd: [\"z\", \"w\"]\n",
+			opts:  []format.Option{format.WithQuoteStyle(format.QuoteDouble)},
+		},
+		"single quotes": {
+			input: "a: \"x\"\nb: \"it's\"\nc: \"tab\\t\"\nd: \"say \\\"hi\\\"\"\n",
+			want:  "a: 'x'\nb: \"it's\"\nc: \"tab\\t\"\nd: 'say \"hi\"'\n",
+			opts:  []format.Option{format.WithQuoteStyle(format.QuoteSingle)},
+		},
+		"blank lines": {
+			input: "\n\na: 1\n\n\n\nb: |+\n  x\n\n\n\nc: 2   \n\n\n",
+			want:  "a: 1\n\nb: |+\n  x\n\n\n\nc: 2\n",
+		},
+		"max blank lines": {
+			input: "a: 1\n\n\nb: 2\n",
+			want:  "a: 1\nb: 2\n",
+			opts:  []format.Option{format.WithMaxBlankLines(0)},
+		},
+		"require document start": {
+			input: "# comment\na: 1\n---\nb: 2\n",
+			want:  "---\n# comment\na: 1\n---\nb: 2\n",
+			opts:  []format.Option{format.WithDocumentStart(format.DocumentStartRequire)},
+		},
+		"forbid document start": {
+			input: "---\na: 1\n---\nb:\n    c: 2\n",
+			want:  "a: 1\n---\nb:\n  c: 2\n",
+			opts:  []format.Option{format.WithDocumentStart(format.DocumentStartForbid)},
+		},
+		"key order": {
+			input: "b:\n    d: 1\n    # about c\n    c: 2\na: 3\n",
+			want:  "a: 3\nb:\n  # about c\n  c: 2\n  d: 1\n",
+			opts:  []format.Option{format.WithKeyOrder(niceyaml.AlphabeticalKeyOrder())},
+		},
+		"line endings": {
+			input: "a:\r\n    b: 1\r\n",
+			want:  "a:\n  b: 1\n",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(tc.input, niceyaml.WithName("test.yaml"))
+
+			got, err := format.New(tc.opts...).Format(source)
+			require.NoError(t, err)
+			assert.Equal(t, strings.TrimSuffix(tc.want, "\n"), got.Content())
+			assert.Equal(t, "test.yaml", got.Name())
+
+			again, err := format.New(tc.opts...).Format(got)
+			require.NoError(t, err)
+			assert.Equal(t, got.Content(), again.Content(), "formatting is not idempotent")
+		})
+	}
+}
+
+func TestFormatter_Format_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := format.New().Format(niceyaml.NewSourceFromString("a: [1\n"))
+	require.Error(t, err)
+
+	// The lexer does not keep the original text of escapes.
+	_, err = format.New().Format(niceyaml.NewSourceFromString("a: \"\\u0041\"\n"))
+	require.ErrorIs(t, err, format.ErrContentChanged)
+}
+
+func TestFormatter_Check(t *testing.T) {
+	t.Parallel()
+
+	f := format.New()
+
+	result, err := f.Check(niceyaml.NewSourceFromString("a: 1\nb:\n  c: 2\n"))
+	require.NoError(t, err)
+
+	added, removed := result.Stats()
+	assert.Zero(t, added+removed)
+
+	result, err = f.Check(niceyaml.NewSourceFromString("a: 1\nb:\n    c: 2\n"))
+	require.NoError(t, err)
+
+	added, removed = result.Stats()
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, "a: 1\nb:\n    c: 2", result.Before().Content())
+	assert.Equal(t, "a: 1\nb:\n  c: 2", result.After().Content())
+}
+
+func TestParseQuoteStyle(t *testing.T) {
+	t.Parallel()
+
+	for _, q := range []format.QuoteStyle{format.QuotePreserve, format.QuoteSingle, format.QuoteDouble} {
+		got, err := format.ParseQuoteStyle(q.String())
+		require.NoError(t, err)
+		assert.Equal(t, q, got)
+	}
+
+	_, err := format.ParseQuoteStyle("backtick")
+	require.ErrorIs(t, err, format.ErrUnknownQuoteStyle)
+}
+
+func TestParseDocumentStart(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []format.DocumentStart{
+		format.DocumentStartPreserve,
+		format.DocumentStartRequire,
+		format.DocumentStartForbid,
+	} {
+		got, err := format.ParseDocumentStart(d.String())
+		require.NoError(t, err)
+		assert.Equal(t, d, got)
+	}
+
+	_, err := format.ParseDocumentStart("always")
+	require.ErrorIs(t, err, format.ErrUnknownDocumentStart)
+}
@@ -0,0 +1,30 @@
+// Package html renders YAML as HTML, for publishing validation results and
+// diffs as static pages.
+//
+// A [niceyaml.Printer] produces ANSI-styled text for terminals. [Printer]
+// renders the same [niceyaml.LineIterator] input, including spans, gutters,
+// overlays, and annotations, as semantic HTML instead. Rather than inlining
+// colors, each element carries a CSS class named after its [style.Style]
+// constant (e.g. "nameTag" for mapping keys, "genericHighlight" for search
+// matches), so the look is defined entirely by a stylesheet:
+//
+//	printer := html.NewPrinter()
+//	page := printer.Print(source)
+//	css := html.Stylesheet(theme.Charm(), html.DefaultClass)
+//
+// # Structure
+//
+// Output is a single <pre> element with the container class (see
+// [WithClass]). Each rendered line is a <span class="line"> holding an
+// optional gutter and the line content. Tokens are wrapped in spans with
+// their style class, and overlays are nested spans with the overlay kind as
+// class. Diff lines carry [style.GenericInserted] or [style.GenericDeleted]
+// on their content, and annotation lines have the "annotation" class.
+//
+// # Stylesheets
+//
+// [Stylesheet] generates CSS rules from any [style.Styles], such as a theme
+// from [go.jacobcolvin.com/niceyaml/style/theme]. Rules are scoped to the
+// container class, so pages can include several themes, or mix rendered YAML
+// with other content, without conflicts.
+package html
@@ -0,0 +1,71 @@
+package html
+
+import (
+	"fmt"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+// GutterFunc returns the HTML gutter for a line based on
+// [niceyaml.GutterContext].
+//
+// The [niceyaml.GutterContext.Styles] field is not set, since styling is left
+// to the stylesheet.
+//
+// Available gutters:
+//   - [DefaultGutter]
+//   - [DiffGutter]
+//   - [LineNumberGutter]
+//   - [NoGutter]
+type GutterFunc func(niceyaml.GutterContext) string
+
+// DefaultGutter creates a [GutterFunc] that renders both line numbers and diff
+// markers.
+//
+// This is the default gutter used by [NewPrinter].
+func DefaultGutter() GutterFunc {
+	return func(ctx niceyaml.GutterContext) string {
+		return renderLineNumber(ctx) + renderDiffMarker(ctx)
+	}
+}
+
+// DiffGutter creates a [GutterFunc] that renders diff-style markers only
+// (" ", "+", "-"), with the "diffMarker" class plus [style.GenericInserted] or
+// [style.GenericDeleted].
+func DiffGutter() GutterFunc {
+	return renderDiffMarker
+}
+
+// LineNumberGutter creates a [GutterFunc] that renders line numbers only,
+// with the "lineNumber" class.
+func LineNumberGutter() GutterFunc {
+	return renderLineNumber
+}
+
+// NoGutter returns a [GutterFunc] that returns an empty string for all lines.
+func NoGutter() GutterFunc {
+	return func(niceyaml.GutterContext) string { return "" }
+}
+
+// renderLineNumber renders the line number portion of a gutter.
+func renderLineNumber(ctx niceyaml.GutterContext) string {
+	if ctx.Flag == line.FlagAnnotation {
+		return `<span class="lineNumber">     </span>`
+	}
+
+	return fmt.Sprintf(`<span class="lineNumber">%4d </span>`, ctx.Number)
+}
+
+// renderDiffMarker renders the diff marker portion of a gutter.
+func renderDiffMarker(ctx niceyaml.GutterContext) string {
+	switch ctx.Flag {
+	case line.FlagInserted:
+		return `<span class="diffMarker ` + className(style.GenericInserted) + `">+</span>`
+	case line.FlagDeleted:
+		return `<span class="diffMarker ` + className(style.GenericDeleted) + `">-</span>`
+	default:
+		return `<span class="diffMarker"> </span>`
+	}
+}
@@ -0,0 +1,382 @@
+package html
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/internal/ansi"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/tokens"
+)
+
+// DefaultClass is the default class of the container element.
+const DefaultClass = "niceyaml"
+
+// Printer renders YAML as HTML with CSS classes named after [style.Style]
+// constants.
+//
+// It mirrors [niceyaml.Printer]: it accepts any [niceyaml.LineIterator],
+// renders the given [position.Span]s, and supports gutters, overlays,
+// annotations, and folds. Word wrapping is left to CSS.
+//
+// Create instances with [NewPrinter].
+type Printer struct {
+	folds              *niceyaml.Folds
+	gutterFunc         GutterFunc
+	annotationFunc     niceyaml.AnnotationFunc
+	class              string
+	annotationsEnabled bool
+}
+
+// NewPrinter creates a new [*Printer].
+// By default it uses [DefaultClass], [DefaultGutter], and
+// [niceyaml.DefaultAnnotation].
+func NewPrinter(opts ...Option) *Printer {
+	p := &Printer{
+		class:              DefaultClass,
+		gutterFunc:         DefaultGutter(),
+		annotationFunc:     niceyaml.DefaultAnnotation(),
+		annotationsEnabled: true,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Option configures a [Printer].
+//
+// Available options:
+//   - [WithClass]
+//   - [WithGutter]
+//   - [WithAnnotationFunc]
+//   - [WithFolds]
+type Option func(*Printer)
+
+// WithClass is an [Option] that sets the class of the container element.
+// Use the same class with [Stylesheet].
+func WithClass(class string) Option {
+	return func(p *Printer) {
+		p.class = class
+	}
+}
+
+// WithGutter is an [Option] that sets the [GutterFunc] for rendering.
+// By default, [DefaultGutter] is used.
+func WithGutter(fn GutterFunc) Option {
+	return func(p *Printer) {
+		p.gutterFunc = fn
+	}
+}
+
+// WithAnnotationFunc is an [Option] that sets the [niceyaml.AnnotationFunc]
+// for rendering annotations.
+//
+// The result is treated as text: ANSI sequences are removed, and HTML is
+// escaped. By default, [niceyaml.DefaultAnnotation] is used.
+func WithAnnotationFunc(fn niceyaml.AnnotationFunc) Option {
+	return func(p *Printer) {
+		p.annotationFunc = fn
+	}
+}
+
+// WithFolds is an [Option] that sets the [niceyaml.Folds] used to collapse
+// outline nodes when printing.
+func WithFolds(f *niceyaml.Folds) Option {
+	return func(p *Printer) {
+		p.folds = f
+	}
+}
+
+// SetAnnotations sets whether annotations are rendered.
+// Defaults to true.
+func (p *Printer) SetAnnotations(enabled bool) {
+	p.annotationsEnabled = enabled
+}
+
+// Print renders any [niceyaml.LineIterator] as an HTML <pre> element.
+// It renders lines within the given [position.Span]s, in the supplied order.
+// If no [position.Span]s are provided, all lines are rendered.
+func (p *Printer) Print(lines niceyaml.LineIterator, spans ...position.Span) string {
+	if len(spans) == 0 {
+		spans = []position.Span{position.NewSpan(0, lines.Len())}
+	}
+
+	var rows []string
+
+	for _, span := range spans {
+		rows = p.appendSpan(rows, lines, span)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(`<pre class="`)
+	sb.WriteString(className(p.class))
+	sb.WriteString(`"><code>`)
+	sb.WriteString(strings.Join(rows, "\n"))
+	sb.WriteString("</code></pre>")
+
+	return sb.String()
+}
+
+// appendSpan appends the rendered rows of the lines in span to rows.
+func (p *Printer) appendSpan(rows []string, t niceyaml.LineIterator, span position.Span) []string {
+	if t.IsEmpty() {
+		return rows
+	}
+
+	totalLines := t.Len()
+
+	var folded position.Spans
+	if p.folds != nil {
+		folded = p.folds.Spans()
+	}
+
+	for pos, ln := range t.AllLines(span) {
+		for len(folded) > 0 && folded[0].End <= pos.Line {
+			folded = folded[1:]
+		}
+
+		foldLen := 0
+
+		if len(folded) > 0 && folded[0].Contains(pos.Line) {
+			if folded[0].Start != pos.Line {
+				continue // Hidden by a fold.
+			}
+
+			foldLen = folded[0].Len()
+		}
+
+		ctx := niceyaml.GutterContext{
+			Index:      pos.Line,
+			Number:     ln.Number(),
+			TotalLines: totalLines,
+			Flag:       ln.Flag,
+			Folded:     foldLen > 0,
+		}
+
+		if row := p.renderAnnotation(ln, ctx, line.Above); row != "" {
+			rows = append(rows, row)
+		}
+
+		var sb strings.Builder
+
+		fmt.Fprintf(&sb, `<span class="line" data-line="%d">`, ctx.Number)
+		sb.WriteString(p.gutter(ctx))
+
+		switch ln.Flag {
+		case line.FlagDeleted, line.FlagInserted:
+			kind := style.GenericInserted
+			if ln.Flag == line.FlagDeleted {
+				kind = style.GenericDeleted
+			}
+
+			writeSpan(&sb, kind, func() {
+				writeText(&sb, ln.Content(), 0, ln.Overlays)
+			})
+
+		default:
+			writeTokens(&sb, ln)
+		}
+
+		if foldLen > 0 {
+			writeSpan(&sb, style.TextSubtle, func() {
+				sb.WriteString(escape(fmt.Sprintf(" … (%d lines)", foldLen)))
+			})
+		}
+
+		sb.WriteString("</span>")
+		rows = append(rows, sb.String())
+
+		if row := p.renderAnnotation(ln, ctx, line.Below); row != "" {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// renderAnnotation renders the annotations of ln at relPos as a row, or
+// returns an empty string if there are none.
+func (p *Printer) renderAnnotation(ln line.Line, ctx niceyaml.GutterContext, relPos line.RelativePosition) string {
+	if !p.annotationsEnabled || p.annotationFunc == nil {
+		return ""
+	}
+
+	anns := ln.Annotations.FilterPosition(relPos)
+	if len(anns) == 0 {
+		return ""
+	}
+
+	content := p.annotationFunc(niceyaml.AnnotationContext{
+		Annotations: anns,
+		Position:    relPos,
+		Styles:      style.Styles{},
+	})
+	if content == "" {
+		return ""
+	}
+
+	ctx.Flag = line.FlagAnnotation
+
+	var sb strings.Builder
+
+	where := "above"
+	if relPos == line.Below {
+		where = "below"
+	}
+
+	fmt.Fprintf(&sb, `<span class="line annotation %s">`, where)
+	sb.WriteString(p.gutter(ctx))
+	writeSpan(&sb, style.Comment, func() {
+		sb.WriteString(escape(xansi.Strip(content)))
+	})
+	sb.WriteString("</span>")
+
+	return sb.String()
+}
+
+// gutter renders the gutter for ctx, or an empty string if there is no
+// [GutterFunc].
+func (p *Printer) gutter(ctx niceyaml.GutterContext) string {
+	if p.gutterFunc == nil {
+		return ""
+	}
+
+	return p.gutterFunc(ctx)
+}
+
+// writeTokens writes the tokens of ln, each wrapped in a span with its style
+// class. Leading whitespace of each token is written unwrapped.
+func writeTokens(sb *strings.Builder, ln line.Line) {
+	col := 0
+
+	for _, tk := range ln.Tokens() {
+		origin := strings.TrimSuffix(tk.Origin, "\n")
+		origin = strings.TrimSuffix(origin, "\r")
+
+		sep := leadingWhitespace(origin, tokens.ValueOffset(tk))
+		if sep != "" {
+			writeText(sb, sep, col, ln.Overlays)
+			col += utf8.RuneCountInString(sep)
+			origin = origin[len(sep):]
+		}
+
+		if origin == "" {
+			continue
+		}
+
+		start := col
+		writeSpan(sb, tokens.TypeStyle(tk), func() {
+			writeText(sb, origin, start, ln.Overlays)
+		})
+
+		col += utf8.RuneCountInString(origin)
+	}
+}
+
+// writeText writes text starting at column col, wrapping the parts covered by
+// overlays in spans with the overlay kinds as classes.
+func writeText(sb *strings.Builder, text string, col int, overlays line.Overlays) {
+	runes := []rune(text)
+	cols := position.NewSpan(col, col+len(runes))
+
+	boundaries := []int{cols.Start, cols.End}
+
+	for _, o := range overlays {
+		if !o.Cols.Overlaps(cols) {
+			continue
+		}
+
+		for _, b := range []int{o.Cols.Start, o.Cols.End} {
+			if b > cols.Start && b < cols.End {
+				boundaries = append(boundaries, b)
+			}
+		}
+	}
+
+	slices.Sort(boundaries)
+	boundaries = slices.Compact(boundaries)
+
+	for i := range len(boundaries) - 1 {
+		start, end := boundaries[i], boundaries[i+1]
+		part := escape(string(runes[start-col : end-col]))
+
+		var kinds []string
+
+		for _, o := range overlays {
+			if o.Cols.Contains(start) && !slices.Contains(kinds, className(o.Kind)) {
+				kinds = append(kinds, className(o.Kind))
+			}
+		}
+
+		if len(kinds) == 0 {
+			sb.WriteString(part)
+
+			continue
+		}
+
+		fmt.Fprintf(sb, `<span class="%s">%s</span>`, strings.Join(kinds, " "), part)
+	}
+}
+
+// writeSpan writes a span with the class for s around the content written by
+// fn.
+func writeSpan(sb *strings.Builder, s style.Style, fn func()) {
+	sb.WriteString(`<span class="`)
+	sb.WriteString(className(s))
+	sb.WriteString(`">`)
+	fn()
+	sb.WriteString("</span>")
+}
+
+// leadingWhitespace returns the leading whitespace of s, up to maxBytes.
+// Returns an empty string if the prefix contains non-whitespace characters.
+func leadingWhitespace(s string, maxBytes int) string {
+	if maxBytes <= 0 || maxBytes > len(s) {
+		return ""
+	}
+
+	prefix := s[:maxBytes]
+	if strings.TrimLeft(prefix, " \t") != "" {
+		return ""
+	}
+
+	return prefix
+}
+
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&#34;",
+	"'", "&#39;",
+)
+
+// escape makes control characters visible (see [ansi.Escape]) and escapes
+// HTML special characters.
+func escape(s string) string {
+	return htmlEscaper.Replace(ansi.Escape(s))
+}
+
+// className returns s with characters that are not valid in CSS class names
+// replaced by "-".
+func className(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
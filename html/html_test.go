@@ -0,0 +1,205 @@
+package html_test
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/html"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+// rows returns the rendered rows of a <pre> element, without the wrapper.
+func rows(t *testing.T, out string) []string {
+	t.Helper()
+
+	require.True(t, strings.HasPrefix(out, `<pre class="niceyaml"><code>`), out)
+	require.True(t, strings.HasSuffix(out, "</code></pre>"), out)
+
+	out = strings.TrimPrefix(out, `<pre class="niceyaml"><code>`)
+	out = strings.TrimSuffix(out, "</code></pre>")
+
+	return strings.Split(out, "\n")
+}
+
+func TestPrinter_Print(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tokens", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			key: "a<b"
+			list: [1, true]
+		`))
+
+		p := html.NewPrinter(html.WithGutter(html.NoGutter()))
+
+		want := []string{
+			`<span class="line" data-line="1">` +
+				`<span class="nameTag">key</span>` +
+				`<span class="punctuationMappingValue">:</span>` +
+				`<span class="literalStringDouble"> &#34;a&lt;b&#34;</span></span>`,
+			`<span class="line" data-line="2">` +
+				`<span class="nameTag">list</span>` +
+				`<span class="punctuationMappingValue">:</span> ` +
+				`<span class="punctuationSequenceStart">[</span>` +
+				`<span class="literalNumberInteger">1</span>` +
+				`<span class="punctuationCollectEntry">,</span> ` +
+				`<span class="literalBoolean">true</span>` +
+				`<span class="punctuationSequenceEnd">]</span></span>`,
+		}
+		assert.Equal(t, want, rows(t, p.Print(source)))
+	})
+
+	t.Run("gutter and spans", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("a: 1\nb: 2\nc: 3\n")
+		p := html.NewPrinter()
+
+		got := rows(t, p.Print(source, position.NewSpan(2, 3)))
+
+		want := []string{
+			`<span class="line" data-line="3">` +
+				`<span class="lineNumber">   3 </span><span class="diffMarker"> </span>` +
+				`<span class="nameTag">c</span>` +
+				`<span class="punctuationMappingValue">:</span> ` +
+				`<span class="literalNumberInteger">3</span></span>`,
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("overlays", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("key: value\n")
+		source.AddOverlay(style.GenericHighlight,
+			position.NewRange(position.New(0, 2), position.New(0, 7)))
+
+		p := html.NewPrinter(html.WithGutter(html.NoGutter()))
+
+		want := []string{
+			`<span class="line" data-line="1">` +
+				`<span class="nameTag">ke<span class="genericHighlight">y</span></span>` +
+				`<span class="punctuationMappingValue"><span class="genericHighlight">:</span></span>` +
+				`<span class="genericHighlight"> </span>` +
+				`<span class="literalString"><span class="genericHighlight">va</span>lue</span></span>`,
+		}
+		assert.Equal(t, want, rows(t, p.Print(source)))
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("key: value\n")
+		source.Line(0).AddAnnotation(
+			line.Annotation{Content: "<bad>", Position: line.Below, Col: 5},
+			line.Annotation{Content: "header", Position: line.Above},
+		)
+
+		p := html.NewPrinter(html.WithGutter(html.LineNumberGutter()))
+
+		got := rows(t, p.Print(source))
+		require.Len(t, got, 3)
+
+		assert.Equal(t,
+			`<span class="line annotation above"><span class="lineNumber">     </span>`+
+				`<span class="comment">header</span></span>`,
+			got[0])
+		assert.Equal(t,
+			`<span class="line annotation below"><span class="lineNumber">     </span>`+
+				`<span class="comment">     ^ &lt;bad&gt;</span></span>`,
+			got[2])
+
+		p.SetAnnotations(false)
+		assert.Len(t, rows(t, p.Print(source)), 1)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		t.Parallel()
+
+		before := niceyaml.NewRevision(niceyaml.NewSourceFromString("a: 1\n"))
+		after := before.Append(niceyaml.NewSourceFromString("a: 2\n"))
+		unified := niceyaml.Diff(before, after).Unified()
+
+		p := html.NewPrinter(html.WithGutter(html.DiffGutter()))
+
+		want := []string{
+			`<span class="line" data-line="1">` +
+				`<span class="diffMarker genericDeleted">-</span>` +
+				`<span class="genericDeleted">a: 1</span></span>`,
+			`<span class="line" data-line="1">` +
+				`<span class="diffMarker genericInserted">+</span>` +
+				`<span class="genericInserted">a: 2</span></span>`,
+		}
+		assert.Equal(t, want, rows(t, p.Print(unified)))
+	})
+
+	t.Run("folds", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("a:\n  b: 1\n  c: 2\n")
+		o, err := source.Outline()
+		require.NoError(t, err)
+
+		folds := niceyaml.NewFolds()
+		folds.Fold(o.Lookup("$.a"))
+
+		p := html.NewPrinter(html.WithGutter(html.NoGutter()), html.WithFolds(folds))
+
+		want := []string{
+			`<span class="line" data-line="1">` +
+				`<span class="nameTag">a</span>` +
+				`<span class="punctuationMappingValue">:</span>` +
+				`<span class="textSubtle"> … (3 lines)</span></span>`,
+		}
+		assert.Equal(t, want, rows(t, p.Print(source)))
+	})
+
+	t.Run("custom class", func(t *testing.T) {
+		t.Parallel()
+
+		p := html.NewPrinter(html.WithClass("my yaml"))
+		out := p.Print(niceyaml.NewSourceFromString(""))
+
+		assert.Equal(t, `<pre class="my-yaml"><code></code></pre>`, out)
+	})
+}
+
+func TestStylesheet(t *testing.T) {
+	t.Parallel()
+
+	styles := style.NewStyles(
+		lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ffffff")).
+			Background(lipgloss.Color("#000000")),
+		style.Set(style.Comment, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)),
+		style.Set(style.NameTag, lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Bold(true)),
+	)
+
+	css := html.Stylesheet(styles, "doc")
+	lines := strings.Split(strings.TrimSuffix(css, "\n"), "\n")
+
+	assert.Equal(t,
+		".doc { font-family: monospace; color: #ffffff; background-color: #000000; }",
+		lines[0])
+	assert.Contains(t, lines,
+		".doc .comment { color: #888888; font-style: italic; }")
+	assert.Contains(t, lines,
+		".doc .nameTag { color: #ff0000; font-weight: bold; }")
+	assert.Contains(t, lines,
+		".doc .literalString { color: #ffffff; background-color: #000000; }",
+		"inherited styles are complete")
+	assert.Equal(t, []string{
+		".doc .lineNumber { color: #888888; user-select: none; }",
+		".doc .diffMarker { user-select: none; }",
+	}, lines[len(lines)-2:])
+}
@@ -0,0 +1,84 @@
+package html
+
+import (
+	"slices"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+// Stylesheet generates CSS rules for the given [style.Styles], scoped to
+// elements with the given container class (see [WithClass]).
+//
+// The [style.Text] style applies to the container itself. Every other
+// [style.Style] in styles becomes a rule for its class name, in sorted order.
+// Since [style.NewStyles] pre-computes inheritance, each rule is complete on
+// its own. Styles that set no supported properties are omitted. See
+// [style.EncodeCSS] for the supported properties.
+//
+// Rules for the gutter classes "lineNumber" and "diffMarker" are also
+// included: they take the [style.Comment] color, and are excluded from text
+// selection.
+func Stylesheet(styles style.Styles, class string) string {
+	scope := "." + className(class)
+
+	var sb strings.Builder
+
+	rule := func(selector, decls string) {
+		if decls == "" {
+			return
+		}
+
+		sb.WriteString(selector)
+		sb.WriteString(" { ")
+		sb.WriteString(decls)
+		sb.WriteString("; }\n")
+	}
+
+	rule(scope, join("font-family: monospace", style.EncodeCSS(*styles.Style(style.Text))))
+
+	keys := make([]style.Style, 0, len(styles))
+	for s := range styles {
+		if s != style.Text {
+			keys = append(keys, s)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, s := range keys {
+		rule(scope+" ."+className(s), style.EncodeCSS(*styles.Style(s)))
+	}
+
+	comment := style.EncodeCSS(styles.Style(style.Text).
+		Foreground(styles.Style(style.Comment).GetForeground()).
+		UnsetBackground())
+	rule(scope+" .lineNumber", join(onlyColor(comment), "user-select: none"))
+	rule(scope+" .diffMarker", "user-select: none")
+
+	return sb.String()
+}
+
+// join joins non-empty CSS declarations.
+func join(decls ...string) string {
+	var parts []string
+
+	for _, d := range decls {
+		if d != "" {
+			parts = append(parts, d)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// onlyColor returns the "color" declaration from decls, if any.
+func onlyColor(decls string) string {
+	for d := range strings.SplitSeq(decls, "; ") {
+		if strings.HasPrefix(d, "color: ") {
+			return d
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,443 @@
+package niceyaml
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"go.jacobcolvin.com/niceyaml/internal/ansi"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+// Label marks a range of the source of an [Error] with a message.
+//
+// Each label is highlighted across its full [position.Range] and underlined
+// below its line, with its message next to the underline. Ranges that span
+// multiple lines are connected by arrows in a margin next to the gutter, with
+// the message below the last line:
+//
+//	3  ╭─▶ spec:
+//	4  │     replicas: three
+//	   ╰── in this spec
+//
+// The primary label marks the cause of an error; secondary labels mark
+// related locations. Primary labels are underlined with "^", and secondary
+// labels with "-".
+//
+// Add labels to an [Error] with [WithLabels].
+// Create instances with [NewPrimaryLabel] or [NewSecondaryLabel].
+type Label struct {
+	// Message is shown next to the label. It may be empty.
+	Message string
+	// Style is used to highlight the range and render the label.
+	Style style.Style
+	// Range is the labeled range of the source.
+	Range position.Range
+	// Primary is true for the label marking the cause of an error.
+	Primary bool
+}
+
+// NewPrimaryLabel creates a primary [Label] for the given range, styled with
+// [style.GenericError].
+func NewPrimaryLabel(r position.Range, msg string) Label {
+	return Label{Message: msg, Range: r, Style: style.GenericError, Primary: true}
+}
+
+// NewSecondaryLabel creates a secondary [Label] for the given range, styled
+// with [style.TextAccent].
+func NewSecondaryLabel(r position.Range, msg string) Label {
+	return Label{Message: msg, Range: r, Style: style.TextAccent}
+}
+
+// annotation returns the [line.Annotation] rendering l, which belongs to the
+// last line of its range.
+func (l Label) annotation() line.Annotation {
+	marker := '-'
+	if l.Primary {
+		marker = '^'
+	}
+
+	ann := line.Annotation{
+		Content:  l.Message,
+		Position: line.Below,
+		Col:      l.Range.Start.Col,
+		Kind:     l.Style,
+		Marker:   marker,
+	}
+
+	if l.Range.Start.Line == l.Range.End.Line {
+		ann.Cols = position.NewSpan(l.Range.Start.Col, max(l.Range.End.Col, l.Range.Start.Col+1))
+	} else {
+		ann.Lines = position.NewSpan(l.Range.Start.Line, l.Range.End.Line+1)
+		ann.Col = 0
+	}
+
+	return ann
+}
+
+// marginRow identifies the kind of row a label margin is rendered for.
+type marginRow int
+
+const (
+	// Content row of a line.
+	marginLine marginRow = iota
+	// Wrapped continuation of a content row.
+	marginSoft
+	// Annotation row above a line.
+	marginAbove
+	// Annotation row below a line.
+	marginBelow
+)
+
+// labelMargin lays out the arrows connecting multi-line labels, which are
+// drawn in a margin between the gutter and the line content.
+//
+// Each multi-line label is assigned a column of the margin, shared by labels
+// whose lines do not overlap. When no multi-line labels are printed, the
+// margin is empty.
+type labelMargin struct {
+	p      *Printer
+	ranges []marginRange
+	cols   int
+}
+
+// marginRange is a multi-line label assigned to a column of a [labelMargin].
+type marginRange struct {
+	kind  style.Style
+	lines position.Span
+	// Line index and annotation index of the label.
+	owner, index int
+	col          int
+}
+
+// last returns the index of the last line of r.
+func (r marginRange) last() int {
+	return r.lines.End - 1
+}
+
+// newLabelMargin lays out the multi-line labels in the given spans of t.
+func (p *Printer) newLabelMargin(t LineIterator, spans []position.Span) *labelMargin {
+	m := &labelMargin{p: p}
+	if !p.annotationsEnabled || t.IsEmpty() {
+		return m
+	}
+
+	for _, span := range spans {
+		for pos, ln := range t.AllLines(span) {
+			for i, ann := range ln.Annotations {
+				if ann.Position == line.Below && ann.Lines.Len() > 1 {
+					m.ranges = append(m.ranges, marginRange{
+						kind:  ann.Kind,
+						lines: position.NewSpan(ann.Lines.Start, min(ann.Lines.End, pos.Line+1)),
+						owner: pos.Line,
+						index: i,
+					})
+				}
+			}
+		}
+	}
+
+	// Outer labels get the leftmost columns.
+	slices.SortStableFunc(m.ranges, func(a, b marginRange) int {
+		return cmp.Or(cmp.Compare(a.lines.Start, b.lines.Start), cmp.Compare(b.lines.End, a.lines.End))
+	})
+
+	var colEnds []int
+
+	for i, r := range m.ranges {
+		col := slices.IndexFunc(colEnds, func(end int) bool { return end <= r.lines.Start })
+		if col < 0 {
+			col = len(colEnds)
+			colEnds = append(colEnds, 0)
+		}
+
+		colEnds[col] = r.lines.End
+		m.ranges[i].col = col
+	}
+
+	m.cols = len(colEnds)
+
+	return m
+}
+
+// width returns the width of the margin: one cell per column, followed by an
+// arrow and a space.
+func (m *labelMargin) width() int {
+	if m.cols == 0 {
+		return 0
+	}
+
+	return m.cols + 3
+}
+
+// find returns the range of the label at the given annotation index of the
+// given line.
+func (m *labelMargin) find(owner, index int) (marginRange, bool) {
+	for _, r := range m.ranges {
+		if r.owner == owner && r.index == index {
+			return r, true
+		}
+	}
+
+	return marginRange{}, false
+}
+
+// render renders the margin for a row of the line at idx.
+//
+// If closing is non-nil, the row belongs to the labels ending at idx: the
+// closing label's column gets a corner if corner is true, and labels ending at
+// idx in the same or later columns are already closed.
+func (m *labelMargin) render(idx int, row marginRow, closing *marginRange, corner bool) string {
+	if m.cols == 0 {
+		return ""
+	}
+
+	type cell struct {
+		kind style.Style
+		r    rune
+	}
+
+	cells := make([]cell, m.width())
+	for i := range cells {
+		cells[i].r = ' '
+	}
+
+	for _, r := range m.ranges {
+		var c rune
+
+		switch row {
+		case marginLine:
+			switch idx {
+			case r.lines.Start:
+				c = '╭'
+			case r.last():
+				c = '├'
+			default:
+				if r.lines.Contains(idx) {
+					c = '│'
+				}
+			}
+
+		case marginSoft, marginBelow:
+			if !r.lines.Contains(idx) {
+				break
+			}
+
+			switch {
+			case closing != nil && r.col == closing.col && r.last() == idx && corner:
+				c = '╰'
+			case closing != nil && r.col >= closing.col && r.last() == idx:
+				// Already closed.
+			default:
+				c = '│'
+			}
+
+		case marginAbove:
+			if idx > r.lines.Start && r.lines.Contains(idx) {
+				c = '│'
+			}
+		}
+
+		if c != 0 {
+			cells[r.col] = cell{kind: r.kind, r: c}
+		}
+	}
+
+	// Draw arrows from corners to the content, starting with the leftmost.
+	for col := range m.cols {
+		kind := cells[col].kind
+
+		var tail string
+
+		switch cells[col].r {
+		case '╭', '├':
+			tail = "─▶ "
+		case '╰':
+			tail = "── "
+		default:
+			continue
+		}
+
+		for i := col + 1; i < m.cols; i++ {
+			if cells[i].r == ' ' {
+				cells[i] = cell{kind: kind, r: '─'}
+			}
+		}
+
+		for i, r := range []rune(tail) {
+			cells[m.cols+i] = cell{kind: kind, r: r}
+		}
+
+		break
+	}
+
+	var (
+		sb   strings.Builder
+		run  strings.Builder
+		kind style.Style
+	)
+
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+
+		s := m.p.styles.Style(style.Text)
+		if kind != "" || strings.TrimSpace(run.String()) != "" {
+			s = m.p.labelStyle(kind)
+		}
+
+		sb.WriteString(s.Render(run.String()))
+		run.Reset()
+	}
+
+	for i, c := range cells {
+		k := c.kind
+		if c.r == ' ' {
+			k = ""
+		}
+
+		if i > 0 && k != kind {
+			flush()
+		}
+
+		kind = k
+
+		run.WriteRune(c.r)
+	}
+
+	flush()
+
+	return sb.String()
+}
+
+// labelStyle returns the style used to render labels of the given kind: the
+// [style.Text] style with the foreground of kind, or its background if kind
+// has no foreground. Defaults to [style.GenericError].
+func (p *Printer) labelStyle(kind style.Style) *lipgloss.Style {
+	if kind == "" {
+		kind = style.GenericError
+	}
+
+	ks := p.styles.Style(kind)
+
+	fg := ks.GetForeground()
+	if _, ok := fg.(lipgloss.NoColor); ok || fg == nil {
+		fg = ks.GetBackground()
+	}
+
+	s := p.styles.Style(style.Text).Foreground(fg)
+
+	return &s
+}
+
+// renderLabels renders the rows of the given labels of the line at pos.
+//
+// Single-line labels are rendered first, ordered by column, followed by the
+// messages of multi-line labels, innermost first.
+func (p *Printer) renderLabels(
+	labels []labelAnnotation,
+	pos position.Position,
+	relPos line.RelativePosition,
+	margin *labelMargin,
+	gutterCtx GutterContext,
+	gutterWidth int,
+) []string {
+	type closingLabel struct {
+		ann line.Annotation
+		r   marginRange
+	}
+
+	var (
+		single  []line.Annotation
+		closing []closingLabel
+	)
+
+	for _, l := range labels {
+		if r, ok := margin.find(pos.Line, l.index); ok && relPos == line.Below {
+			closing = append(closing, closingLabel{ann: l.ann, r: r})
+		} else {
+			single = append(single, l.ann)
+		}
+	}
+
+	slices.SortStableFunc(single, func(a, b line.Annotation) int {
+		return cmp.Compare(a.Cols.Start, b.Cols.Start)
+	})
+	slices.SortStableFunc(closing, func(a, b closingLabel) int {
+		return cmp.Compare(b.r.col, a.r.col)
+	})
+
+	row := marginAbove
+	if relPos == line.Below {
+		row = marginBelow
+	}
+
+	var rows []string
+
+	commentStyle := p.styles.Style(style.Comment)
+
+	for _, ann := range single {
+		marker := ann.Marker
+		if marker == 0 {
+			marker = '^'
+		}
+
+		text := strings.Repeat(string(marker), max(1, ann.Cols.Len()))
+		if ann.Content != "" {
+			text += " " + ann.Content
+		}
+
+		padding := strings.Repeat(" ", max(0, ann.Cols.Start))
+		continuation := strings.Repeat(" ", max(0, ann.Cols.Start)+max(1, ann.Cols.Len())+1)
+		labelStyle := p.labelStyle(ann.Kind)
+
+		for j, subLine := range p.wrapContent(padding+text, gutterWidth) {
+			ctx := gutterCtx
+			ctx.Soft = j > 0
+
+			var sb strings.Builder
+
+			sb.WriteString(p.gutterFunc(ctx))
+			sb.WriteString(margin.render(pos.Line, row, nil, false))
+
+			if j > 0 {
+				sb.WriteString(commentStyle.Render(continuation))
+			} else {
+				subLine = strings.TrimPrefix(subLine, padding)
+				sb.WriteString(commentStyle.Render(padding))
+			}
+
+			sb.WriteString(labelStyle.Render(ansi.Escape(subLine)))
+			rows = append(rows, sb.String())
+		}
+	}
+
+	for _, c := range closing {
+		labelStyle := p.labelStyle(c.ann.Kind)
+
+		for j, subLine := range p.wrapContent(c.ann.Content, gutterWidth) {
+			ctx := gutterCtx
+			ctx.Soft = j > 0
+
+			var sb strings.Builder
+
+			sb.WriteString(p.gutterFunc(ctx))
+			sb.WriteString(margin.render(pos.Line, marginBelow, &c.r, j == 0))
+			sb.WriteString(labelStyle.Render(ansi.Escape(subLine)))
+			rows = append(rows, sb.String())
+		}
+	}
+
+	return rows
+}
+
+// labelAnnotation is a label and its index in the annotations of its line.
+type labelAnnotation struct {
+	ann   line.Annotation
+	index int
+}
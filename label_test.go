@@ -0,0 +1,264 @@
+package niceyaml_test
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+func newPlainPrinter() *niceyaml.Printer {
+	return niceyaml.NewPrinter(
+		niceyaml.WithStyles(style.Styles{}),
+		niceyaml.WithStyle(lipgloss.NewStyle()),
+	)
+}
+
+func labelRange(startLine, startCol, endLine, endCol int) position.Range {
+	return position.NewRange(position.New(startLine, startCol), position.New(endLine, endCol))
+}
+
+func TestError_Labels(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+		  image: nginx
+		other: 1
+	`)
+
+	tcs := map[string]struct {
+		opts []niceyaml.ErrorOption
+		want string
+	}{
+		"single line labels": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithLabels(
+					niceyaml.NewPrimaryLabel(labelRange(2, 12, 2, 17), "expected a number"),
+					niceyaml.NewSecondaryLabel(labelRange(2, 2, 2, 10), "for this key"),
+				),
+			},
+			want: stringtest.JoinLF(
+				"[3:13] invalid spec:",
+				"",
+				"   1  name: app",
+				"   2  spec:",
+				"   3    replicas: three",
+				"        -------- for this key",
+				"                  ^^^^^ expected a number",
+				"   4    image: nginx",
+				"   5  other: 1",
+			),
+		},
+		"multi-line labels": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithLabels(
+					niceyaml.NewPrimaryLabel(labelRange(2, 12, 2, 17), "expected a number"),
+					niceyaml.NewSecondaryLabel(labelRange(1, 0, 3, 14), "in this spec"),
+					niceyaml.NewSecondaryLabel(labelRange(2, 2, 3, 7), "inner"),
+				),
+			},
+			want: stringtest.JoinLF(
+				"[3:13] invalid spec:",
+				"",
+				"   1       name: app",
+				"   2  ╭──▶ spec:",
+				"   3  │╭─▶   replicas: three",
+				"      ││               ^^^^^ expected a number",
+				"   4  ├├─▶   image: nginx",
+				"      │╰── inner",
+				"      ╰─── in this spec",
+				"   5       other: 1",
+			),
+		},
+		"sequential multi-line labels share a column": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithSourceLines(0),
+				niceyaml.WithLabels(
+					niceyaml.NewSecondaryLabel(labelRange(0, 0, 1, 4), "first"),
+					niceyaml.NewSecondaryLabel(labelRange(2, 2, 3, 7), "second"),
+				),
+			},
+			want: stringtest.JoinLF(
+				"invalid spec:",
+				"",
+				"   1  ╭─▶ name: app",
+				"   2  ├─▶ spec:",
+				"      ╰── first",
+				"   3  ╭─▶   replicas: three",
+				"   4  ├─▶   image: nginx",
+				"      ╰── second",
+			),
+		},
+		"with main error path": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithPath(paths.Root().Child("spec", "replicas").Value()),
+				niceyaml.WithSourceLines(0),
+				niceyaml.WithLabels(
+					niceyaml.NewSecondaryLabel(labelRange(3, 2, 3, 7), "see also"),
+				),
+			},
+			want: stringtest.JoinLF(
+				"[3:13] invalid spec:",
+				"",
+				"   3    replicas: three",
+				"   4    image: nginx",
+				"        ----- see also",
+			),
+		},
+		"nested errors with labels": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithSourceLines(0),
+				niceyaml.WithErrors(
+					niceyaml.NewError("expected a number",
+						niceyaml.WithLabels(niceyaml.NewPrimaryLabel(labelRange(2, 12, 2, 17), "")),
+					),
+					niceyaml.NewError("unknown key",
+						niceyaml.WithLabels(niceyaml.NewPrimaryLabel(labelRange(4, 0, 4, 5), "")),
+					),
+				),
+			},
+			want: stringtest.JoinLF(
+				"invalid spec:",
+				"",
+				"   3    replicas: three",
+				"                  ^ expected a number",
+				"      ...",
+				"   5  other: 1",
+				"      ^ unknown key",
+			),
+		},
+		"out of range labels are ignored": {
+			opts: []niceyaml.ErrorOption{
+				niceyaml.WithSourceLines(0),
+				niceyaml.WithLabels(
+					niceyaml.NewPrimaryLabel(labelRange(0, 0, 0, 4), ""),
+					niceyaml.NewSecondaryLabel(labelRange(9, 0, 9, 4), "missing"),
+				),
+			},
+			want: stringtest.JoinLF(
+				"[1:1] invalid spec:",
+				"",
+				"   1  name: app",
+				"      ^^^^",
+			),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]niceyaml.ErrorOption{
+				niceyaml.WithSource(niceyaml.NewSourceFromString(input)),
+				niceyaml.WithPrinter(newPlainPrinter()),
+			}, tc.opts...)
+
+			err := niceyaml.NewError("invalid spec", opts...)
+
+			assert.Equal(t, tc.want, trimLines(err.Error()))
+		})
+	}
+}
+
+func TestError_Labels_SourceUnchanged(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("name: app\nname: other\n")
+	err := niceyaml.NewError("duplicate key",
+		niceyaml.WithSource(source),
+		niceyaml.WithPrinter(newPlainPrinter()),
+		niceyaml.WithLabels(
+			niceyaml.NewPrimaryLabel(labelRange(1, 0, 1, 4), "duplicate"),
+			niceyaml.NewSecondaryLabel(labelRange(0, 0, 0, 4), "first defined here"),
+		),
+	)
+
+	first := err.Error()
+	assert.Equal(t, first, err.Error())
+	assert.Equal(t, 1, strings.Count(first, "first defined here"))
+
+	for _, ln := range source.Lines() {
+		assert.Empty(t, ln.Annotations)
+		assert.Empty(t, ln.Overlays)
+	}
+}
+
+func TestError_Labels_NoSource(t *testing.T) {
+	t.Parallel()
+
+	label := niceyaml.NewPrimaryLabel(labelRange(0, 0, 0, 4), "here")
+	err := niceyaml.NewError("invalid", niceyaml.WithLabels(label))
+
+	assert.Equal(t, "invalid", err.Error())
+	assert.Equal(t, []niceyaml.Label{label}, err.Labels())
+}
+
+func TestPrinter_Labels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wrapped label", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("key: value\n")
+		source.Line(0).AddAnnotation(line.Annotation{
+			Content:  "a long message that wraps",
+			Position: line.Below,
+			Cols:     position.NewSpan(5, 10),
+			Marker:   '~',
+		})
+
+		p := newPlainPrinter()
+		p.SetWidth(30)
+
+		want := stringtest.JoinLF(
+			"   1  key: value",
+			"           ~~~~~ a long",
+			"                 message that wraps",
+		)
+		assert.Equal(t, want, trimLines(p.Print(source)))
+	})
+
+	t.Run("labels with other annotations", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("a: 1\nb: 2\n")
+		source.Line(0).AddAnnotation(
+			line.Annotation{Content: "label", Position: line.Below, Cols: position.NewSpan(3, 4)},
+			line.Annotation{Content: "note", Position: line.Below, Col: 0},
+		)
+		source.Line(1).AddAnnotation(
+			line.Annotation{Content: "header", Position: line.Above},
+			line.Annotation{Content: "span", Position: line.Below, Lines: position.NewSpan(0, 2)},
+		)
+
+		p := niceyaml.NewPrinter(
+			niceyaml.WithStyles(style.Styles{}),
+			niceyaml.WithStyle(lipgloss.NewStyle()),
+			niceyaml.WithGutter(niceyaml.NoGutter()),
+		)
+
+		want := stringtest.JoinLF(
+			"╭─▶ a: 1",
+			"│   ^ note",
+			"│      ^ label",
+			"│   header",
+			"├─▶ b: 2",
+			"╰── span",
+		)
+		assert.Equal(t, want, trimLines(p.Print(source)))
+
+		p.SetAnnotations(false)
+		assert.Equal(t, "a: 1\nb: 2", trimLines(p.Print(source)))
+	})
+}
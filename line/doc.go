@@ -76,6 +76,17 @@
 //	    Col:      4,  // Align with the error location.
 //	})
 //
+// Labels are annotations that mark a region of the line instead of a single
+// column. They are rendered with an underline below Cols, or, for multi-line
+// regions, with arrows connecting the Lines of the region:
+//
+//	l.AddAnnotation(line.Annotation{
+//	    Content:  "expected a number",
+//	    Position: line.Below,
+//	    Kind:     style.GenericError,
+//	    Cols:     position.NewSpan(4, 9),
+//	})
+//
 // [Overlays] apply styles to column ranges.
 // Use [Lines.AddOverlay] for multi-line ranges that need automatic splitting:
 //
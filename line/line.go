@@ -3,6 +3,7 @@ package line
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/goccy/go-yaml/token"
@@ -502,3 +503,13 @@ func (ls *Lines) ClearOverlays() {
 		(*ls)[i].Overlays = nil
 	}
 }
+
+// RemoveOverlays removes the [Overlay] values of the given kinds from all
+// lines, keeping overlays of other kinds.
+func (ls *Lines) RemoveOverlays(kinds ...style.Style) {
+	for i := range *ls {
+		(*ls)[i].Overlays = slices.DeleteFunc((*ls)[i].Overlays, func(o Overlay) bool {
+			return slices.Contains(kinds, o.Kind)
+		})
+	}
+}
@@ -2970,6 +2970,24 @@ func TestLines_ClearOverlays(t *testing.T) {
 	})
 }
 
+func TestLines_RemoveOverlays(t *testing.T) {
+	t.Parallel()
+
+	lines := line.NewLines(lexer.Tokenize("key1: value1\nkey2: value2\n"))
+	require.Len(t, lines, 2)
+
+	lines.AddOverlay("keep", position.NewRange(position.New(0, 0), position.New(0, 4)))
+	lines.AddOverlay("remove",
+		position.NewRange(position.New(0, 6), position.New(0, 12)),
+		position.NewRange(position.New(1, 6), position.New(1, 12)),
+	)
+
+	lines.RemoveOverlays("remove")
+
+	assert.Equal(t, line.Overlays{{Kind: "keep", Cols: position.NewSpan(0, 4)}}, lines[0].Overlays)
+	assert.Empty(t, lines[1].Overlays)
+}
+
 func TestLine_Clone_PreservesOverlays(t *testing.T) {
 	t.Parallel()
 
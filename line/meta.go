@@ -37,10 +37,28 @@ const (
 // part of the main token stream.
 //
 // Add annotations using [Line.AddAnnotation].
+//
+// An annotation with Cols or a multi-line Lines span is a label: it marks a
+// region of the source rather than a single column. See [Annotation.IsLabel].
 type Annotation struct {
 	Content  string
 	Position RelativePosition
 	Col      int // Optional, 0-indexed column position for the annotation.
+	// Kind is the optional style of a label.
+	Kind style.Style
+	// Cols is the optional span of columns underlined by a label.
+	Cols position.Span
+	// Lines is the optional span of line indices connected by a multi-line
+	// label. Multi-line labels belong below the last line of the span.
+	Lines position.Span
+	// Marker is the rune used to underline Cols. Defaults to '^'.
+	Marker rune
+}
+
+// IsLabel reports whether the annotation is a label, i.e. it underlines a span
+// of columns or connects a span of multiple lines.
+func (a Annotation) IsLabel() bool {
+	return a.Cols.Len() > 0 || a.Lines.Len() > 1
 }
 
 // String returns the annotation content padded to the specified column.
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/position"
 )
 
 func TestAnnotation_String(t *testing.T) {
@@ -43,6 +44,40 @@ func TestAnnotation_String(t *testing.T) {
 	}
 }
 
+func TestAnnotation_IsLabel(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		ann  line.Annotation
+		want bool
+	}{
+		"plain": {
+			ann:  line.Annotation{Content: "note", Col: 5},
+			want: false,
+		},
+		"underlined columns": {
+			ann:  line.Annotation{Content: "note", Cols: position.NewSpan(2, 4)},
+			want: true,
+		},
+		"multiple lines": {
+			ann:  line.Annotation{Content: "note", Lines: position.NewSpan(1, 3)},
+			want: true,
+		},
+		"single line": {
+			ann:  line.Annotation{Content: "note", Lines: position.NewSpan(1, 2)},
+			want: false,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tc.ann.IsLabel())
+		})
+	}
+}
+
 func TestAnnotations_Col(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,264 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// Regular expressions of the plain scalars resolved by the YAML 1.1 types
+// (https://yaml.org/type/), and by the YAML 1.2 core schema.
+var (
+	yaml11Bool = regexp.MustCompile(
+		`^(y|Y|yes|Yes|YES|n|N|no|No|NO|true|True|TRUE|false|False|FALSE|on|On|ON|off|Off|OFF)$`,
+	)
+	yaml11Int = regexp.MustCompile(
+		`^[-+]?(0b[0-1_]+|0[0-7_]+|0|[1-9][0-9_]*|0x[0-9a-fA-F_]+|[1-9][0-9_]*(:[0-5]?[0-9])+)$`,
+	)
+	// Like PyYAML, floats need a digit before or after a single point, so
+	// that versions and addresses, such as "1.2.3", are strings.
+	yaml11Float = regexp.MustCompile(
+		`^([-+]?[0-9][0-9_]*\.[0-9_]*([eE][-+][0-9]+)?|[-+]?\.[0-9][0-9_]*([eE][-+][0-9]+)?|` +
+			`[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+\.[0-9_]*|[-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`,
+	)
+	yaml11Timestamp = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2}|` +
+		`[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}([Tt]|[ \t]+)[0-9]{1,2}:[0-9]{2}:[0-9]{2}(\.[0-9]*)?` +
+		`(([ \t]*)Z|[-+][0-9]{1,2}(:[0-9]{2})?)?)$`)
+	yaml11Null = regexp.MustCompile(`^(~|null|Null|NULL|)$`)
+
+	yaml12Bool  = regexp.MustCompile(`^(true|True|TRUE|false|False|FALSE)$`)
+	yaml12Int   = regexp.MustCompile(`^([-+]?[0-9]+|0o[0-7]+|0x[0-9a-fA-F]+)$`)
+	yaml12Float = regexp.MustCompile(
+		`^([-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?|[-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`,
+	)
+	yaml12Null = regexp.MustCompile(`^(~|null|Null|NULL|)$`)
+
+	// pointFloat matches the floats without a digit before or after the
+	// point, which the YAML 1.2 JSON schema does not read as floats.
+	pointFloat = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+\.)([eE][-+]?[0-9]+)?$`)
+)
+
+// AmbiguousValues reports plain scalars that YAML 1.1 and YAML 1.2 parsers
+// read differently, the "Norway problem": "no" is the boolean false in YAML
+// 1.1, but a string in YAML 1.2. Each problem explains both readings, and
+// suggests quoting the scalar so that it is a string in both.
+//
+// Floats without a digit before or after the point, such as ".5", are
+// reported too: both YAML 1.1 and the YAML 1.2 core schema read them as
+// floats, but the YAML 1.2 JSON schema does not.
+//
+// Create instances with [NewAmbiguousValues].
+type AmbiguousValues struct {
+	// CheckKeys reports mapping keys too. The default is true.
+	CheckKeys bool `yaml:"check-keys"`
+}
+
+// NewAmbiguousValues creates a new [*AmbiguousValues] with the default
+// settings.
+func NewAmbiguousValues() *AmbiguousValues {
+	return &AmbiguousValues{CheckKeys: true}
+}
+
+// Name implements [Rule].
+func (r *AmbiguousValues) Name() string {
+	return "ambiguous-values"
+}
+
+// Check implements [Rule].
+func (r *AmbiguousValues) Check(p *Pass) {
+	tks := p.Tokens()
+
+	for _, i := range plainScalars(tks) {
+		tk := tks[i]
+		if !r.CheckKeys && isKey(tks, i) {
+			continue
+		}
+
+		explanation, ok := explainAmbiguity(tk.Value)
+		if !ok {
+			continue
+		}
+
+		p.ReportToken(tk, fmt.Sprintf("ambiguous value %q: %s", tk.Value, explanation),
+			niceyaml.WithHelp("quote the value to read it as a string with both YAML 1.1 and YAML 1.2"),
+			niceyaml.WithSuggestions(niceyaml.Suggestion{
+				Message:     "quote the value",
+				Replacement: `"` + tk.Value + `"`,
+				Range:       p.TokenRange(tk),
+			}),
+		)
+	}
+}
+
+// explainAmbiguity returns how YAML 1.1 and YAML 1.2 read the plain scalar v,
+// or false if they read it the same way.
+func explainAmbiguity(v string) (string, bool) {
+	yaml11, yaml12 := readYAML11(v), readYAML12(v)
+	if yaml11 != yaml12 {
+		return fmt.Sprintf("YAML 1.1 reads it as %s, but YAML 1.2 reads it as %s", yaml11, yaml12), true
+	}
+
+	if pointFloat.MatchString(v) {
+		return fmt.Sprintf("YAML 1.1 and the YAML 1.2 core schema read it as %s, "+
+			"but the YAML 1.2 JSON schema reads it as a string", yaml12), true
+	}
+
+	return "", false
+}
+
+// readYAML11 describes how YAML 1.1 reads the plain scalar v, e.g. "the
+// integer 8".
+func readYAML11(v string) string {
+	switch {
+	case yaml11Null.MatchString(v):
+		return "null"
+	case yaml11Bool.MatchString(v):
+		switch strings.ToLower(v) {
+		case "y", "yes", "true", "on":
+			return "the boolean true"
+		default:
+			return "the boolean false"
+		}
+	case yaml11Int.MatchString(v):
+		return describeInt(parseYAML11Int(v))
+	case yaml11Float.MatchString(v):
+		return describeFloat(parseYAML11Float(v))
+	case yaml11Timestamp.MatchString(v):
+		return "a timestamp"
+	default:
+		return "a string"
+	}
+}
+
+// readYAML12 describes how the YAML 1.2 core schema reads the plain scalar v,
+// e.g. "the integer 8".
+func readYAML12(v string) string {
+	switch {
+	case yaml12Null.MatchString(v):
+		return "null"
+	case yaml12Bool.MatchString(v):
+		return "the boolean " + strings.ToLower(v)
+	case yaml12Int.MatchString(v):
+		switch {
+		case strings.HasPrefix(v, "0o"):
+			return describeInt(strconv.ParseInt(v[2:], 8, 64))
+		case strings.HasPrefix(v, "0x"):
+			return describeInt(strconv.ParseInt(v[2:], 16, 64))
+		default:
+			// Leading zeros are not an octal prefix in YAML 1.2.
+			return describeInt(strconv.ParseInt(v, 10, 64))
+		}
+	case yaml12Float.MatchString(v):
+		return describeFloat(parseFloat(v))
+	default:
+		return "a string"
+	}
+}
+
+// parseYAML11Int parses an integer of the YAML 1.1 int type.
+func parseYAML11Int(v string) (int64, error) {
+	v = strings.ReplaceAll(v, "_", "")
+
+	sign := int64(1)
+	if rest, ok := strings.CutPrefix(v, "-"); ok {
+		sign, v = -1, rest
+	} else {
+		v = strings.TrimPrefix(v, "+")
+	}
+
+	var (
+		n   int64
+		err error
+	)
+
+	switch {
+	case strings.Contains(v, ":"):
+		n, err = parseSexagesimal(v)
+	case strings.HasPrefix(v, "0b"):
+		n, err = strconv.ParseInt(v[2:], 2, 64)
+	case strings.HasPrefix(v, "0x"):
+		n, err = strconv.ParseInt(v[2:], 16, 64)
+	case len(v) > 1 && v[0] == '0':
+		n, err = strconv.ParseInt(v[1:], 8, 64)
+	default:
+		n, err = strconv.ParseInt(v, 10, 64)
+	}
+
+	return sign * n, err
+}
+
+// parseYAML11Float parses a float of the YAML 1.1 float type.
+func parseYAML11Float(v string) (float64, error) {
+	v = strings.ReplaceAll(v, "_", "")
+
+	whole, frac, ok := strings.Cut(v, ".")
+	if !ok || !strings.Contains(whole, ":") {
+		return parseFloat(v)
+	}
+
+	sign := 1.0
+	if rest, ok := strings.CutPrefix(whole, "-"); ok {
+		sign, whole = -1, rest
+	} else {
+		whole = strings.TrimPrefix(whole, "+")
+	}
+
+	n, err := parseSexagesimal(whole)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := parseFloat("0." + frac)
+
+	return sign * (float64(n) + f), err
+}
+
+// parseSexagesimal parses an unsigned base 60 integer, e.g. "1:30" is 90.
+func parseSexagesimal(v string) (int64, error) {
+	var n int64
+
+	for part := range strings.SplitSeq(v, ":") {
+		d, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse %q: %w", v, err)
+		}
+
+		n = n*60 + d
+	}
+
+	return n, nil
+}
+
+// parseFloat parses a float, including the YAML ".inf" and ".nan" forms.
+func parseFloat(v string) (float64, error) {
+	v = strings.Replace(strings.ToLower(v), ".inf", "inf", 1)
+	v = strings.Replace(v, ".nan", "nan", 1)
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %q: %w", v, err)
+	}
+
+	return f, nil
+}
+
+// describeInt describes an integer, e.g. "the integer 8".
+func describeInt(n int64, err error) string {
+	if err != nil {
+		return "an integer"
+	}
+
+	return fmt.Sprintf("the integer %d", n)
+}
+
+// describeFloat describes a float, e.g. "the float 0.5".
+func describeFloat(f float64, err error) string {
+	if err != nil {
+		return "a float"
+	}
+
+	return "the float " + strconv.FormatFloat(f, 'g', -1, 64)
+}
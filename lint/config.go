@@ -0,0 +1,256 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// ErrInvalidConfig indicates that a [Config] is invalid.
+var ErrInvalidConfig = errors.New("invalid lint configuration")
+
+// ConfigFiles are the names of the yamllint configuration files found by
+// [FindConfig], in order of precedence.
+var ConfigFiles = []string{".yamllint", ".yamllint.yaml", ".yamllint.yml"}
+
+// Config is a yamllint-compatible configuration of a [Linter]:
+//
+//	extends: default
+//	rules:
+//	  line-length:
+//	    max: 120
+//	    level: warning
+//	  document-start: disable
+//	  key-ordering: enable
+//
+// Rule settings that are not supported are ignored, so that yamllint
+// configuration files can be used as is.
+type Config struct {
+	// Rules configures rules by name. Each value is "enable", "disable", or
+	// a mapping of the rule's settings, see the fields of each [Rule], and
+	// its "level": "error" or "warning".
+	Rules map[string]ast.Node `yaml:"rules"`
+	// Extends is the configuration that Rules are applied to: "default" for
+	// [DefaultRules], or "relaxed" for a more tolerant variant. The default
+	// is "default".
+	Extends string `yaml:"extends"`
+}
+
+// FindConfig returns the path of the first of [ConfigFiles] in dir, or an
+// empty string if there is none.
+func FindConfig(dir string) (string, error) {
+	for _, name := range ConfigFiles {
+		path := filepath.Join(dir, name)
+
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("find lint configuration: %w", err)
+		}
+	}
+
+	return "", nil
+}
+
+// LoadConfig reads and validates the [Config] in the yamllint configuration
+// file at path. Errors are annotated with the source of the file.
+func LoadConfig(path string, opts ...niceyaml.SourceOption) (*Config, error) {
+	src, err := niceyaml.NewSourceFromFile(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseConfig(src)
+}
+
+// ParseConfig parses and validates the [Config] in src. Errors are annotated
+// with src.
+func ParseConfig(src *niceyaml.Source) (*Config, error) {
+	dec, err := src.Decoder()
+	if err != nil {
+		return nil, src.WrapError(err)
+	}
+
+	cfg := &Config{}
+
+	for _, doc := range dec.Documents() {
+		err = doc.Decode(cfg)
+		if err != nil {
+			return nil, src.WrapError(err)
+		}
+	}
+
+	_, err = cfg.Options()
+	if err != nil {
+		return nil, src.WrapError(err)
+	}
+
+	return cfg, nil
+}
+
+// Options returns the [Option]s that configure a [Linter] as described by
+// the [Config].
+//
+// Errors in rule settings are returned as [*niceyaml.Error]s at the tokens of
+// the settings, without a source; see [niceyaml.Source.WrapError].
+func (c *Config) Options() ([]Option, error) {
+	rules := map[string]Rule{}
+	severities := map[string]niceyaml.Severity{}
+
+	switch c.Extends {
+	case "", "default":
+		for _, r := range DefaultRules() {
+			rules[r.Name()] = r
+		}
+	case "relaxed":
+		rules = relaxedRules()
+
+		for name := range rules {
+			severities[name] = niceyaml.SeverityWarning
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown configuration %q to extend", ErrInvalidConfig, c.Extends)
+	}
+
+	for name, node := range c.Rules {
+		rule, severity, err := configureRule(name, rules[name], node)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule == nil {
+			delete(rules, name)
+
+			continue
+		}
+
+		rules[name] = rule
+
+		if severity != nil {
+			severities[name] = *severity
+		}
+	}
+
+	// Run the rules in the order of [AllRules], regardless of the order of
+	// the configuration.
+	var enabled []Rule
+
+	for _, r := range AllRules() {
+		if rule, ok := rules[r.Name()]; ok {
+			enabled = append(enabled, rule)
+		}
+	}
+
+	opts := []Option{WithRules(enabled...)}
+	for name, s := range severities {
+		opts = append(opts, WithSeverity(name, s))
+	}
+
+	return opts, nil
+}
+
+// relaxedRules returns the rules of yamllint's "relaxed" configuration.
+func relaxedRules() map[string]Rule {
+	indentation := NewIndentation()
+	indentation.IndentSequences = "consistent"
+
+	rules := map[string]Rule{}
+	for _, r := range []Rule{
+		indentation,
+		NewLineLength(),
+		NewTrailingSpaces(),
+		NewKeyDuplicates(),
+	} {
+		rules[r.Name()] = r
+	}
+
+	return rules
+}
+
+// configureRule applies the settings in node to the named rule, or to a new
+// rule with its default settings if rule is nil. It returns a nil rule if the
+// rule is disabled, and the severity set by the "level" setting, if any.
+func configureRule(name string, rule Rule, node ast.Node) (Rule, *niceyaml.Severity, error) {
+	if rule == nil {
+		var err error
+
+		rule, err = NewRule(name)
+		if err != nil {
+			return nil, nil, nodeError(node, err.Error())
+		}
+	}
+
+	switch n := node.(type) {
+	case *ast.StringNode:
+		switch n.Value {
+		case "enable":
+			return rule, nil, nil
+		case "disable":
+			return nil, nil, nil
+		}
+	case *ast.MappingNode, *ast.MappingValueNode:
+		err := decodeNode(node, rule)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var level struct {
+			Level ast.Node `yaml:"level"`
+		}
+
+		err = decodeNode(node, &level)
+		if err != nil || level.Level == nil {
+			return rule, nil, err
+		}
+
+		severity, err := parseLevel(level.Level)
+
+		return rule, severity, err
+	}
+
+	return nil, nil, nodeError(node, fmt.Sprintf(`rule %q must be "enable", "disable", or a mapping of settings`, name))
+}
+
+// parseLevel returns the severity of the "level" rule setting.
+func parseLevel(node ast.Node) (*niceyaml.Severity, error) {
+	if n, ok := node.(*ast.StringNode); ok {
+		for _, s := range []niceyaml.Severity{niceyaml.SeverityError, niceyaml.SeverityWarning} {
+			if n.Value == s.String() {
+				return &s, nil
+			}
+		}
+	}
+
+	return nil, nodeError(node, `level must be "error" or "warning"`)
+}
+
+// decodeNode decodes node into v, converting YAML errors to
+// [*niceyaml.Error]s.
+func decodeNode(node ast.Node, v any) error {
+	err := yaml.NodeToValue(node, v)
+	if err == nil {
+		return nil
+	}
+
+	var yamlErr yaml.Error
+	if errors.As(err, &yamlErr) {
+		return niceyaml.NewError(yamlErr.GetMessage(), niceyaml.WithErrorToken(yamlErr.GetToken()))
+	}
+
+	//nolint:wrapcheck // Errors of rules, such as [*niceyaml.Error]s.
+	return err
+}
+
+// nodeError returns an [*niceyaml.Error] at the token of node.
+func nodeError(node ast.Node, msg string) error {
+	return niceyaml.NewErrorFrom(fmt.Errorf("%w: %s", ErrInvalidConfig, msg), niceyaml.WithErrorToken(node.GetToken()))
+}
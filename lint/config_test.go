@@ -0,0 +1,161 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/lint"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.JoinLF(
+		"---",
+		"a: yes",
+		"b: 0755",
+		"c:",
+		"- 1",
+		"#comment",
+		"long: 1234567890",
+	)
+
+	tcs := map[string]struct {
+		config string
+		want   []string
+	}{
+		"empty": {
+			config: "{}",
+			want: []string{
+				"2:4: warning: truthy value should be one of [false, true]",
+				"5:1: error: wrong indentation: expected 2 but found 0",
+				"6:1: warning: missing starting space in comment",
+			},
+		},
+		"rules": {
+			config: stringtest.Input(`
+				extends: default
+				rules:
+				  comments: disable
+				  truthy:
+				    allowed-values: [yes]
+				  octal-values: enable
+				  indentation:
+				    spaces: consistent
+				    indent-sequences: false
+				  line-length:
+				    max: 10
+				    level: warning
+				    allow-non-breakable-inline-mappings: true
+			`),
+			want: []string{
+				`3:4: error: forbidden implicit octal value "0755"`,
+				"7:11: warning: line too long (16 > 10 characters)",
+			},
+		},
+		"relaxed": {
+			config: stringtest.Input(`
+				extends: relaxed
+				rules:
+				  octal-values:
+				    level: error
+			`),
+			want: []string{
+				`3:4: error: forbidden implicit octal value "0755"`,
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := lint.ParseConfig(niceyaml.NewSourceFromString(tc.config))
+			require.NoError(t, err)
+
+			opts, err := cfg.Options()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, lintMessages(t, input, opts...))
+		})
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		config string
+		want   string
+	}{
+		"unknown rule": {
+			config: "rules:\n  unknown: enable",
+			want:   `2:12: error: invalid lint configuration: unknown rule: "unknown"`,
+		},
+		"invalid rule value": {
+			config: "rules:\n  truthy: true",
+			want: `2:11: error: invalid lint configuration: ` +
+				`rule "truthy" must be "enable", "disable", or a mapping of settings`,
+		},
+		"invalid level": {
+			config: "rules:\n  truthy:\n    level: fatal",
+			want:   `3:12: error: invalid lint configuration: level must be "error" or "warning"`,
+		},
+		"invalid indent sequences": {
+			config: "rules:\n  indentation:\n    indent-sequences: sometimes",
+			want: `3:23: error: invalid lint configuration: ` +
+				`indent-sequences must be true, false, "whatever", or "consistent"`,
+		},
+		"invalid key order": {
+			config: "rules:\n  key-ordering:\n    order: random",
+			want:   `3:12: error: invalid lint configuration: order must be "alphabetical" or "kubernetes"`,
+		},
+		"unknown extends": {
+			config: "extends: strict",
+			want:   `invalid lint configuration: unknown configuration "strict" to extend`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := niceyaml.NewSourceFromString(tc.config,
+				niceyaml.WithErrorOptions(niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact)),
+			)
+
+			_, err := lint.ParseConfig(src)
+			require.ErrorIs(t, err, lint.ErrInvalidConfig)
+			assert.Equal(t, tc.want, err.Error())
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	got, err := lint.FindConfig(dir)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	for _, name := range []string{".yamllint.yml", ".yamllint"} {
+		err = os.WriteFile(filepath.Join(dir, name), []byte("extends: relaxed\n"), 0o600)
+		require.NoError(t, err)
+	}
+
+	got, err = lint.FindConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".yamllint"), got)
+
+	cfg, err := lint.LoadConfig(got)
+	require.NoError(t, err)
+	assert.Equal(t, "relaxed", cfg.Extends)
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// Inline directives, written as comments. Each is followed by the names of
+// the rules it applies to, or by nothing to apply to all rules.
+const (
+	// DirectiveDisableLine disables rules on the line of the comment, or on
+	// the next line if the comment is on a line of its own.
+	DirectiveDisableLine = "nyaml-disable-line"
+	// DirectiveDisable disables rules from the line of the comment until
+	// they are enabled again with [DirectiveEnable].
+	DirectiveDisable = "nyaml-disable"
+	// DirectiveEnable enables rules disabled with [DirectiveDisable].
+	DirectiveEnable = "nyaml-enable"
+)
+
+// directive is an inline [DirectiveDisable] or [DirectiveEnable] comment.
+type directive struct {
+	rules  []string
+	line   int
+	enable bool
+}
+
+// directives are the inline directives of a source.
+//
+// Create instances with [parseDirectives].
+type directives struct {
+	// lines maps line indexes to the rules disabled on them by
+	// [DirectiveDisableLine]. An empty name disables all rules.
+	lines map[int][]string
+	// blocks are the [DirectiveDisable] and [DirectiveEnable] comments, in
+	// source order.
+	blocks []directive
+}
+
+// parseDirectives returns the inline directives in the comments of tks.
+func parseDirectives(tks token.Tokens) *directives {
+	d := &directives{lines: map[int][]string{}}
+
+	// The line of the last token that is not a comment, to find comments on
+	// lines of their own.
+	contentLine := -1
+
+	for _, tk := range tks {
+		if tk.Position == nil {
+			continue
+		}
+
+		line := position.NewFromToken(tk).Line
+
+		if tk.Type != token.CommentType {
+			contentLine = line
+
+			continue
+		}
+
+		fields := strings.FieldsFunc(tk.Value, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(fields) == 0 {
+			continue
+		}
+
+		rules := fields[1:]
+
+		switch fields[0] {
+		case DirectiveDisableLine:
+			if contentLine != line {
+				line++
+			}
+
+			if len(rules) == 0 {
+				rules = []string{""}
+			}
+
+			d.lines[line] = append(d.lines[line], rules...)
+		case DirectiveDisable:
+			d.blocks = append(d.blocks, directive{line: line, rules: rules})
+		case DirectiveEnable:
+			d.blocks = append(d.blocks, directive{line: line, rules: rules, enable: true})
+		}
+	}
+
+	return d
+}
+
+// disabled reports whether rule is disabled on the line with the given index.
+func (d *directives) disabled(rule string, line int) bool {
+	if rules := d.lines[line]; slices.Contains(rules, "") || slices.Contains(rules, rule) {
+		return true
+	}
+
+	var (
+		all     bool
+		matched bool
+		state   bool
+	)
+
+	for _, b := range d.blocks {
+		if b.line > line {
+			break
+		}
+
+		switch {
+		case len(b.rules) == 0:
+			all, matched = !b.enable, false
+		case slices.Contains(b.rules, rule):
+			state, matched = !b.enable, true
+		}
+	}
+
+	if matched {
+		return state
+	}
+
+	return all
+}
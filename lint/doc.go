@@ -0,0 +1,66 @@
+// Package lint checks YAML sources for style and correctness problems, in the
+// spirit of yamllint, and reports them as [niceyaml.Error] diagnostics that
+// render with the rest of niceyaml.
+//
+// A [Linter] runs a set of [Rule]s over a [niceyaml.Source]:
+//
+//	linter := lint.New()
+//	if err := linter.Lint(source); err != nil {
+//		fmt.Println(err) // Pretty, annotated diagnostics.
+//	}
+//
+// # Rules
+//
+// Each [Rule] inspects the [go.jacobcolvin.com/niceyaml/line.Lines], tokens,
+// and AST of a source through a [Pass], and reports problems at exact ranges
+// with [Pass.Report]. The built-in rules mirror yamllint's rules of the same
+// names:
+//
+//   - [Indentation] ("indentation")
+//   - [LineLength] ("line-length")
+//   - [TrailingSpaces] ("trailing-spaces")
+//   - [Truthy] ("truthy")
+//   - [KeyDuplicates] ("key-duplicates")
+//   - [KeyOrdering] ("key-ordering")
+//   - [EmptyValues] ("empty-values")
+//   - [DocumentStart] ("document-start")
+//   - [Comments] ("comments")
+//   - [OctalValues] ("octal-values")
+//
+// [AmbiguousValues] ("ambiguous-values") reports scalars that YAML 1.1 and
+// YAML 1.2 parsers read differently, such as "no", "010", and "1:30", with
+// an explanation of both readings and a suggestion to quote them.
+//
+// Rules are configured through their fields, e.g. [LineLength.Max], and
+// their [niceyaml.Severity] through [WithSeverity]. [DefaultRules] returns
+// the rules enabled by yamllint's default configuration, and [AllRules]
+// returns all built-in rules. Custom rules implement [Rule].
+//
+// # Inline Directives
+//
+// Comments disable rules for parts of a source. Each directive is followed by
+// the rules it applies to, or by nothing to apply to all rules:
+//
+//	key: value # nyaml-disable-line truthy
+//	# nyaml-disable-line line-length
+//	url: https://example.com/a/very/long/url
+//	# nyaml-disable comments key-ordering
+//	...
+//	# nyaml-enable comments key-ordering
+//
+// # Configuration
+//
+// A [Config] configures a [Linter] from a yamllint-compatible configuration
+// file, see [LoadConfig]:
+//
+//	extends: default
+//	rules:
+//	  line-length:
+//	    max: 120
+//	    level: warning
+//	  document-start: disable
+//	  key-ordering: enable
+//
+// Syntax errors are reported with the code "syntax", and the rest of the
+// source is still linted, as parsed by [niceyaml.Source.ParseTolerant].
+package lint
@@ -0,0 +1,238 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// defaultSpaces is the number of spaces per indentation level expected before
+// any indentation is seen, with [Indentation.Spaces] set to zero.
+const defaultSpaces = 2
+
+// Indentation reports block mappings and sequences that are not indented by
+// the expected number of spaces.
+//
+// Create instances with [NewIndentation].
+type Indentation struct {
+	// Spaces is the number of spaces per indentation level. Zero requires
+	// consistent indentation, using the first indentation of the source. The
+	// default is zero.
+	Spaces int `yaml:"spaces"`
+	// IndentSequences controls whether block sequences in mappings are
+	// indented: "true" requires it, "false" forbids it, "whatever" allows
+	// both, and "consistent" requires the same choice throughout the source.
+	// The default is "true".
+	IndentSequences string `yaml:"indent-sequences"`
+}
+
+// NewIndentation creates a new [*Indentation] with the default settings.
+func NewIndentation() *Indentation {
+	return &Indentation{IndentSequences: "true"}
+}
+
+// Name implements [Rule].
+func (r *Indentation) Name() string {
+	return "indentation"
+}
+
+// UnmarshalYAML implements [yaml.NodeUnmarshaler]. As in yamllint, Spaces
+// may be "consistent", which is the same as zero, and IndentSequences may be
+// a boolean.
+//
+// [yaml.NodeUnmarshaler]: https://pkg.go.dev/github.com/goccy/go-yaml#NodeUnmarshaler
+func (r *Indentation) UnmarshalYAML(node ast.Node) error {
+	var raw struct {
+		Spaces          ast.Node `yaml:"spaces"`
+		IndentSequences ast.Node `yaml:"indent-sequences"`
+	}
+
+	err := decodeNode(node, &raw)
+	if err != nil {
+		return err
+	}
+
+	if n, ok := raw.Spaces.(*ast.StringNode); ok && n.Value == "consistent" {
+		r.Spaces = 0
+	} else if raw.Spaces != nil {
+		err = decodeNode(raw.Spaces, &r.Spaces)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch n := raw.IndentSequences.(type) {
+	case nil:
+	case *ast.BoolNode:
+		r.IndentSequences = strconv.FormatBool(n.Value)
+	case *ast.StringNode:
+		if !slices.Contains([]string{"true", "false", "whatever", "consistent"}, n.Value) {
+			return nodeError(n, `indent-sequences must be true, false, "whatever", or "consistent"`)
+		}
+
+		r.IndentSequences = n.Value
+	default:
+		return nodeError(n, `indent-sequences must be true, false, "whatever", or "consistent"`)
+	}
+
+	return nil
+}
+
+// Check implements [Rule].
+func (r *Indentation) Check(p *Pass) {
+	if p.File() == nil {
+		return
+	}
+
+	c := &indentationChecker{p: p, spaces: r.Spaces, indentSequences: r.IndentSequences}
+
+	for _, doc := range p.File().Docs {
+		body := unwrap(doc.Body)
+		if tk := blockStart(body); tk != nil {
+			c.check(tk, 0)
+			c.walk(body)
+		}
+	}
+}
+
+// indentationChecker holds the state of an [Indentation] check.
+type indentationChecker struct {
+	p               *Pass
+	indentSequences string
+	spaces          int
+}
+
+// walk checks the indentation of the children of node.
+func (c *indentationChecker) walk(node ast.Node) {
+	switch n := unwrap(node).(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle {
+			return
+		}
+
+		for _, mv := range n.Values {
+			c.walkEntry(mv)
+		}
+	case *ast.MappingValueNode:
+		c.walkEntry(n)
+	case *ast.SequenceNode:
+		if n.IsFlowStyle {
+			return
+		}
+
+		for _, entry := range n.Entries {
+			c.walkChild(entry.Start, entry.Value, false)
+		}
+	}
+}
+
+// walkEntry checks the value of a mapping entry.
+func (c *indentationChecker) walkEntry(mv *ast.MappingValueNode) {
+	if mv.Key == nil {
+		return
+	}
+
+	c.walkChild(mv.Key.GetToken(), mv.Value, true)
+}
+
+// walkChild checks the indentation of the block collection value, relative
+// to the token of its parent key or sequence entry.
+func (c *indentationChecker) walkChild(parent *token.Token, value ast.Node, inMapping bool) {
+	value = unwrap(value)
+
+	tk := blockStart(value)
+	if tk == nil || parent == nil || parent.Position == nil || tk.Position.Line <= parent.Position.Line {
+		c.walk(value)
+
+		return
+	}
+
+	parentIndent := parent.Position.Column - 1
+
+	if _, ok := value.(*ast.SequenceNode); ok && inMapping {
+		c.checkSequence(tk, parentIndent)
+	} else {
+		c.check(tk, parentIndent+c.levelSpaces(tk.Position.Column-1-parentIndent))
+	}
+
+	c.walk(value)
+}
+
+// checkSequence checks the indentation of a block sequence in a mapping.
+func (c *indentationChecker) checkSequence(tk *token.Token, parentIndent int) {
+	found := tk.Position.Column - 1
+
+	switch c.indentSequences {
+	case "whatever":
+		if found == parentIndent {
+			return
+		}
+	case "consistent":
+		if found == parentIndent {
+			c.indentSequences = "false"
+
+			return
+		}
+
+		c.indentSequences = "true"
+	case "false":
+		c.check(tk, parentIndent)
+
+		return
+	}
+
+	spaces := c.levelSpaces(found - parentIndent)
+	if spaces == 0 {
+		// Not indented, before any indentation was seen.
+		spaces = defaultSpaces
+	}
+
+	c.check(tk, parentIndent+spaces)
+}
+
+// levelSpaces returns the number of spaces per indentation level, detecting
+// it from the found indentation if not set.
+func (c *indentationChecker) levelSpaces(found int) int {
+	if c.spaces == 0 && found > 0 {
+		c.spaces = found
+	}
+
+	return c.spaces
+}
+
+// check reports tk if it is not indented by expected spaces.
+func (c *indentationChecker) check(tk *token.Token, expected int) {
+	if found := tk.Position.Column - 1; found != expected {
+		c.p.ReportToken(tk, fmt.Sprintf("wrong indentation: expected %d but found %d", expected, found))
+	}
+}
+
+// blockStart returns the first token of a block mapping or sequence: its
+// first key, or its first entry indicator. Returns nil for other nodes.
+func blockStart(node ast.Node) *token.Token {
+	var tk *token.Token
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if !n.IsFlowStyle && len(n.Values) > 0 && n.Values[0].Key != nil {
+			tk = n.Values[0].Key.GetToken()
+		}
+	case *ast.MappingValueNode:
+		if n.Key != nil {
+			tk = n.Key.GetToken()
+		}
+	case *ast.SequenceNode:
+		if !n.IsFlowStyle && len(n.Entries) > 0 {
+			tk = n.Entries[0].Start
+		}
+	}
+
+	if tk == nil || tk.Position == nil {
+		return nil
+	}
+
+	return tk
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// KeyDuplicates reports keys that appear more than once in a mapping, with a
+// secondary label at the first definition. Merge keys ("<<") are not
+// reported; see [niceyaml.Source.CheckDuplicateKeys] for keys that override
+// merged keys.
+//
+// Create instances with [NewKeyDuplicates].
+type KeyDuplicates struct{}
+
+// NewKeyDuplicates creates a new [*KeyDuplicates].
+func NewKeyDuplicates() *KeyDuplicates {
+	return &KeyDuplicates{}
+}
+
+// Name implements [Rule].
+func (r *KeyDuplicates) Name() string {
+	return "key-duplicates"
+}
+
+// Check implements [Rule].
+func (r *KeyDuplicates) Check(p *Pass) {
+	walk(p.File(), func(node, _ ast.Node) {
+		m, ok := node.(*ast.MappingNode)
+		if !ok {
+			return
+		}
+
+		seen := map[string]*token.Token{}
+
+		for _, mv := range m.Values {
+			if mv.Key == nil || mv.Key.IsMergeKey() {
+				continue
+			}
+
+			key := keyString(mv.Key)
+			if first, ok := seen[key]; ok {
+				p.ReportToken(mv.Key.GetToken(), fmt.Sprintf("duplication of key %q in mapping", key),
+					niceyaml.WithLabels(niceyaml.NewSecondaryLabel(p.TokenRange(first), "first defined here")),
+				)
+
+				continue
+			}
+
+			seen[key] = mv.Key.GetToken()
+		}
+	})
+}
+
+// KeyOrdering reports mapping keys that are out of order: alphabetical by
+// default, as in yamllint, or the order of [KeyOrdering.Order]. Merge keys
+// ("<<") are not reported. [niceyaml.Source.SortKeys] fixes the problems.
+//
+// Create instances with [NewKeyOrdering].
+type KeyOrdering struct {
+	// Order compares keys. The default, nil, sorts keys alphabetically. In
+	// configuration files, "order" may be "alphabetical" or "kubernetes",
+	// see [niceyaml.KubernetesKeyOrder].
+	Order niceyaml.KeyOrder `yaml:"-"`
+}
+
+// NewKeyOrdering creates a new [*KeyOrdering].
+func NewKeyOrdering() *KeyOrdering {
+	return &KeyOrdering{}
+}
+
+// Name implements [Rule].
+func (r *KeyOrdering) Name() string {
+	return "key-ordering"
+}
+
+// UnmarshalYAML implements [yaml.NodeUnmarshaler], reading the "order"
+// setting.
+//
+// [yaml.NodeUnmarshaler]: https://pkg.go.dev/github.com/goccy/go-yaml#NodeUnmarshaler
+func (r *KeyOrdering) UnmarshalYAML(node ast.Node) error {
+	var raw struct {
+		Order ast.Node `yaml:"order"`
+	}
+
+	err := decodeNode(node, &raw)
+	if err != nil || raw.Order == nil {
+		return err
+	}
+
+	n, ok := raw.Order.(*ast.StringNode)
+
+	switch {
+	case ok && n.Value == "alphabetical":
+		r.Order = nil
+	case ok && n.Value == "kubernetes":
+		r.Order = niceyaml.KubernetesKeyOrder()
+	default:
+		return nodeError(raw.Order, `order must be "alphabetical" or "kubernetes"`)
+	}
+
+	return nil
+}
+
+// Check implements [Rule].
+func (r *KeyOrdering) Check(p *Pass) {
+	if p.File() == nil {
+		return
+	}
+
+	order := r.Order
+	if order == nil {
+		order = niceyaml.AlphabeticalKeyOrder()
+	}
+
+	for _, doc := range p.File().Docs {
+		walkMappings(doc.Body, nil, func(m *ast.MappingNode, path []string) {
+			var last *string
+
+			for _, mv := range m.Values {
+				if mv.Key == nil || mv.Key.IsMergeKey() {
+					continue
+				}
+
+				key := keyString(mv.Key)
+				if last != nil && order(path, key, *last) < 0 {
+					p.ReportToken(mv.Key.GetToken(), fmt.Sprintf("wrong ordering of key %q in mapping", key))
+
+					continue
+				}
+
+				last = &key
+			}
+		})
+	}
+}
+
+// walkMappings calls fn for each mapping in node, with the keys leading to it
+// from path, as passed to a [niceyaml.KeyOrder].
+func walkMappings(node ast.Node, path []string, fn func(m *ast.MappingNode, path []string)) {
+	switch n := unwrap(node).(type) {
+	case *ast.MappingNode:
+		fn(n, path)
+
+		for _, mv := range n.Values {
+			if mv.Key != nil {
+				walkMappings(mv.Value, append(slices.Clip(path), keyString(mv.Key)), fn)
+			}
+		}
+	case *ast.MappingValueNode:
+		if n.Key != nil {
+			walkMappings(n.Value, append(slices.Clip(path), keyString(n.Key)), fn)
+		}
+	case *ast.SequenceNode:
+		for _, v := range n.Values {
+			walkMappings(v, append(slices.Clip(path), niceyaml.SequenceItemKey), fn)
+		}
+	}
+}
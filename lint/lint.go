@@ -0,0 +1,267 @@
+package lint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// ErrUnknownRule indicates that a rule name is not known.
+var ErrUnknownRule = errors.New("unknown rule")
+
+// SyntaxCode is the code of diagnostics reporting syntax errors.
+const SyntaxCode = "syntax"
+
+// Rule checks a source for one kind of problem.
+type Rule interface {
+	// Name returns the name of the rule, e.g. "line-length". It is used as the
+	// code of the rule's diagnostics, see [niceyaml.WithCode].
+	Name() string
+	// Check reports the problems found in the source of the [Pass].
+	Check(p *Pass)
+}
+
+// Pass provides a [Rule] with the source to check, and collects the problems
+// it reports.
+//
+// Create instances with [Linter.Diagnostics].
+type Pass struct {
+	source   *niceyaml.Source
+	file     *ast.File
+	tokens   token.Tokens
+	rule     Rule
+	errs     []*niceyaml.Error
+	severity niceyaml.Severity
+}
+
+// Source returns the [*niceyaml.Source] being linted.
+func (p *Pass) Source() *niceyaml.Source {
+	return p.source
+}
+
+// File returns the AST of the source. If the source has syntax errors, the
+// AST only contains the parts that could be parsed.
+func (p *Pass) File() *ast.File {
+	return p.file
+}
+
+// Tokens returns the tokens of the source.
+func (p *Pass) Tokens() token.Tokens {
+	return p.tokens
+}
+
+// Report reports a problem at the given range of the source.
+//
+// The diagnostic has the [niceyaml.Severity] configured for the rule, and the
+// rule's name as its code. Options are applied last.
+func (p *Pass) Report(rng position.Range, msg string, opts ...niceyaml.ErrorOption) {
+	p.errs = append(p.errs, niceyaml.NewError(msg, append([]niceyaml.ErrorOption{
+		niceyaml.WithSeverity(p.severity),
+		niceyaml.WithCode(p.rule.Name()),
+		niceyaml.WithLabels(niceyaml.NewPrimaryLabel(rng, "")),
+		niceyaml.WithSource(p.source),
+	}, opts...)...))
+}
+
+// ReportToken reports a problem at the content of the given token, see
+// [Pass.Report].
+func (p *Pass) ReportToken(tk *token.Token, msg string, opts ...niceyaml.ErrorOption) {
+	p.Report(p.TokenRange(tk), msg, opts...)
+}
+
+// TokenRange returns the range of the content of tk in the source, excluding
+// surrounding whitespace.
+func (p *Pass) TokenRange(tk *token.Token) position.Range {
+	start := position.NewFromToken(tk)
+
+	ranges := p.source.ContentPositionRanges(start)
+	if len(ranges) == 0 {
+		return position.NewRange(start, start)
+	}
+
+	return position.NewRange(ranges[0].Start, ranges[len(ranges)-1].End)
+}
+
+// Linter runs [Rule]s over sources.
+//
+// Create instances with [New].
+type Linter struct {
+	severities map[string]niceyaml.Severity
+	disabled   map[string]bool
+	rules      []Rule
+}
+
+// Option configures a [Linter].
+//
+// Available options:
+//   - [WithRules]
+//   - [WithSeverity]
+//   - [WithDisabled]
+type Option func(*Linter)
+
+// WithRules is an [Option] that sets the rules of the [Linter], replacing
+// [DefaultRules]. Rules are run in the given order.
+func WithRules(rules ...Rule) Option {
+	return func(l *Linter) {
+		l.rules = rules
+	}
+}
+
+// WithSeverity is an [Option] that sets the [niceyaml.Severity] of the
+// diagnostics of the named rule.
+//
+// By default, "comments", "document-start", and "truthy" report warnings, as
+// in yamllint's default configuration, as does "ambiguous-values". Other
+// rules report errors.
+func WithSeverity(rule string, s niceyaml.Severity) Option {
+	return func(l *Linter) {
+		l.severities[rule] = s
+	}
+}
+
+// WithDisabled is an [Option] that disables the named rules.
+func WithDisabled(rules ...string) Option {
+	return func(l *Linter) {
+		for _, r := range rules {
+			l.disabled[r] = true
+		}
+	}
+}
+
+// New creates a new [*Linter] running [DefaultRules], configured with the
+// given options.
+func New(opts ...Option) *Linter {
+	l := &Linter{
+		rules: DefaultRules(),
+		severities: map[string]niceyaml.Severity{
+			"ambiguous-values": niceyaml.SeverityWarning,
+			"comments":         niceyaml.SeverityWarning,
+			"document-start":   niceyaml.SeverityWarning,
+			"truthy":           niceyaml.SeverityWarning,
+		},
+		disabled: map[string]bool{},
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Rules returns the enabled rules of the [Linter].
+func (l *Linter) Rules() []Rule {
+	var rules []Rule
+
+	for _, r := range l.rules {
+		if !l.disabled[r.Name()] {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules
+}
+
+// Diagnostics runs the enabled rules over src, and returns the problems found
+// in order of their position.
+//
+// Syntax errors are reported with the code [SyntaxCode], and the rest of the
+// source is linted regardless, as parsed by [niceyaml.Source.ParseTolerant].
+//
+// Problems on lines disabled by inline comments are not reported, see
+// [DirectiveDisableLine], [DirectiveDisable], and [DirectiveEnable].
+func (l *Linter) Diagnostics(src *niceyaml.Source) []*niceyaml.Error {
+	tks := src.Tokens()
+
+	// The parser rejects duplicate keys, which are reported by [KeyDuplicates]
+	// instead.
+	parsed := niceyaml.NewSourceFromTokens(tks, niceyaml.WithParserOptions(parser.AllowDuplicateMapKey()))
+	file, err := parsed.ParseTolerant()
+
+	var errs []*niceyaml.Error
+
+	var yamlErr *niceyaml.Error
+	if errors.As(err, &yamlErr) {
+		syntaxErrs := yamlErr.Errors()
+		if len(syntaxErrs) == 0 {
+			syntaxErrs = []*niceyaml.Error{yamlErr}
+		}
+
+		for _, e := range syntaxErrs {
+			errs = append(errs, niceyaml.NewError(e.Message(),
+				niceyaml.WithCode(SyntaxCode),
+				niceyaml.WithLabels(niceyaml.NewPrimaryLabel(rangeOf(e, src), "")),
+				niceyaml.WithSource(src),
+			))
+		}
+	}
+
+	dirs := parseDirectives(tks)
+
+	for _, rule := range l.Rules() {
+		p := &Pass{
+			source:   src,
+			file:     file,
+			tokens:   tks,
+			rule:     rule,
+			severity: l.severities[rule.Name()],
+		}
+
+		rule.Check(p)
+
+		for _, e := range p.errs {
+			if !dirs.disabled(rule.Name(), rangeOf(e, src).Start.Line) {
+				errs = append(errs, e)
+			}
+		}
+	}
+
+	slices.SortStableFunc(errs, func(a, b *niceyaml.Error) int {
+		ra, rb := rangeOf(a, src), rangeOf(b, src)
+
+		return cmp.Or(
+			cmp.Compare(ra.Start.Line, rb.Start.Line),
+			cmp.Compare(ra.Start.Col, rb.Start.Col),
+		)
+	})
+
+	return errs
+}
+
+// Lint runs the enabled rules over src, and returns the problems found as an
+// error, or nil if there are none.
+//
+// A single problem is returned as is. Several problems are returned as one
+// [*niceyaml.Error] with an error per problem, see [niceyaml.WithErrors].
+// The error has the options of the source applied, see
+// [niceyaml.Source.WrapError].
+func (l *Linter) Lint(src *niceyaml.Source) error {
+	errs := l.Diagnostics(src)
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return src.WrapError(errs[0])
+	default:
+		return src.WrapError(niceyaml.NewError(
+			fmt.Sprintf("found %d problems", len(errs)),
+			niceyaml.WithErrors(errs...),
+		))
+	}
+}
+
+// rangeOf returns the range of e in src, or the zero range if unknown.
+func rangeOf(e *niceyaml.Error, src *niceyaml.Source) position.Range {
+	rng, _ := e.ResolveRange(src)
+
+	return rng
+}
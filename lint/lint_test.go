@@ -0,0 +1,494 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/lint"
+)
+
+// lintMessages lints input with the given rules, and returns the problems
+// found in the compact error format.
+func lintMessages(t *testing.T, input string, opts ...lint.Option) []string {
+	t.Helper()
+
+	source := niceyaml.NewSourceFromString(input)
+	linter := lint.New(opts...)
+
+	var msgs []string
+
+	for _, e := range linter.Diagnostics(source) {
+		e.SetOption(niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact))
+		msgs = append(msgs, e.Error())
+	}
+
+	return msgs
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		rule  lint.Rule
+		input string
+		want  []string
+	}{
+		"line-length": {
+			rule: &lint.LineLength{Max: 10, AllowNonBreakableWords: true},
+			input: stringtest.JoinLF(
+				"short: 1",
+				"too long: 12345",
+				"# https://example.com/long",
+			),
+			want: []string{"2:11: error: line too long (15 > 10 characters)"},
+		},
+		"line-length with non-breakable sequence entry": {
+			rule:  &lint.LineLength{Max: 10, AllowNonBreakableWords: true},
+			input: "- https://example.com/long",
+		},
+		"line-length without non-breakable words": {
+			rule:  &lint.LineLength{Max: 10},
+			input: "- https://example.com/long",
+			want:  []string{"1:11: error: line too long (26 > 10 characters)"},
+		},
+		"trailing-spaces": {
+			rule: lint.NewTrailingSpaces(),
+			input: stringtest.JoinLF(
+				"a: 1 # comment  ",
+				"b: |",
+				"  text  ",
+				"c: 2",
+			),
+			want: []string{
+				"1:15: error: trailing spaces",
+				"3:7: error: trailing spaces",
+			},
+		},
+		"document-start missing": {
+			rule:  lint.NewDocumentStart(),
+			input: "# comment\na: 1",
+			want:  []string{`2:1: warning: missing document start "---"`},
+		},
+		"document-start present": {
+			rule:  lint.NewDocumentStart(),
+			input: "# comment\n---\na: 1",
+		},
+		"document-start forbidden": {
+			rule:  &lint.DocumentStart{},
+			input: "---\na: 1",
+			want:  []string{`1:1: warning: found forbidden document start "---"`},
+		},
+		"comments": {
+			rule: lint.NewComments(),
+			input: stringtest.JoinLF(
+				"#!/usr/bin/env yq",
+				"#bad",
+				"# good",
+				"### good",
+				"a: 1 # too close",
+				"b: 2  # ok",
+			),
+			want: []string{
+				"2:1: warning: missing starting space in comment",
+				"5:6: warning: too few spaces before comment",
+			},
+		},
+		"truthy": {
+			rule: lint.NewTruthy(),
+			input: stringtest.JoinLF(
+				"a: yes",
+				"b: true",
+				"c: 'on'",
+				"d: !!bool off",
+				"no: 1",
+			),
+			want: []string{
+				"1:4: warning: truthy value should be one of [false, true]",
+				"5:1: warning: truthy value should be one of [false, true]",
+			},
+		},
+		"truthy without keys": {
+			rule:  &lint.Truthy{AllowedValues: []string{"yes"}},
+			input: "a: yes\nb: true\non: 1",
+			want:  []string{"2:4: warning: truthy value should be one of [yes]"},
+		},
+		"octal-values": {
+			rule: lint.NewOctalValues(),
+			input: stringtest.JoinLF(
+				"a: 0755",
+				"b: 0o755",
+				"c: '0755'",
+				"d: 0",
+				"e: 10",
+			),
+			want: []string{
+				`1:4: error: forbidden implicit octal value "0755"`,
+				`2:4: error: forbidden explicit octal value "0o755"`,
+			},
+		},
+		"empty-values": {
+			rule: lint.NewEmptyValues(),
+			input: stringtest.JoinLF(
+				"a:",
+				"b: null",
+				"c: {d: }",
+				"e:",
+				"  -",
+				"  - ~",
+			),
+			want: []string{
+				"1:1: error: empty value in block mapping",
+				"3:5: error: empty value in flow mapping",
+				"5:3: error: empty value in block sequence",
+			},
+		},
+		"key-duplicates": {
+			rule: lint.NewKeyDuplicates(),
+			input: stringtest.JoinLF(
+				"a: 1",
+				"b:",
+				"  c: 1",
+				"  c: 2",
+				"a: 3",
+			),
+			want: []string{
+				`4:3: error: duplication of key "c" in mapping`,
+				`5:1: error: duplication of key "a" in mapping`,
+			},
+		},
+		"key-ordering": {
+			rule: lint.NewKeyOrdering(),
+			input: stringtest.JoinLF(
+				"b: 1",
+				"a: 2",
+				"c:",
+				"  y: 1",
+				"  x: 2",
+			),
+			want: []string{
+				`2:1: error: wrong ordering of key "a" in mapping`,
+				`5:3: error: wrong ordering of key "x" in mapping`,
+			},
+		},
+		"key-ordering explicit keys": {
+			rule: lint.NewKeyOrdering(),
+			input: stringtest.JoinLF(
+				"? ماء # Water in Arabic",
+				": 1",
+				"? بيض # Eggs in Arabic",
+				": 2",
+			),
+			want: []string{
+				`3:1: error: wrong ordering of key "بيض" in mapping`,
+			},
+		},
+		"key-ordering kubernetes": {
+			rule: &lint.KeyOrdering{Order: niceyaml.KubernetesKeyOrder()},
+			input: stringtest.JoinLF(
+				"apiVersion: v1",
+				"metadata:",
+				"  labels: {}",
+				"  name: app",
+				"kind: Pod",
+			),
+			want: []string{
+				`4:3: error: wrong ordering of key "name" in mapping`,
+				`5:1: error: wrong ordering of key "kind" in mapping`,
+			},
+		},
+		"ambiguous-values": {
+			rule: lint.NewAmbiguousValues(),
+			input: stringtest.JoinLF(
+				"country: no",
+				"on: 1",
+				"mode: 010",
+				"new-mode: 0o10",
+				"time: 1:30",
+				"ratio: .5",
+				"big: 1_000",
+				"flags: 0b101",
+				"exp: 1e3",
+				"date: 2001-12-14",
+				"ok: [true, 10, 1.5, '1:30', \"no\", text, ~]",
+			),
+			want: []string{
+				`1:10: warning: ambiguous value "no": ` +
+					`YAML 1.1 reads it as the boolean false, but YAML 1.2 reads it as a string`,
+				`2:1: warning: ambiguous value "on": ` +
+					`YAML 1.1 reads it as the boolean true, but YAML 1.2 reads it as a string`,
+				`3:7: warning: ambiguous value "010": ` +
+					`YAML 1.1 reads it as the integer 8, but YAML 1.2 reads it as the integer 10`,
+				`4:11: warning: ambiguous value "0o10": ` +
+					`YAML 1.1 reads it as a string, but YAML 1.2 reads it as the integer 8`,
+				`5:7: warning: ambiguous value "1:30": ` +
+					`YAML 1.1 reads it as the integer 90, but YAML 1.2 reads it as a string`,
+				`6:8: warning: ambiguous value ".5": YAML 1.1 and the YAML 1.2 core schema read it as the float 0.5, ` +
+					`but the YAML 1.2 JSON schema reads it as a string`,
+				`7:6: warning: ambiguous value "1_000": ` +
+					`YAML 1.1 reads it as the integer 1000, but YAML 1.2 reads it as a string`,
+				`8:8: warning: ambiguous value "0b101": ` +
+					`YAML 1.1 reads it as the integer 5, but YAML 1.2 reads it as a string`,
+				`9:6: warning: ambiguous value "1e3": ` +
+					`YAML 1.1 reads it as a string, but YAML 1.2 reads it as the float 1000`,
+				`10:7: warning: ambiguous value "2001-12-14": ` +
+					`YAML 1.1 reads it as a timestamp, but YAML 1.2 reads it as a string`,
+			},
+		},
+		"ambiguous-values versions and addresses": {
+			rule: lint.NewAmbiguousValues(),
+			input: stringtest.JoinLF(
+				"version: 1.2.3",
+				"ip: 10.0.0.1",
+				"dot: .",
+				"dots: 1..2",
+				"tag: v1.2",
+				"float: 1_0.5",
+			),
+			want: []string{
+				`6:8: warning: ambiguous value "1_0.5": ` +
+					`YAML 1.1 reads it as the float 10.5, but YAML 1.2 reads it as a string`,
+			},
+		},
+		"ambiguous-values without keys": {
+			rule:  &lint.AmbiguousValues{},
+			input: "on: 1:30.5",
+			want: []string{
+				`1:5: warning: ambiguous value "1:30.5": ` +
+					`YAML 1.1 reads it as the float 90.5, but YAML 1.2 reads it as a string`,
+			},
+		},
+		"indentation consistent": {
+			rule: lint.NewIndentation(),
+			input: stringtest.JoinLF(
+				"a:",
+				"  b:",
+				"    c: 1",
+				"  d:",
+				"  - 1",
+				"e:",
+				"   f: 1",
+			),
+			want: []string{
+				"5:3: error: wrong indentation: expected 4 but found 2",
+				"7:4: error: wrong indentation: expected 2 but found 3",
+			},
+		},
+		"indentation with spaces": {
+			rule: &lint.Indentation{Spaces: 4, IndentSequences: "false"},
+			input: stringtest.JoinLF(
+				"a:",
+				"    b: 1",
+				"c:",
+				"  d: 1",
+				"e:",
+				"- 1",
+				"f:",
+				"    - 1",
+			),
+			want: []string{
+				"4:3: error: wrong indentation: expected 4 but found 2",
+				"8:5: error: wrong indentation: expected 0 but found 4",
+			},
+		},
+		"indentation whatever": {
+			rule: &lint.Indentation{IndentSequences: "whatever"},
+			input: stringtest.JoinLF(
+				"a:",
+				"- 1",
+				"b:",
+				"  - 1",
+			),
+		},
+		"indentation consistent sequences": {
+			rule: &lint.Indentation{IndentSequences: "consistent"},
+			input: stringtest.JoinLF(
+				"a:",
+				"- 1",
+				"b:",
+				"  - 1",
+			),
+			want: []string{"4:3: error: wrong indentation: expected 0 but found 2"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := lintMessages(t, tc.input, lint.WithRules(tc.rule))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAmbiguousValues_Suggestions(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("country: no\ntime: 1:30 # comment\n")
+
+	var suggestions []niceyaml.Suggestion
+	for _, e := range lint.New(lint.WithRules(lint.NewAmbiguousValues())).Diagnostics(source) {
+		assert.Equal(t, "quote the value to read it as a string with both YAML 1.1 and YAML 1.2", e.Help())
+
+		suggestions = append(suggestions, e.Suggestions()...)
+	}
+
+	fixed, err := source.ApplySuggestions(suggestions...)
+	require.NoError(t, err)
+	assert.Equal(t, "country: \"no\"\ntime: \"1:30\" # comment", fixed.Content())
+}
+
+func TestLinter_Options(t *testing.T) {
+	t.Parallel()
+
+	input := stringtest.JoinLF(
+		"a: yes",
+		"b: 0755",
+		"#comment",
+	)
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []string{
+			`1:1: warning: missing document start "---"`,
+			"1:4: warning: truthy value should be one of [false, true]",
+			"3:1: warning: missing starting space in comment",
+		}, lintMessages(t, input))
+	})
+
+	t.Run("all rules", func(t *testing.T) {
+		t.Parallel()
+
+		got := lintMessages(t, input,
+			lint.WithRules(lint.AllRules()...),
+			lint.WithDisabled("document-start", "comments", "ambiguous-values"),
+			lint.WithSeverity("truthy", niceyaml.SeverityError),
+		)
+
+		assert.Equal(t, []string{
+			"1:4: error: truthy value should be one of [false, true]",
+			`2:4: error: forbidden implicit octal value "0755"`,
+		}, got)
+	})
+}
+
+func TestLinter_SyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	got := lintMessages(t, stringtest.JoinLF(
+		"---",
+		"a: [",
+		"b: yes",
+	))
+
+	assert.Equal(t, []string{
+		"2:4: error: sequence end token ']' not found",
+		"3:4: warning: truthy value should be one of [false, true]",
+	}, got)
+}
+
+func TestLinter_Lint(t *testing.T) {
+	t.Parallel()
+
+	linter := lint.New()
+
+	t.Run("no problems", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, linter.Lint(niceyaml.NewSourceFromString("---\na: 1\n")))
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("a: yes\nb: on\n",
+			niceyaml.WithName("test.yaml"),
+			niceyaml.WithErrorOptions(niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact)),
+		)
+
+		err := linter.Lint(source)
+		require.Error(t, err)
+		assert.Equal(t, stringtest.JoinLF(
+			`test.yaml:1:1: warning: missing document start "---"`,
+			"test.yaml:1:4: warning: truthy value should be one of [false, true]",
+			"test.yaml:2:4: warning: truthy value should be one of [false, true]",
+		), err.Error())
+	})
+}
+
+func TestNewRule(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range lint.RuleNames() {
+		rule, err := lint.NewRule(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, rule.Name())
+	}
+
+	_, err := lint.NewRule("unknown")
+	require.ErrorIs(t, err, lint.ErrUnknownRule)
+}
+
+func TestLinter_Directives(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		input string
+		want  []string
+	}{
+		"disable line": {
+			input: stringtest.JoinLF(
+				"---",
+				"a: yes  # nyaml-disable-line truthy",
+				"b: yes  #nyaml-disable-line comments",
+			),
+			want: []string{"3:4: warning: truthy value should be one of [false, true]"},
+		},
+		"disable next line": {
+			input: stringtest.JoinLF(
+				"---",
+				"# nyaml-disable-line",
+				"a: yes",
+				"b: yes",
+			),
+			want: []string{"4:4: warning: truthy value should be one of [false, true]"},
+		},
+		"disable and enable": {
+			input: stringtest.JoinLF(
+				"---",
+				"# nyaml-disable truthy, comments",
+				"a: yes  #bad",
+				"# nyaml-enable truthy",
+				"b: yes  #bad",
+			),
+			want: []string{"5:4: warning: truthy value should be one of [false, true]"},
+		},
+		"disable all and enable one": {
+			input: stringtest.JoinLF(
+				"# nyaml-disable",
+				"a: yes  #bad",
+				"# nyaml-enable comments",
+				"b: yes  #bad",
+				"# nyaml-enable",
+				"c: yes",
+			),
+			want: []string{
+				"4:9: warning: missing starting space in comment",
+				"6:4: warning: truthy value should be one of [false, true]",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, lintMessages(t, tc.input))
+		})
+	}
+}
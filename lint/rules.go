@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// DefaultRules returns the built-in rules enabled by yamllint's default
+// configuration, with their default settings. As in yamllint, [KeyOrdering],
+// [EmptyValues], and [OctalValues] are not included; see [AllRules].
+func DefaultRules() []Rule {
+	return []Rule{
+		NewIndentation(),
+		NewLineLength(),
+		NewTrailingSpaces(),
+		NewTruthy(),
+		NewKeyDuplicates(),
+		NewDocumentStart(),
+		NewComments(),
+	}
+}
+
+// AllRules returns all built-in rules, with their default settings.
+func AllRules() []Rule {
+	return append(DefaultRules(),
+		NewKeyOrdering(),
+		NewEmptyValues(),
+		NewOctalValues(),
+		NewAmbiguousValues(),
+	)
+}
+
+// NewRule returns the built-in rule with the given name, with its default
+// settings. Returns [ErrUnknownRule] if there is no such rule.
+func NewRule(name string) (Rule, error) {
+	for _, r := range AllRules() {
+		if r.Name() == name {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownRule, name)
+}
+
+// RuleNames returns the names of all built-in rules, sorted.
+func RuleNames() []string {
+	var names []string
+	for _, r := range AllRules() {
+		names = append(names, r.Name())
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// walk calls fn for each node of file, with its parent node, in depth-first
+// order. The parent of document nodes is nil.
+func walk(file *ast.File, fn func(node, parent ast.Node)) {
+	if file == nil {
+		return
+	}
+
+	for _, doc := range file.Docs {
+		ast.Walk(parentVisitor{fn: fn}, doc)
+	}
+}
+
+// parentVisitor is an [ast.Visitor] tracking the parent of visited nodes.
+type parentVisitor struct {
+	parent ast.Node
+	fn     func(node, parent ast.Node)
+}
+
+// Visit implements [ast.Visitor].
+func (v parentVisitor) Visit(node ast.Node) ast.Visitor {
+	v.fn(node, v.parent)
+
+	return parentVisitor{parent: node, fn: v.fn}
+}
+
+// unwrap returns the node wrapped by anchor and tag nodes.
+func unwrap(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+// keyString returns the string value of a mapping key. Explicit keys ("? key")
+// use the value of the key, without the indicator or comments.
+func keyString(key ast.MapKeyNode) string {
+	var node ast.Node = key
+	if mk, ok := key.(*ast.MappingKeyNode); ok && mk.Value != nil {
+		node = mk.Value
+	}
+
+	if scalar, ok := unwrap(node).(ast.ScalarNode); ok {
+		if v := scalar.GetValue(); v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+
+	return node.String()
+}
+
+// plainScalars returns the indices of the plain (unquoted, untagged) scalar
+// tokens in tks, excluding the content of block scalars.
+func plainScalars(tks token.Tokens) []int {
+	var indices []int
+
+	for i, tk := range tks {
+		switch tk.Type {
+		case token.StringType, token.BoolType, token.IntegerType, token.BinaryIntegerType,
+			token.OctetIntegerType, token.HexIntegerType, token.FloatType, token.InfinityType,
+			token.NanType, token.NullType:
+		default:
+			continue
+		}
+
+		if i > 0 {
+			switch tks[i-1].Type {
+			case token.LiteralType, token.FoldedType, token.TagType:
+				continue
+			default:
+			}
+		}
+
+		indices = append(indices, i)
+	}
+
+	return indices
+}
+
+// isKey reports whether the token at index i of tks is a mapping key.
+func isKey(tks token.Tokens, i int) bool {
+	return i+1 < len(tks) && tks[i+1].Type == token.MappingValueType
+}
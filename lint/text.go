@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// LineLength reports lines longer than a maximum number of characters.
+//
+// Create instances with [NewLineLength].
+type LineLength struct {
+	// Max is the maximum number of characters per line. Zero or a negative
+	// value disables the rule. The default is 80.
+	Max int `yaml:"max"`
+	// AllowNonBreakableWords allows lines made of a single word, e.g. a long
+	// URL, optionally preceded by "- " or "# ". The default is true.
+	AllowNonBreakableWords bool `yaml:"allow-non-breakable-words"`
+}
+
+// NewLineLength creates a new [*LineLength] with the default settings.
+func NewLineLength() *LineLength {
+	return &LineLength{Max: 80, AllowNonBreakableWords: true}
+}
+
+// Name implements [Rule].
+func (r *LineLength) Name() string {
+	return "line-length"
+}
+
+// Check implements [Rule].
+func (r *LineLength) Check(p *Pass) {
+	if r.Max <= 0 {
+		return
+	}
+
+	for i, l := range p.Source().Lines() {
+		content := l.Content()
+
+		n := utf8.RuneCountInString(content)
+		if n <= r.Max {
+			continue
+		}
+
+		if r.AllowNonBreakableWords && isNonBreakable(content) {
+			continue
+		}
+
+		p.Report(
+			position.NewRange(position.New(i, r.Max), position.New(i, n)),
+			fmt.Sprintf("line too long (%d > %d characters)", n, r.Max),
+		)
+	}
+}
+
+// isNonBreakable reports whether the line is a single word after its
+// indentation and an optional "- " or "#" prefix.
+func isNonBreakable(content string) bool {
+	s := strings.TrimLeft(content, " ")
+
+	switch {
+	case strings.HasPrefix(s, "#"):
+		s = strings.TrimLeft(s, "#")
+		s = strings.TrimPrefix(s, " ")
+	case strings.HasPrefix(s, "- "):
+		s = s[2:]
+	}
+
+	return s != "" && !strings.ContainsAny(s, " \t")
+}
+
+// TrailingSpaces reports spaces and tabs at the end of lines.
+//
+// The lexer drops whitespace between a plain scalar and the end of its line,
+// so only trailing whitespace kept in the [niceyaml.Source] is reported, e.g.
+// after comments and in block scalars.
+//
+// Create instances with [NewTrailingSpaces].
+type TrailingSpaces struct{}
+
+// NewTrailingSpaces creates a new [*TrailingSpaces].
+func NewTrailingSpaces() *TrailingSpaces {
+	return &TrailingSpaces{}
+}
+
+// Name implements [Rule].
+func (r *TrailingSpaces) Name() string {
+	return "trailing-spaces"
+}
+
+// Check implements [Rule].
+func (r *TrailingSpaces) Check(p *Pass) {
+	for i, l := range p.Source().Lines() {
+		content := l.Content()
+
+		trimmed := strings.TrimRight(content, " \t")
+		if trimmed == content {
+			continue
+		}
+
+		rng := position.NewRange(
+			position.New(i, utf8.RuneCountInString(trimmed)),
+			position.New(i, utf8.RuneCountInString(content)),
+		)
+
+		p.Report(rng, "trailing spaces", niceyaml.WithSuggestions(niceyaml.Suggestion{
+			Message: "remove trailing spaces",
+			Range:   rng,
+		}))
+	}
+}
+
+// DocumentStart reports a missing, or a forbidden, document start marker
+// ("---") at the start of the source.
+//
+// Create instances with [NewDocumentStart].
+type DocumentStart struct {
+	// Present requires the marker if true, and forbids it otherwise. The
+	// default is true.
+	Present bool `yaml:"present"`
+}
+
+// NewDocumentStart creates a new [*DocumentStart] with the default settings.
+func NewDocumentStart() *DocumentStart {
+	return &DocumentStart{Present: true}
+}
+
+// Name implements [Rule].
+func (r *DocumentStart) Name() string {
+	return "document-start"
+}
+
+// Check implements [Rule].
+func (r *DocumentStart) Check(p *Pass) {
+	if !r.Present {
+		for _, tk := range p.Tokens() {
+			if tk.Type == token.DocumentHeaderType {
+				p.ReportToken(tk, `found forbidden document start "---"`)
+			}
+		}
+
+		return
+	}
+
+	for _, tk := range p.Tokens() {
+		switch tk.Type {
+		case token.CommentType:
+			continue
+		case token.DocumentHeaderType, token.DirectiveType:
+		default:
+			p.ReportToken(tk, `missing document start "---"`)
+		}
+
+		return
+	}
+}
+
+// Comments reports badly formatted comments.
+//
+// Create instances with [NewComments].
+type Comments struct {
+	// RequireStartingSpace requires a space after the "#" of comments, e.g.
+	// "# comment". The default is true.
+	RequireStartingSpace bool `yaml:"require-starting-space"`
+	// IgnoreShebangs ignores a "#!" comment at the very start of the source.
+	// The default is true.
+	IgnoreShebangs bool `yaml:"ignore-shebangs"`
+	// MinSpacesFromContent is the minimum number of spaces between content
+	// and a comment on the same line. Zero disables the check. The default
+	// is 2.
+	MinSpacesFromContent int `yaml:"min-spaces-from-content"`
+}
+
+// NewComments creates a new [*Comments] with the default settings.
+func NewComments() *Comments {
+	return &Comments{RequireStartingSpace: true, IgnoreShebangs: true, MinSpacesFromContent: 2}
+}
+
+// Name implements [Rule].
+func (r *Comments) Name() string {
+	return "comments"
+}
+
+// Check implements [Rule].
+func (r *Comments) Check(p *Pass) {
+	lines := p.Source().Lines()
+
+	for _, tk := range p.Tokens() {
+		if tk.Type != token.CommentType || tk.Position == nil {
+			continue
+		}
+
+		pos := position.NewFromToken(tk)
+
+		if r.RequireStartingSpace && !(r.IgnoreShebangs && pos == position.New(0, 0) && strings.HasPrefix(tk.Value, "!")) {
+			text := strings.TrimLeft(tk.Value, "#")
+			if text != "" && !strings.HasPrefix(text, " ") && !strings.HasPrefix(text, "\t") {
+				at := position.New(pos.Line, pos.Col+1+len(tk.Value)-len(text))
+				p.Report(p.TokenRange(tk), "missing starting space in comment",
+					niceyaml.WithSuggestions(niceyaml.Suggestion{
+						Message:     "add a space",
+						Replacement: " ",
+						Range:       position.NewRange(at, at),
+					}),
+				)
+			}
+		}
+
+		if r.MinSpacesFromContent <= 0 || pos.Line >= len(lines) {
+			continue
+		}
+
+		before := []rune(lines[pos.Line].Content())
+		before = before[:min(pos.Col, len(before))]
+
+		content := strings.TrimRight(string(before), " \t")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		if spaces := len(before) - utf8.RuneCountInString(content); spaces < r.MinSpacesFromContent {
+			p.Report(p.TokenRange(tk), "too few spaces before comment")
+		}
+	}
+}
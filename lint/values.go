@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// truthyValues are the plain scalars that YAML 1.1 parsers read as booleans.
+var truthyValues = []string{
+	"YES", "Yes", "yes", "NO", "No", "no",
+	"TRUE", "True", "true", "FALSE", "False", "false",
+	"ON", "On", "on", "OFF", "Off", "off",
+	"Y", "y", "N", "n",
+}
+
+// Truthy reports plain scalars read as booleans by YAML 1.1 parsers, such as
+// "yes" and "on", unless they are explicitly allowed. Quoted and tagged
+// scalars are not reported.
+//
+// Create instances with [NewTruthy].
+type Truthy struct {
+	// AllowedValues are the truthy values that are allowed. The default is
+	// "true" and "false".
+	AllowedValues []string `yaml:"allowed-values"`
+	// CheckKeys reports mapping keys too. The default is true.
+	CheckKeys bool `yaml:"check-keys"`
+}
+
+// NewTruthy creates a new [*Truthy] with the default settings.
+func NewTruthy() *Truthy {
+	return &Truthy{AllowedValues: []string{"true", "false"}, CheckKeys: true}
+}
+
+// Name implements [Rule].
+func (r *Truthy) Name() string {
+	return "truthy"
+}
+
+// Check implements [Rule].
+func (r *Truthy) Check(p *Pass) {
+	allowed := slices.Clone(r.AllowedValues)
+	slices.Sort(allowed)
+
+	tks := p.Tokens()
+
+	for _, i := range plainScalars(tks) {
+		tk := tks[i]
+		if !slices.Contains(truthyValues, tk.Value) || slices.Contains(allowed, tk.Value) {
+			continue
+		}
+
+		if !r.CheckKeys && isKey(tks, i) {
+			continue
+		}
+
+		p.ReportToken(tk, fmt.Sprintf("truthy value should be one of [%s]", strings.Join(allowed, ", ")))
+	}
+}
+
+var (
+	implicitOctal = regexp.MustCompile(`^0[0-7]+$`)
+	explicitOctal = regexp.MustCompile(`^0o[0-7]+$`)
+)
+
+// OctalValues reports plain scalars that are octal numbers, which YAML 1.1
+// and YAML 1.2 parsers read differently: "010" is 8 in YAML 1.1, but 10 in
+// YAML 1.2.
+//
+// Create instances with [NewOctalValues].
+type OctalValues struct {
+	// ForbidImplicitOctal reports octal numbers such as "010". The default
+	// is true.
+	ForbidImplicitOctal bool `yaml:"forbid-implicit-octal"`
+	// ForbidExplicitOctal reports octal numbers such as "0o10". The default
+	// is true.
+	ForbidExplicitOctal bool `yaml:"forbid-explicit-octal"`
+}
+
+// NewOctalValues creates a new [*OctalValues] with the default settings.
+func NewOctalValues() *OctalValues {
+	return &OctalValues{ForbidImplicitOctal: true, ForbidExplicitOctal: true}
+}
+
+// Name implements [Rule].
+func (r *OctalValues) Name() string {
+	return "octal-values"
+}
+
+// Check implements [Rule].
+func (r *OctalValues) Check(p *Pass) {
+	tks := p.Tokens()
+
+	for _, i := range plainScalars(tks) {
+		tk := tks[i]
+
+		switch {
+		case r.ForbidImplicitOctal && implicitOctal.MatchString(tk.Value):
+			p.ReportToken(tk, fmt.Sprintf("forbidden implicit octal value %q", tk.Value))
+		case r.ForbidExplicitOctal && explicitOctal.MatchString(tk.Value):
+			p.ReportToken(tk, fmt.Sprintf("forbidden explicit octal value %q", tk.Value))
+		}
+	}
+}
+
+// EmptyValues reports implicit null values, i.e. mapping keys and sequence
+// entries without a value.
+//
+// Create instances with [NewEmptyValues].
+type EmptyValues struct {
+	// ForbidInBlockMappings reports "key:" in block mappings. The default is
+	// true.
+	ForbidInBlockMappings bool `yaml:"forbid-in-block-mappings"`
+	// ForbidInFlowMappings reports "{key:}" in flow mappings. The default is
+	// true.
+	ForbidInFlowMappings bool `yaml:"forbid-in-flow-mappings"`
+	// ForbidInBlockSequences reports "-" without a value in block
+	// sequences. The default is true.
+	ForbidInBlockSequences bool `yaml:"forbid-in-block-sequences"`
+}
+
+// NewEmptyValues creates a new [*EmptyValues] with the default settings.
+func NewEmptyValues() *EmptyValues {
+	return &EmptyValues{
+		ForbidInBlockMappings:  true,
+		ForbidInFlowMappings:   true,
+		ForbidInBlockSequences: true,
+	}
+}
+
+// Name implements [Rule].
+func (r *EmptyValues) Name() string {
+	return "empty-values"
+}
+
+// Check implements [Rule].
+func (r *EmptyValues) Check(p *Pass) {
+	// Implicit nulls are added by the parser, so they have no source token.
+	explicit := map[int]bool{}
+
+	for _, tk := range p.Tokens() {
+		if tk.Type == token.NullType && tk.Position != nil {
+			explicit[tk.Position.Offset] = true
+		}
+	}
+
+	isEmpty := func(node ast.Node) bool {
+		null, ok := node.(*ast.NullNode)
+
+		return ok && (null.Token == nil || null.Token.Position == nil || !explicit[null.Token.Position.Offset])
+	}
+
+	walk(p.File(), func(node, parent ast.Node) {
+		switch n := node.(type) {
+		case *ast.MappingValueNode:
+			if !isEmpty(n.Value) {
+				return
+			}
+
+			if m, ok := parent.(*ast.MappingNode); ok && m.IsFlowStyle {
+				if r.ForbidInFlowMappings {
+					p.ReportToken(n.Key.GetToken(), "empty value in flow mapping")
+				}
+			} else if r.ForbidInBlockMappings {
+				p.ReportToken(n.Key.GetToken(), "empty value in block mapping")
+			}
+		case *ast.SequenceNode:
+			if n.IsFlowStyle || !r.ForbidInBlockSequences {
+				return
+			}
+
+			for _, entry := range n.Entries {
+				if isEmpty(entry.Value) {
+					p.ReportToken(entry.Start, "empty value in block sequence")
+				}
+			}
+		}
+	})
+}
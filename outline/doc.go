@@ -0,0 +1,52 @@
+// Package outline builds a structural tree of mapping keys and sequence items
+// from a parsed YAML document.
+//
+// A [line.Lines] collection is flat: it knows which tokens appear on each line,
+// but not which lines belong to which mapping entry. Features like folding,
+// tree navigation, and "go to key" need that structure, along with the line
+// span each entry occupies.
+//
+// [New] walks an [*ast.File] and produces an [Outline] with one root [Node] per
+// document. Each [Node] below a document root represents a mapping entry or a
+// sequence item and records:
+//
+//   - The [position.Span] of lines it occupies, from the key (or "-") line to
+//     the last line of its value.
+//   - A [*paths.Path] locating it within the document.
+//   - The [Kind] of its value (mapping, sequence, scalar, alias, or null).
+//
+// For example:
+//
+//	┌───────────────────┐
+//	│metadata:          │  metadata       [0, 3)  mapping
+//	│  name: app        │  ├─ name        [1, 2)  scalar
+//	│  labels: {a: b}   │  └─ labels      [2, 3)  mapping
+//	│items:             │  items          [3, 5)  sequence
+//	│  - one            │  └─ [0]         [4, 5)  scalar
+//	└───────────────────┘
+//
+// # Spans
+//
+// A node's span ends where its next sibling begins, or at the end of its
+// parent's span for the last sibling. Trailing blank and comment-only lines
+// are excluded, so a comment introducing the next entry is not folded into the
+// previous one.
+//
+// # Usage
+//
+// Build an outline from a [niceyaml.Source] with [niceyaml.Source.Outline], or
+// directly from an [*ast.File] and its [line.Lines]:
+//
+//	o, err := source.Outline()
+//	if err != nil {
+//		return err
+//	}
+//
+//	for n := range o.All() {
+//		fmt.Printf("%s%s %s\n", strings.Repeat("  ", n.Depth-1), n.Label(), n.Span)
+//	}
+//
+// Use [Outline.Enclosing] to find the chain of nodes containing a line (for
+// breadcrumbs or sticky headers), [Outline.At] for the innermost node at a
+// line, and [Outline.Lookup] to find a node by its YAML path.
+package outline
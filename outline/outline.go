@@ -0,0 +1,446 @@
+package outline
+
+import (
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// Kind identifies the kind of value held by a [Node].
+type Kind int
+
+const (
+	// KindNull indicates an explicit or implicit null value.
+	KindNull Kind = iota
+	// KindScalar indicates a scalar value (string, number, boolean, etc.).
+	KindScalar
+	// KindMapping indicates a block or flow mapping.
+	KindMapping
+	// KindSequence indicates a block or flow sequence.
+	KindSequence
+	// KindAlias indicates an alias (*name) to an anchored value.
+	KindAlias
+)
+
+// String returns the lowercase name of the [Kind].
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindScalar:
+		return "scalar"
+	case KindMapping:
+		return "mapping"
+	case KindSequence:
+		return "sequence"
+	case KindAlias:
+		return "alias"
+	default:
+		return "unknown"
+	}
+}
+
+// Node is an entry in an [Outline].
+//
+// Document roots have no [Node.Parent] and a [Node.Depth] of 0. Every other
+// node is either a mapping entry (with a [Node.Key]) or a sequence item (with
+// an [Node.Index]).
+type Node struct {
+	// Parent is the enclosing node, or nil for document roots.
+	Parent *Node
+	// Path locates the node within its document. Mapping entries target the
+	// key; sequence items and document roots target the value.
+	// May be nil if the path could not be represented.
+	Path *paths.Path
+	// Key is the mapping key, or empty for sequence items and document roots.
+	Key string
+	// Anchor is the anchor name attached to the value, if any.
+	Anchor string
+	// Children holds nested mapping entries or sequence items, in order.
+	Children []*Node
+	// Pos is the position of the key, the sequence entry indicator, or the
+	// start of the document.
+	Pos position.Position
+	// Span is the half-open range of line indices occupied by the node.
+	Span position.Span
+	// Index is the sequence item index for sequence items, the document index
+	// for document roots, and -1 for mapping entries.
+	Index int
+	// Depth is the nesting depth: 0 for document roots, 1 for top-level
+	// entries, and so on.
+	Depth int
+	// Kind is the kind of value held by the node.
+	Kind Kind
+}
+
+// IsDocument reports whether the [Node] is a document root.
+func (n *Node) IsDocument() bool {
+	return n.Parent == nil
+}
+
+// IsFoldable reports whether the [Node] spans more than one line.
+func (n *Node) IsFoldable() bool {
+	return n.Span.Len() > 1
+}
+
+// Label returns a short display label: the key for mapping entries, "[i]" for
+// sequence items, and "---" for document roots.
+func (n *Node) Label() string {
+	switch {
+	case n.IsDocument():
+		return "---"
+	case n.Index >= 0:
+		return "[" + strconv.Itoa(n.Index) + "]"
+	default:
+		return n.Key
+	}
+}
+
+// Outline is a tree of [Node]s describing the structure of one or more YAML
+// documents.
+//
+// Create instances with [New].
+type Outline struct {
+	docs []*Node
+}
+
+// New creates a new [*Outline] from a parsed [*ast.File] and the [line.Lines]
+// it was parsed from.
+//
+// Token positions in file are mapped to 0-indexed line indices in lines, so
+// both must describe the same content. The lines are also used to exclude
+// trailing blank and comment-only lines from each node's span.
+//
+// Returns an empty outline if file is nil.
+func New(file *ast.File, lines line.Lines) *Outline {
+	o := &Outline{}
+	if file == nil {
+		return o
+	}
+
+	b := builder{lines: lines}
+
+	for i, doc := range file.Docs {
+		root := &Node{
+			Index: i,
+			Kind:  KindNull,
+			Path:  paths.Root().Value(),
+		}
+
+		switch {
+		case doc.Start != nil:
+			root.Pos = position.NewFromToken(doc.Start)
+		case i == 0:
+			root.Pos = position.New(0, 0)
+		case doc.Body != nil:
+			root.Pos = position.NewFromToken(doc.Body.GetToken())
+		}
+
+		if doc.Body != nil {
+			root.Kind, root.Anchor = b.addValue(root, doc.Body)
+		}
+
+		o.docs = append(o.docs, root)
+	}
+
+	// Each document ends where the next begins.
+	for i, root := range o.docs {
+		end := len(lines)
+		if i+1 < len(o.docs) {
+			end = o.docs[i+1].Pos.Line
+		}
+
+		root.Span = position.NewSpan(root.Pos.Line, max(root.Pos.Line+1, end))
+		b.assignSpans(root.Children, root.Span.End)
+	}
+
+	return o
+}
+
+// Documents returns the document root [Node]s, in order.
+func (o *Outline) Documents() []*Node {
+	return o.docs
+}
+
+// All returns an iterator over all non-document [Node]s in depth-first,
+// pre-order traversal (document order).
+func (o *Outline) All() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		for _, doc := range o.docs {
+			if !walk(doc.Children, yield) {
+				return
+			}
+		}
+	}
+}
+
+// walk yields nodes and their descendants in pre-order.
+// Returns false if iteration was stopped.
+func walk(nodes []*Node, yield func(*Node) bool) bool {
+	for _, n := range nodes {
+		if !yield(n) || !walk(n.Children, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Enclosing returns the chain of non-document [Node]s whose span contains the
+// given 0-indexed line, outermost first.
+//
+// Returns nil if no node contains the line.
+func (o *Outline) Enclosing(lineIdx int) []*Node {
+	var chain []*Node
+
+	for _, doc := range o.docs {
+		if !doc.Span.Contains(lineIdx) {
+			continue
+		}
+
+		nodes := doc.Children
+		for {
+			next := findContaining(nodes, lineIdx)
+			if next == nil {
+				break
+			}
+
+			chain = append(chain, next)
+			nodes = next.Children
+		}
+
+		break
+	}
+
+	return chain
+}
+
+// findContaining returns the last node whose span contains lineIdx.
+// Later siblings win so that flow entries sharing a line resolve to the
+// innermost structure on that line consistently.
+func findContaining(nodes []*Node, lineIdx int) *Node {
+	var found *Node
+
+	for _, n := range nodes {
+		if n.Span.Start > lineIdx {
+			break
+		}
+
+		if n.Span.Contains(lineIdx) {
+			found = n
+		}
+	}
+
+	return found
+}
+
+// At returns the innermost non-document [Node] whose span contains the given
+// 0-indexed line.
+//
+// Returns nil if no node contains the line.
+func (o *Outline) At(lineIdx int) *Node {
+	chain := o.Enclosing(lineIdx)
+	if len(chain) == 0 {
+		return nil
+	}
+
+	return chain[len(chain)-1]
+}
+
+// Lookup returns the first [Node] whose YAML path matches the given path
+// expression (e.g. "$.metadata.name" or "$.items[0]"), searching all
+// documents in order.
+//
+// Returns nil if no node matches.
+func (o *Outline) Lookup(path string) *Node {
+	for n := range o.All() {
+		if n.Path != nil && n.Path.Path().String() == path {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// builder accumulates nodes while walking the AST.
+type builder struct {
+	lines line.Lines
+}
+
+// addValue adds child nodes for the given value node to parent, unwrapping
+// anchors and tags.
+//
+// Returns the [Kind] of the value and the anchor name attached to it, if any.
+func (b *builder) addValue(parent *Node, value ast.Node) (Kind, string) {
+	value, anchor := unwrap(value)
+
+	switch n := value.(type) {
+	case nil, *ast.NullNode:
+		return KindNull, anchor
+
+	case *ast.AliasNode:
+		return KindAlias, anchor
+
+	case *ast.MappingNode:
+		for _, mv := range n.Values {
+			b.addMappingValue(parent, mv)
+		}
+
+		return KindMapping, anchor
+
+	case *ast.MappingValueNode:
+		// A single-entry mapping may be represented without a MappingNode.
+		b.addMappingValue(parent, n)
+
+		return KindMapping, anchor
+
+	case *ast.SequenceNode:
+		for i, item := range n.Values {
+			var entry *token.Token
+			if i < len(n.Entries) && n.Entries[i] != nil {
+				entry = n.Entries[i].Start
+			}
+
+			b.addSequenceItem(parent, i, entry, item)
+		}
+
+		return KindSequence, anchor
+
+	default:
+		return KindScalar, anchor
+	}
+}
+
+// addMappingValue adds a mapping entry node to parent.
+func (b *builder) addMappingValue(parent *Node, mv *ast.MappingValueNode) {
+	keyTk := mapKeyToken(mv.Key)
+
+	n := &Node{
+		Parent: parent,
+		Key:    keyTk.Value,
+		Index:  -1,
+		Depth:  parent.Depth + 1,
+		Pos:    position.NewFromToken(keyTk),
+		Path:   nodePath(mv, true),
+	}
+
+	n.Kind, n.Anchor = b.addValue(n, mv.Value)
+	parent.Children = append(parent.Children, n)
+}
+
+// addSequenceItem adds a sequence item node to parent.
+// The entry token is the "-" indicator, or nil for flow sequences.
+func (b *builder) addSequenceItem(parent *Node, idx int, entry *token.Token, item ast.Node) {
+	posTk := entry
+	if posTk == nil && item != nil {
+		posTk = item.GetToken()
+	}
+
+	n := &Node{
+		Parent: parent,
+		Index:  idx,
+		Depth:  parent.Depth + 1,
+		Pos:    position.NewFromToken(posTk),
+	}
+
+	if item != nil {
+		n.Path = nodePath(item, false)
+	}
+
+	n.Kind, n.Anchor = b.addValue(n, item)
+	parent.Children = append(parent.Children, n)
+}
+
+// assignSpans sets the span of each sibling node, then recurses into children.
+// Each node ends where its next sibling starts, or at end for the last
+// sibling, excluding trailing blank and comment-only lines.
+func (b *builder) assignSpans(nodes []*Node, end int) {
+	for i, n := range nodes {
+		start := n.Pos.Line
+
+		next := end
+		if i+1 < len(nodes) {
+			next = min(end, nodes[i+1].Pos.Line)
+		}
+
+		for next > start+1 && b.isTrivia(next-1) {
+			next--
+		}
+
+		n.Span = position.NewSpan(start, max(start+1, next))
+		b.assignSpans(n.Children, n.Span.End)
+	}
+}
+
+// isTrivia reports whether the line at idx has only comment tokens, or parts
+// of tokens without text, such as the line breaks of multi-line tokens.
+// Lines of block scalars are not trivia, even if they start with "#".
+func (b *builder) isTrivia(idx int) bool {
+	if idx < 0 || idx >= len(b.lines) {
+		return false
+	}
+
+	for _, tk := range b.lines[idx].Tokens() {
+		if tk.Type != token.CommentType && strings.TrimSpace(tk.Origin) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unwrap strips anchor and tag nodes from value, returning the underlying node
+// and the name of the innermost anchor, if any.
+func unwrap(value ast.Node) (ast.Node, string) {
+	var anchor string
+
+	for {
+		switch n := value.(type) {
+		case *ast.AnchorNode:
+			if n.Name != nil {
+				anchor = n.Name.GetToken().Value
+			}
+
+			value = n.Value
+
+		case *ast.TagNode:
+			value = n.Value
+
+		default:
+			return value, anchor
+		}
+	}
+}
+
+// mapKeyToken returns the token holding the text of a mapping key.
+func mapKeyToken(key ast.MapKeyNode) *token.Token {
+	if mk, ok := key.(*ast.MappingKeyNode); ok && mk.Value != nil {
+		return mk.Value.GetToken()
+	}
+
+	return key.GetToken()
+}
+
+// nodePath converts the node's YAML path to a [*paths.Path] targeting either
+// the key or the value.
+//
+// Returns nil if the path cannot be parsed.
+func nodePath(n ast.Node, targetKey bool) *paths.Path {
+	b, err := paths.FromString(n.GetPath())
+	if err != nil {
+		return nil
+	}
+
+	if targetKey {
+		return b.Key()
+	}
+
+	return b.Value()
+}
@@ -0,0 +1,287 @@
+package outline_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/outline"
+)
+
+// render formats each node of the outline as "<indent><label> <span> <kind>".
+func render(o *outline.Outline) []string {
+	var out []string
+
+	for _, doc := range o.Documents() {
+		out = append(out, fmt.Sprintf("%s %s %s", doc.Label(), doc.Span, doc.Kind))
+
+		for n := range walk(doc) {
+			out = append(out, fmt.Sprintf("%s%s %s %s",
+				strings.Repeat("  ", n.Depth), n.Label(), n.Span, n.Kind))
+		}
+	}
+
+	return out
+}
+
+func walk(doc *outline.Node) func(func(*outline.Node) bool) {
+	return func(yield func(*outline.Node) bool) {
+		var rec func(nodes []*outline.Node) bool
+
+		rec = func(nodes []*outline.Node) bool {
+			for _, n := range nodes {
+				if !yield(n) || !rec(n.Children) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		rec(doc.Children)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		input string
+		want  []string
+	}{
+		"nested mapping": {
+			input: stringtest.Input(`
+				metadata:
+				  name: app
+				  labels: {a: b}
+				items:
+				  - one
+				  - two
+			`),
+			want: []string{
+				"--- 0-6 mapping",
+				"  metadata 0-3 mapping",
+				"    name 1-2 scalar",
+				"    labels 2-3 mapping",
+				"      a 2-3 scalar",
+				"  items 3-6 sequence",
+				"    [0] 4-5 scalar",
+				"    [1] 5-6 scalar",
+			},
+		},
+		"sequence of mappings": {
+			input: stringtest.Input(`
+				- name: a
+				  value: 1
+				- name: b
+			`),
+			want: []string{
+				"--- 0-3 sequence",
+				"  [0] 0-2 mapping",
+				"    name 0-1 scalar",
+				"    value 1-2 scalar",
+				"  [1] 2-3 mapping",
+				"    name 2-3 scalar",
+			},
+		},
+		"trailing comments excluded": {
+			input: stringtest.Input(`
+				a:
+				  b: 1
+
+				# About c.
+				c: 2
+			`),
+			want: []string{
+				"--- 0-5 mapping",
+				"  a 0-2 mapping",
+				"    b 1-2 scalar",
+				"  c 4-5 scalar",
+			},
+		},
+		"block scalar": {
+			input: stringtest.Input(`
+				script: |
+				  echo one
+				  echo two
+				next: ~
+			`),
+			want: []string{
+				"--- 0-4 mapping",
+				"  script 0-3 scalar",
+				"  next 3-4 null",
+			},
+		},
+		"block scalar ending with hash lines": {
+			input: stringtest.Input(`
+				run: |
+				  echo one
+				  # not a comment
+				next: ~
+			`),
+			want: []string{
+				"--- 0-4 mapping",
+				"  run 0-3 scalar",
+				"  next 3-4 null",
+			},
+		},
+		"anchors and aliases": {
+			input: stringtest.Input(`
+				base: &base
+				  x: 1
+				copy: *base
+			`),
+			want: []string{
+				"--- 0-3 mapping",
+				"  base 0-2 mapping",
+				"    x 1-2 scalar",
+				"  copy 2-3 alias",
+			},
+		},
+		"multiple documents": {
+			input: stringtest.Input(`
+				a: 1
+				---
+				b:
+				  - 2
+			`),
+			want: []string{
+				"--- 0-1 mapping",
+				"  a 0-1 scalar",
+				"--- 1-4 mapping",
+				"  b 2-4 sequence",
+				"    [0] 3-4 scalar",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(tc.input)
+
+			o, err := source.Outline()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, render(o))
+		})
+	}
+}
+
+func TestNew_NilFile(t *testing.T) {
+	t.Parallel()
+
+	o := outline.New(nil, nil)
+
+	assert.Empty(t, o.Documents())
+	assert.Nil(t, o.At(0))
+}
+
+func TestNode(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		spec: &spec
+		  containers:
+		    - name: app
+	`))
+
+	o, err := source.Outline()
+	require.NoError(t, err)
+
+	spec := o.Lookup("$.spec")
+	require.NotNil(t, spec)
+
+	assert.Equal(t, "spec", spec.Key)
+	assert.Equal(t, "spec", spec.Anchor)
+	assert.Equal(t, -1, spec.Index)
+	assert.Equal(t, 1, spec.Depth)
+	assert.Equal(t, "$.spec.(key)", spec.Path.String())
+	assert.True(t, spec.IsFoldable())
+	assert.False(t, spec.IsDocument())
+	assert.True(t, spec.Parent.IsDocument())
+
+	item := o.Lookup("$.spec.containers[0]")
+	require.NotNil(t, item)
+
+	assert.Equal(t, "[0]", item.Label())
+	assert.Equal(t, 0, item.Index)
+	assert.Equal(t, 4, item.Pos.Col)
+	assert.Equal(t, "$.spec.containers[0].(value)", item.Path.String())
+	assert.False(t, item.IsFoldable())
+
+	assert.Nil(t, o.Lookup("$.missing"))
+}
+
+func TestOutline_Enclosing(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		a:
+		  b:
+		    c: 1
+		d: 2
+	`))
+
+	o, err := source.Outline()
+	require.NoError(t, err)
+
+	labels := func(nodes []*outline.Node) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.Label())
+		}
+
+		return out
+	}
+
+	assert.Equal(t, []string{"a"}, labels(o.Enclosing(0)))
+	assert.Equal(t, []string{"a", "b", "c"}, labels(o.Enclosing(2)))
+	assert.Equal(t, []string{"d"}, labels(o.Enclosing(3)))
+	assert.Empty(t, o.Enclosing(10))
+
+	require.NotNil(t, o.At(2))
+	assert.Equal(t, "c", o.At(2).Key)
+	assert.Nil(t, o.At(10))
+}
+
+func TestOutline_All(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		a:
+		  b: 1
+		c: 2
+	`))
+
+	o, err := source.Outline()
+	require.NoError(t, err)
+
+	var keys []string
+	for n := range o.All() {
+		keys = append(keys, n.Key)
+		if n.Key == "b" {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestKind_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "null", outline.KindNull.String())
+	assert.Equal(t, "scalar", outline.KindScalar.String())
+	assert.Equal(t, "mapping", outline.KindMapping.String())
+	assert.Equal(t, "sequence", outline.KindSequence.String())
+	assert.Equal(t, "alias", outline.KindAlias.String())
+	assert.Equal(t, "unknown", outline.Kind(99).String())
+}
@@ -73,13 +73,27 @@ type WrappingPrinter interface {
 // contextual notes. The printer renders them via [AnnotationFunc], defaulting
 // to [DefaultAnnotation] which prefixes below-line annotations with "^ ".
 //
+// Labels (see [line.Annotation.IsLabel]) are rendered by the printer itself:
+// single-line labels underline their columns, followed by their message, and
+// multi-line labels are connected by arrows drawn in a margin between the
+// gutter and the content. The margin is only present when multi-line labels
+// are printed. Labels use the foreground of their [line.Annotation.Kind].
+//
 // # Word Wrapping
 //
 // Call [Printer.SetWidth] to enable word wrapping at a given width. The printer
 // accounts for gutter width when calculating available content width. Wrapped
 // continuation lines show a "-" marker in the gutter.
+//
+// # Folding
+//
+// Set [Folds] via [WithFolds] or [Printer.SetFolds] to collapse outline nodes.
+// A folded node renders as its first line followed by a placeholder, e.g.
+// "key: … (42 lines)", and its remaining lines are skipped. Gutters still
+// receive the original line numbers.
 type Printer struct {
 	styles             StyleGetter
+	folds              *Folds
 	style              lipgloss.Style
 	gutterFunc         GutterFunc
 	annotationFunc     AnnotationFunc
@@ -121,10 +135,14 @@ func NewPrinter(opts ...PrinterOption) *Printer {
 //   - [WithStyles]
 //   - [WithGutter]
 //   - [WithAnnotationFunc]
+//   - [WithFolds]
 type PrinterOption func(*Printer)
 
 // GutterContext provides context about the current line for gutter rendering.
 // It is passed to [GutterFunc] to determine the appropriate gutter content.
+//
+// Index and Number always refer to the original line, even when preceding
+// lines are hidden by [Folds].
 type GutterContext struct {
 	Styles     StyleGetter
 	Index      int
@@ -132,6 +150,8 @@ type GutterContext struct {
 	TotalLines int
 	Flag       line.Flag
 	Soft       bool
+	// Folded is true if the line is the first line of a folded node.
+	Folded bool
 }
 
 // GutterFunc returns the gutter content for a line based on [GutterContext].
@@ -290,6 +310,20 @@ func WithAnnotationFunc(fn AnnotationFunc) PrinterOption {
 	}
 }
 
+// WithFolds is a [PrinterOption] that sets the [Folds] used to collapse
+// outline nodes when printing.
+func WithFolds(f *Folds) PrinterOption {
+	return func(p *Printer) {
+		p.folds = f
+	}
+}
+
+// SetFolds sets the [Folds] used to collapse outline nodes when printing.
+// Pass nil to disable folding.
+func (p *Printer) SetFolds(f *Folds) {
+	p.folds = f
+}
+
 // SetWidth sets the width for word wrapping.
 // A width of 0 disables wrapping.
 func (p *Printer) SetWidth(width int) {
@@ -321,11 +355,14 @@ func (p *Printer) Style(s style.Style) *lipgloss.Style {
 func (p *Printer) Print(lines LineIterator, spans ...position.Span) string {
 	if len(spans) == 0 {
 		// No spans specified, print all lines.
-		content := p.renderLinesInSpan(lines, position.NewSpan(0, lines.Len()))
+		span := position.NewSpan(0, lines.Len())
+		content := p.renderLinesInSpan(lines, span, p.newLabelMargin(lines, []position.Span{span}))
 
 		return p.style.Render(content)
 	}
 
+	margin := p.newLabelMargin(lines, spans)
+
 	var sb strings.Builder
 
 	for i, span := range spans {
@@ -333,14 +370,15 @@ func (p *Printer) Print(lines LineIterator, spans ...position.Span) string {
 			sb.WriteByte('\n')
 		}
 
-		sb.WriteString(p.renderLinesInSpan(lines, span))
+		sb.WriteString(p.renderLinesInSpan(lines, span, margin))
 	}
 
 	return p.style.Render(sb.String())
 }
 
 // renderLinesInSpan renders lines in the given span.
-func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
+// The margin holds the arrows of multi-line labels, see [labelMargin].
+func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span, margin *labelMargin) string {
 	if t.IsEmpty() {
 		return ""
 	}
@@ -355,6 +393,9 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 		gutterWidth = lipgloss.Width(sampleGutter)
 	}
 
+	// The label margin is part of the gutter for wrapping.
+	gutterWidth += margin.width()
+
 	var (
 		sb          strings.Builder
 		renderedIdx int
@@ -367,7 +408,27 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 	deletedStyle := p.styles.Style(style.GenericDeleted)
 	insertedStyle := p.styles.Style(style.GenericInserted)
 
+	var folded position.Spans
+	if p.folds != nil {
+		folded = p.folds.Spans()
+	}
+
 	for pos, ln := range t.AllLines(span) {
+		// Skip folds that end before this line.
+		for len(folded) > 0 && folded[0].End <= pos.Line {
+			folded = folded[1:]
+		}
+
+		foldLen := 0
+
+		if len(folded) > 0 && folded[0].Contains(pos.Line) {
+			if folded[0].Start != pos.Line {
+				continue // Hidden by a fold.
+			}
+
+			foldLen = folded[0].Len()
+		}
+
 		lineNum := ln.Number()
 
 		var (
@@ -387,7 +448,7 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 			}
 
 			// Render annotation above the line.
-			p.renderAnnotation(&sb, ln, pos, lineNum, totalLines, line.Above, gutterWidth)
+			p.renderAnnotation(&sb, ln, pos, lineNum, totalLines, line.Above, gutterWidth, margin)
 			sb.WriteByte('\n')
 		} else if renderedIdx > 0 {
 			// Add newline between lines within a hunk.
@@ -401,6 +462,7 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 			Soft:       false,
 			Flag:       ln.Flag,
 			Styles:     p.styles,
+			Folded:     foldLen > 0,
 		}
 
 		linePos := position.New(pos.Line, 0)
@@ -412,24 +474,28 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 
 		switch ln.Flag {
 		case line.FlagDeleted:
-			content = ln.Content()
+			content = ln.Content() + foldPlaceholder(foldLen)
 			contentStyle = deletedStyle
 
 		case line.FlagInserted:
-			content = ln.Content()
+			content = ln.Content() + foldPlaceholder(foldLen)
 			contentStyle = insertedStyle
 
 		default: // FlagDefault (equal line).
 			// Render with syntax highlighting.
 			content = p.renderTokenLine(pos.Line, ln)
+			if foldLen > 0 {
+				content += p.styles.Style(style.TextSubtle).Render(foldPlaceholder(foldLen))
+			}
+
 			contentStyle = nil
 		}
 
-		p.writeLine(&sb, content, linePos, contentStyle, ln.Overlays, gutterCtx, gutterWidth)
+		p.writeLine(&sb, content, linePos, contentStyle, ln.Overlays, gutterCtx, gutterWidth, margin)
 
 		if hasBelowAnnotation {
 			sb.WriteByte('\n')
-			p.renderAnnotation(&sb, ln, pos, lineNum, totalLines, line.Below, gutterWidth)
+			p.renderAnnotation(&sb, ln, pos, lineNum, totalLines, line.Below, gutterWidth, margin)
 		}
 
 		renderedIdx++
@@ -438,10 +504,22 @@ func (p *Printer) renderLinesInSpan(t LineIterator, span position.Span) string {
 	return sb.String()
 }
 
+// foldPlaceholder returns the text appended to the first line of a fold
+// spanning n lines, or an empty string if n is 0.
+func foldPlaceholder(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" … (%d lines)", n)
+}
+
 // renderAnnotation renders annotation lines with gutter padding for the given
 // position.
 //
-// The annotation content uses [AnnotationFunc] for rendering.
+// The annotation content uses [AnnotationFunc] for rendering. Labels (see
+// [line.Annotation.IsLabel]) are rendered after other annotations, with their
+// underlines and styles.
 //
 // The gutterWidth parameter enables width calculation for wrapping.
 func (p *Printer) renderAnnotation(
@@ -451,12 +529,53 @@ func (p *Printer) renderAnnotation(
 	lineNum, totalLines int,
 	relPos line.RelativePosition,
 	gutterWidth int,
+	margin *labelMargin,
 ) {
-	anns := ln.Annotations.FilterPosition(relPos)
-	if len(anns) == 0 {
-		return
+	var (
+		anns   line.Annotations
+		labels []labelAnnotation
+	)
+
+	for i, ann := range ln.Annotations {
+		switch {
+		case ann.Position != relPos:
+		case ann.IsLabel():
+			labels = append(labels, labelAnnotation{ann: ann, index: i})
+		default:
+			anns = append(anns, ann)
+		}
 	}
 
+	gutterCtx := GutterContext{
+		Index:      pos.Line,
+		Number:     lineNum,
+		TotalLines: totalLines,
+		Flag:       line.FlagAnnotation,
+		Styles:     p.styles,
+	}
+
+	var rows []string
+
+	if len(anns) > 0 {
+		rows = p.renderAnnotationRows(anns, pos, relPos, margin, gutterCtx, gutterWidth)
+	}
+
+	if len(labels) > 0 {
+		rows = append(rows, p.renderLabels(labels, pos, relPos, margin, gutterCtx, gutterWidth)...)
+	}
+
+	sb.WriteString(strings.Join(rows, "\n"))
+}
+
+// renderAnnotationRows renders the rows of annotations that are not labels.
+func (p *Printer) renderAnnotationRows(
+	anns line.Annotations,
+	pos position.Position,
+	relPos line.RelativePosition,
+	margin *labelMargin,
+	gutterCtx GutterContext,
+	gutterWidth int,
+) []string {
 	annCtx := AnnotationContext{
 		Annotations: anns,
 		Position:    relPos,
@@ -464,7 +583,7 @@ func (p *Printer) renderAnnotation(
 	}
 	content := p.annotationFunc(annCtx)
 	if content == "" {
-		return
+		return nil
 	}
 
 	subLines := p.wrapContent(content, gutterWidth)
@@ -477,20 +596,20 @@ func (p *Printer) renderAnnotation(
 		continuationPadding += "  " // Align with text after "^ ".
 	}
 
+	row := marginAbove
+	if relPos == line.Below {
+		row = marginBelow
+	}
+
+	rows := make([]string, 0, len(subLines))
+
 	for j, subLine := range subLines {
-		if j > 0 {
-			sb.WriteByte('\n')
-		}
+		var sb strings.Builder
 
-		gutterCtx := GutterContext{
-			Index:      pos.Line,
-			Number:     lineNum,
-			TotalLines: totalLines,
-			Soft:       j > 0,
-			Flag:       line.FlagAnnotation,
-			Styles:     p.styles,
-		}
-		sb.WriteString(p.gutterFunc(gutterCtx))
+		ctx := gutterCtx
+		ctx.Soft = j > 0
+		sb.WriteString(p.gutterFunc(ctx))
+		sb.WriteString(margin.render(pos.Line, row, nil, false))
 
 		// Add continuation padding for wrapped lines.
 		if j > 0 {
@@ -498,7 +617,10 @@ func (p *Printer) renderAnnotation(
 		}
 
 		sb.WriteString(p.styles.Style(style.Comment).Render(ansi.Escape(subLine)))
+		rows = append(rows, sb.String())
 	}
+
+	return rows
 }
 
 // writeLine writes a line with optional word wrapping.
@@ -512,6 +634,7 @@ func (p *Printer) writeLine(
 	overlays line.Overlays,
 	gutterCtx GutterContext,
 	gutterWidth int,
+	margin *labelMargin,
 ) {
 	subLines := p.wrapContent(content, gutterWidth)
 
@@ -526,6 +649,13 @@ func (p *Printer) writeLine(
 		gutter := p.gutterFunc(ctx)
 		sb.WriteString(gutter)
 
+		row := marginLine
+		if ctx.Soft {
+			row = marginSoft
+		}
+
+		sb.WriteString(margin.render(pos.Line, row, nil, false))
+
 		// Write content.
 		if contentStyle != nil {
 			// For diff lines: apply diff style to content.
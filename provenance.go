@@ -0,0 +1,319 @@
+package niceyaml
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// FieldLocation is the location in a YAML document of a value decoded into a
+// Go field.
+type FieldLocation struct {
+	// Path is the YAML path of the value.
+	Path *paths.Path
+	// Range is the range of the value in the source. For mappings and
+	// sequences, this is the range of their key, or of their first token if
+	// they have no key, e.g. in a sequence.
+	Range position.Range
+	// Token of the value, or of the key of mappings and sequences.
+	token *token.Token
+}
+
+// Provenance maps the Go fields of a decoded value to their locations in the
+// YAML document, so that problems found after decoding, e.g. by
+// application-level validation, can be reported at the exact YAML value:
+//
+//	var cfg Config
+//	prov, err := doc.UnmarshalWithProvenance(&cfg)
+//	if err != nil {
+//		return err
+//	}
+//	if cfg.Spec.Replicas > 10 {
+//		return prov.Error("Spec.Replicas", "too many replicas")
+//	}
+//
+// Fields are identified by Go field paths relative to the decoded value, made
+// of field names separated by dots, with sequence indices and map keys in
+// brackets, e.g. "Spec.Containers[0].Image" or "Labels[tier]". Fields of
+// embedded structs inlined with the "inline" tag option are promoted, as in
+// Go.
+//
+// Only values present in the document are included.
+//
+// Create instances with [DocumentDecoder.Provenance] or
+// [DocumentDecoder.UnmarshalWithProvenance].
+type Provenance struct {
+	source *Source
+	fields map[string]FieldLocation
+}
+
+// Lookup returns the [FieldLocation] of the Go field path.
+// Returns false if the field has no value in the document.
+func (p *Provenance) Lookup(field string) (FieldLocation, bool) {
+	loc, ok := p.fields[field]
+
+	return loc, ok
+}
+
+// Fields returns the Go field paths with values in the document, in document
+// order.
+func (p *Provenance) Fields() []string {
+	fields := slices.Collect(maps.Keys(p.fields))
+
+	slices.SortFunc(fields, func(a, b string) int {
+		ra, rb := p.fields[a].Range.Start, p.fields[b].Range.Start
+
+		return cmp.Or(
+			cmp.Compare(ra.Line, rb.Line),
+			cmp.Compare(ra.Col, rb.Col),
+			cmp.Compare(a, b),
+		)
+	})
+
+	return fields
+}
+
+// Error creates an [*Error] with the given message, located at the value of
+// the Go field path. See [Provenance.WrapError].
+func (p *Provenance) Error(field, msg string, opts ...ErrorOption) *Error {
+	return p.WrapError(field, NewError(msg), opts...)
+}
+
+// WrapError creates an [*Error] wrapping err, located at the value of the Go
+// field path.
+//
+// If the field has no value in the document, e.g. because it was omitted, the
+// error is located at its closest parent with a value instead. The source of
+// the [DocumentDecoder] is attached, if any, and opts are applied last.
+func (p *Provenance) WrapError(field string, err error, opts ...ErrorOption) *Error {
+	var errOpts []ErrorOption
+
+	if p.source != nil {
+		errOpts = append(errOpts, WithSource(p.source))
+	}
+
+	for f := field; f != ""; f = parentField(f) {
+		if loc, ok := p.fields[f]; ok {
+			errOpts = append(errOpts, WithPath(loc.Path), WithErrorToken(loc.token))
+
+			break
+		}
+	}
+
+	var yamlErr *Error
+	if e, ok := err.(*Error); ok { //nolint:errorlint // Only reuse unwrapped errors.
+		yamlErr = e
+	} else {
+		yamlErr = NewErrorFrom(err)
+	}
+
+	yamlErr.SetOption(append(errOpts, opts...)...)
+
+	return yamlErr
+}
+
+// parentField returns the parent of a Go field path, or an empty string for
+// top-level fields.
+func parentField(field string) string {
+	i := strings.LastIndexAny(field, ".[")
+	if i < 0 {
+		return ""
+	}
+
+	return field[:i]
+}
+
+// Provenance returns the [*Provenance] of the document for values of the type
+// of v, which must be a non-nil pointer. The document is not decoded.
+func (dd *DocumentDecoder) Provenance(v any) *Provenance {
+	p := &Provenance{source: dd.source, fields: map[string]FieldLocation{}}
+
+	t := reflect.TypeOf(v)
+	if t == nil || dd.doc == nil || dd.doc.Body == nil {
+		return p
+	}
+
+	pb := provenanceBuilder{p: p}
+	pb.walk(dd.doc.Body, nil, t, "", nil)
+
+	return p
+}
+
+// UnmarshalWithProvenance unmarshals the document into v, as
+// [DocumentDecoder.Unmarshal], and returns the [*Provenance] of its fields.
+//
+// This is a convenience wrapper around
+// [DocumentDecoder.UnmarshalWithProvenanceContext] with
+// [context.Background].
+func (dd *DocumentDecoder) UnmarshalWithProvenance(v any) (*Provenance, error) {
+	return dd.UnmarshalWithProvenanceContext(context.Background(), v)
+}
+
+// UnmarshalWithProvenanceContext unmarshals the document into v, as
+// [DocumentDecoder.UnmarshalContext], and returns the [*Provenance] of its
+// fields.
+//
+// The provenance is returned even if unmarshaling fails.
+func (dd *DocumentDecoder) UnmarshalWithProvenanceContext(ctx context.Context, v any) (*Provenance, error) {
+	return dd.Provenance(v), dd.UnmarshalContext(ctx, v)
+}
+
+// pathSegment is a segment of a YAML path: a mapping key or sequence index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// provenanceBuilder walks a document alongside a Go type to build a
+// [Provenance].
+type provenanceBuilder struct {
+	p *Provenance
+}
+
+// walk records the location of node for the Go field path field, and walks
+// the children of node decoded into values of type t. The key of node is nil
+// for sequence entries and the document body.
+func (b *provenanceBuilder) walk(node, key ast.Node, t reflect.Type, field string, segs []pathSegment) {
+	if field != "" {
+		b.record(node, key, field, segs)
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if isUnmarshaler(t) {
+		return
+	}
+
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingValueNode:
+		b.walkMapping(ast.Mapping(n.GetToken(), false, n), t, field, segs)
+	case *ast.MappingNode:
+		b.walkMapping(n, t, field, segs)
+	case *ast.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for i, value := range n.Values {
+			b.walk(value, nil, t.Elem(), field+"["+strconv.Itoa(i)+"]",
+				append(slices.Clip(segs), pathSegment{index: i, isIndex: true}))
+		}
+	}
+}
+
+// walkMapping walks the entries of node decoded into a struct or map of type
+// t.
+func (b *provenanceBuilder) walkMapping(node *ast.MappingNode, t reflect.Type, field string, segs []pathSegment) {
+	var fields map[string]structField
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields = structFields(t)
+	case reflect.Map:
+	default:
+		return
+	}
+
+	for _, mv := range node.Values {
+		if mv.Key == nil || mv.Key.IsMergeKey() {
+			continue
+		}
+
+		key := mapKeyString(mv.Key)
+		childSegs := append(slices.Clip(segs), pathSegment{key: key})
+
+		if fields == nil {
+			b.walk(mv.Value, mv.Key, t.Elem(), field+"["+key+"]", childSegs)
+
+			continue
+		}
+
+		f, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		childField := f.goPath
+		if field != "" {
+			childField = field + "." + f.goPath
+		}
+
+		b.walk(mv.Value, mv.Key, f.typ, childField, childSegs)
+	}
+}
+
+// record records the location of node for the Go field path field.
+//
+// Mappings and sequences are located at their key, since their own tokens
+// are indicators like ":" and "-".
+func (b *provenanceBuilder) record(node, key ast.Node, field string, segs []pathSegment) {
+	tk := node.GetToken()
+	isCollection := false
+
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingNode, *ast.MappingValueNode, *ast.SequenceNode:
+		isCollection = true
+
+		if key != nil {
+			tk = key.GetToken()
+		} else if m, ok := n.(*ast.MappingNode); ok && len(m.Values) > 0 {
+			tk = m.Values[0].Key.GetToken()
+		} else if mv, ok := n.(*ast.MappingValueNode); ok {
+			tk = mv.Key.GetToken()
+		}
+	}
+
+	if tk == nil || tk.Position == nil {
+		return
+	}
+
+	builder := paths.Root()
+
+	for _, seg := range segs {
+		if seg.isIndex {
+			builder = builder.Index(seg.index)
+		} else {
+			builder = builder.Child(seg.key)
+		}
+	}
+
+	path := builder.Value()
+	if isCollection && key != nil {
+		path = builder.Key()
+	}
+
+	b.p.fields[field] = FieldLocation{
+		Path:  path,
+		Range: b.tokenRange(tk),
+		token: tk,
+	}
+}
+
+// tokenRange returns the range of tk, using the source if available.
+func (b *provenanceBuilder) tokenRange(tk *token.Token) position.Range {
+	start := position.NewFromToken(tk)
+
+	if b.p.source != nil {
+		if ranges := b.p.source.ContentPositionRanges(start); len(ranges) > 0 {
+			return position.NewRange(ranges[0].Start, ranges[len(ranges)-1].End)
+		}
+	}
+
+	first, _, _ := strings.Cut(strings.TrimSpace(tk.Origin), "\n")
+
+	return position.NewRange(start, position.New(start.Line, start.Col+utf8.RuneCountInString(first)))
+}
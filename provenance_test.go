@@ -0,0 +1,146 @@
+package niceyaml_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+func TestDocumentDecoder_Provenance(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		name: app
+		spec:
+		  replicas: 3
+		  labels:
+		    tier: 1
+		  containers:
+		    - name: web
+		      port: 80
+		  unknown: true
+	`))
+
+	dec, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range dec.Documents() {
+		var cfg collectConfig
+
+		prov, err := doc.UnmarshalWithProvenance(&cfg)
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.Spec.Replicas)
+
+		want := map[string]struct {
+			path string
+			rng  string
+		}{
+			"Name":                    {path: "$.name.(value)", rng: "1:7-1:10"},
+			"Spec":                    {path: "$.spec.(key)", rng: "2:1-2:5"},
+			"Spec.Replicas":           {path: "$.spec.replicas.(value)", rng: "3:13-3:14"},
+			"Spec.Meta.Labels":        {path: "$.spec.labels.(key)", rng: "4:3-4:9"},
+			"Spec.Meta.Labels[tier]":  {path: "$.spec.labels.tier.(value)", rng: "5:11-5:12"},
+			"Spec.Containers":         {path: "$.spec.containers.(key)", rng: "6:3-6:13"},
+			"Spec.Containers[0]":      {path: "$.spec.containers[0].(value)", rng: "7:7-7:11"},
+			"Spec.Containers[0].Name": {path: "$.spec.containers[0].name.(value)", rng: "7:13-7:16"},
+			"Spec.Containers[0].Port": {path: "$.spec.containers[0].port.(value)", rng: "8:13-8:15"},
+		}
+
+		assert.Equal(t, []string{
+			"Name",
+			"Spec",
+			"Spec.Replicas",
+			"Spec.Meta.Labels",
+			"Spec.Meta.Labels[tier]",
+			"Spec.Containers",
+			"Spec.Containers[0]",
+			"Spec.Containers[0].Name",
+			"Spec.Containers[0].Port",
+		}, prov.Fields())
+
+		for field, w := range want {
+			loc, ok := prov.Lookup(field)
+			require.True(t, ok, field)
+			assert.Equal(t, w.path, loc.Path.String(), field)
+			assert.Equal(t, w.rng, loc.Range.String(), field)
+		}
+
+		_, ok := prov.Lookup("Spec.Containers[0].Image")
+		assert.False(t, ok)
+	}
+}
+
+func TestProvenance_Error(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		name: app
+		spec:
+		  replicas: 30
+		  containers:
+		    - name: web
+	`), niceyaml.WithName("test.yaml"))
+
+	dec, err := source.Decoder()
+	require.NoError(t, err)
+
+	for _, doc := range dec.Documents() {
+		var cfg collectConfig
+
+		prov, err := doc.UnmarshalWithProvenance(&cfg)
+		require.NoError(t, err)
+
+		tcs := map[string]struct {
+			err  *niceyaml.Error
+			want string
+		}{
+			"field with value": {
+				err:  prov.Error("Spec.Replicas", "too many replicas"),
+				want: "test.yaml:3:13: error: too many replicas [$.spec.replicas]",
+			},
+			"missing field uses parent": {
+				err:  prov.Error("Spec.Containers[0].Image", "image is required"),
+				want: "test.yaml:5:7: error: image is required [$.spec.containers[0]]",
+			},
+			"unknown field": {
+				err:  prov.Error("Other", "other is required"),
+				want: "test.yaml: error: other is required",
+			},
+			"wrapped error with options": {
+				err: prov.WrapError("Name", errors.New("reserved name"),
+					niceyaml.WithSeverity(niceyaml.SeverityWarning),
+				),
+				want: "test.yaml:1:7: warning: reserved name [$.name]",
+			},
+		}
+
+		for name, tc := range tcs {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				tc.err.SetOption(niceyaml.WithErrorFormat(niceyaml.ErrorFormatCompact))
+				assert.Equal(t, tc.want, tc.err.Error())
+			})
+		}
+
+		t.Run("full format", func(t *testing.T) {
+			t.Parallel()
+
+			err := prov.Error("Spec.Replicas", "too many replicas",
+				niceyaml.WithPrinter(newPlainPrinter()),
+				niceyaml.WithSourceLines(0),
+			)
+
+			assert.Equal(t, stringtest.JoinLF(
+				"[3:13] too many replicas:",
+				"",
+				"   3    replicas: 30",
+			), trimLines(err.Error()))
+		})
+	}
+}
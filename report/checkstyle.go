@@ -0,0 +1,79 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// checkstyleVersion is the checkstyle format version written by
+// [EncodeCheckstyle].
+const checkstyleVersion = "4.3"
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr,omitempty"`
+}
+
+// EncodeCheckstyle writes the given [File]s to w as checkstyle XML.
+//
+// Every file is listed, including files without diagnostics. Diagnostics
+// without a range are reported at line 0. The code of a diagnostic is
+// reported as its source.
+func EncodeCheckstyle(w io.Writer, files ...File) error {
+	result := checkstyleResult{Version: checkstyleVersion}
+
+	for _, f := range files {
+		cf := checkstyleFile{Name: f.Path}
+
+		for _, d := range f.Diagnostics {
+			ce := checkstyleError{
+				Severity: checkstyleSeverity(d.Severity),
+				Message:  d.Message,
+				Source:   d.Code,
+			}
+			if d.Path != "" {
+				ce.Message += " (" + d.Path + ")"
+			}
+
+			if d.Range != nil {
+				ce.Line = d.Range.Start.Line
+				ce.Column = d.Range.Start.Column
+			}
+
+			cf.Errors = append(cf.Errors, ce)
+		}
+
+		result.Files = append(result.Files, cf)
+	}
+
+	return encodeXML(w, "checkstyle", result)
+}
+
+// checkstyleSeverity returns the checkstyle severity for the given severity
+// name.
+func checkstyleSeverity(severity string) string {
+	switch severity {
+	case niceyaml.SeverityWarning.String():
+		return "warning"
+	case niceyaml.SeverityInfo.String(), niceyaml.SeverityHint.String():
+		return "info"
+	default:
+		return "error"
+	}
+}
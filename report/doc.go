@@ -0,0 +1,24 @@
+// Package report encodes [niceyaml.Error] values in machine-readable formats,
+// for consumption by CI systems, code scanners, and editors.
+//
+// The pretty output of [niceyaml.Error.Error] is meant for people. For tools,
+// [Collect] flattens an error, including nested errors added with
+// [niceyaml.WithErrors], into [Diagnostic]s with file paths, line and column
+// ranges, messages, and YAML paths. [File] groups the diagnostics of a file:
+//
+//	files := []report.File{
+//		report.NewFile("config.yaml", err),
+//	}
+//	err := report.Encode(os.Stdout, report.FormatSARIF, files...)
+//
+// # Formats
+//
+// The following formats are supported:
+//
+//   - [FormatJSON]: a JSON array of [File]s.
+//   - [FormatSARIF]: SARIF 2.1.0, for code scanning integrations.
+//   - [FormatJUnit]: JUnit XML, with a test case per file.
+//   - [FormatCheckstyle]: checkstyle XML.
+//
+// Lines and columns are 1-indexed in all formats. Range ends are exclusive.
+package report
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// EncodeJSON writes the given [File]s to w as an indented JSON array.
+func EncodeJSON(w io.Writer, files ...File) error {
+	if files == nil {
+		files = []File{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(files)
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
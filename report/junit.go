@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+}
+
+type junitTestCase struct {
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// EncodeJUnit writes the given [File]s to w as JUnit XML.
+//
+// Each file is a test case, which fails if the file has diagnostics with the
+// "error" severity. The failure lists all errors of the file, one per line,
+// prefixed by their location. Other diagnostics are listed in the test case
+// output.
+func EncodeJUnit(w io.Writer, files ...File) error {
+	suite := junitTestSuite{Name: toolName, Tests: len(files)}
+
+	for _, f := range files {
+		tc := junitTestCase{Name: f.Path, ClassName: toolName}
+
+		var errs, others []string
+
+		for _, d := range f.Diagnostics {
+			if d.IsError() {
+				errs = append(errs, formatLine(f.Path, d))
+			} else {
+				others = append(others, formatLine(f.Path, d))
+			}
+		}
+
+		if len(errs) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d error(s)", len(errs)),
+				Type:    "error",
+				Text:    strings.Join(errs, "\n"),
+			}
+		}
+
+		tc.SystemOut = strings.Join(others, "\n")
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return encodeXML(w, "junit", junitTestSuites{
+		Name:     toolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	})
+}
+
+// formatLine formats d as "path:line:column: severity: message (yaml path)".
+func formatLine(path string, d Diagnostic) string {
+	var sb strings.Builder
+
+	sb.WriteString(path)
+
+	if loc := d.location(); loc != "" {
+		sb.WriteString(":" + loc)
+	}
+
+	sb.WriteString(": " + d.Severity)
+
+	if d.Code != "" {
+		sb.WriteString("[" + d.Code + "]")
+	}
+
+	sb.WriteString(": " + d.Message)
+
+	if d.Path != "" {
+		sb.WriteString(" (" + d.Path + ")")
+	}
+
+	return sb.String()
+}
+
+// encodeXML writes v to w as indented XML with a header.
+func encodeXML(w io.Writer, format string, v any) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", format, err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	err = enc.Encode(v)
+	if err == nil {
+		_, err = io.WriteString(w, "\n")
+	}
+
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", format, err)
+	}
+
+	return nil
+}
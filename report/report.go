@@ -0,0 +1,186 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/position"
+)
+
+// ErrUnknownFormat indicates that an output format is not supported.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Format is a machine-readable output format.
+type Format string
+
+// Supported formats.
+const (
+	FormatJSON       Format = "json"
+	FormatSARIF      Format = "sarif"
+	FormatJUnit      Format = "junit"
+	FormatCheckstyle Format = "checkstyle"
+)
+
+// Formats returns all supported [Format]s.
+func Formats() []Format {
+	return []Format{FormatJSON, FormatSARIF, FormatJUnit, FormatCheckstyle}
+}
+
+// ParseFormat returns the [Format] with the given name.
+// Returns [ErrUnknownFormat] if the format is not supported.
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(name))
+	if !slices.Contains(Formats(), f) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+	}
+
+	return f, nil
+}
+
+// Position is a 1-indexed line and column.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Range is a range of a file. The end is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// newRange converts a 0-indexed [position.Range] to a [Range].
+func newRange(r position.Range) *Range {
+	return &Range{
+		Start: Position{Line: r.Start.Line + 1, Column: r.Start.Col + 1},
+		End:   Position{Line: r.End.Line + 1, Column: r.End.Col + 1},
+	}
+}
+
+// Diagnostic is a single problem found in a file.
+type Diagnostic struct {
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Severity is the name of the [niceyaml.Severity], e.g. "error".
+	Severity string `json:"severity"`
+	// Path is the YAML path of the problem, e.g. "$.spec.replicas".
+	Path string `json:"path,omitempty"`
+	// Range is the location of the problem, or nil if unknown.
+	Range *Range `json:"range,omitempty"`
+	// Code identifies the kind of problem, e.g. "NY0012".
+	Code string `json:"code,omitempty"`
+	// Help explains how to resolve the problem.
+	Help string `json:"help,omitempty"`
+	// DocsURL links to documentation about the problem.
+	DocsURL string `json:"docsUrl,omitempty"`
+}
+
+// IsError reports whether the diagnostic has the "error" severity.
+func (d Diagnostic) IsError() bool {
+	return d.Severity == niceyaml.SeverityError.String()
+}
+
+// location returns the 1-indexed "line:column" of d, or an empty string if
+// d has no range.
+func (d Diagnostic) location() string {
+	if d.Range == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d:%d", d.Range.Start.Line, d.Range.Start.Column)
+}
+
+// File is the result of checking a single file.
+type File struct {
+	// Path is the path of the file.
+	Path string `json:"path"`
+	// Diagnostics are the problems found in the file. Empty if the file is
+	// valid.
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// NewFile creates a [File] with the given path and the [Diagnostic]s
+// collected from err, which may be nil.
+func NewFile(path string, err error) File {
+	diags := Collect(err)
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+
+	return File{Path: path, Diagnostics: diags}
+}
+
+// Errors returns the number of diagnostics with the "error" severity.
+func (f File) Errors() int {
+	n := 0
+
+	for _, d := range f.Diagnostics {
+		if d.IsError() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Collect flattens err into [Diagnostic]s.
+//
+// If err is or wraps a [*niceyaml.Error], it is flattened with
+// [niceyaml.Error.Flatten].
+//
+// Any other error becomes a single diagnostic without a range.
+// Returns nil if err is nil.
+func Collect(err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	var yamlErr *niceyaml.Error
+	if !errors.As(err, &yamlErr) {
+		return []Diagnostic{{
+			Message:  err.Error(),
+			Severity: niceyaml.SeverityError.String(),
+		}}
+	}
+
+	var diags []Diagnostic
+
+	for e, src := range yamlErr.Flatten() {
+		d := Diagnostic{
+			Message:  e.Message(),
+			Severity: e.Severity().String(),
+			Path:     e.Path(),
+			Code:     e.Code(),
+			Help:     e.Help(),
+			DocsURL:  e.DocsURL(),
+		}
+		if r, ok := e.ResolveRange(src); ok {
+			d.Range = newRange(r)
+		}
+
+		diags = append(diags, d)
+	}
+
+	return diags
+}
+
+// Encode writes the given [File]s to w in the given [Format].
+// Returns [ErrUnknownFormat] if the format is not supported.
+func Encode(w io.Writer, format Format, files ...File) error {
+	switch format {
+	case FormatJSON:
+		return EncodeJSON(w, files...)
+	case FormatSARIF:
+		return EncodeSARIF(w, files...)
+	case FormatJUnit:
+		return EncodeJUnit(w, files...)
+	case FormatCheckstyle:
+		return EncodeCheckstyle(w, files...)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,169 @@
+package report_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/paths"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/report"
+)
+
+func newFiles() []report.File {
+	source := niceyaml.NewSourceFromString(stringtest.Input(`
+		name: app
+		spec:
+		  replicas: three
+		  image: nginx
+	`), niceyaml.WithFilePath("deploy.yaml"))
+
+	err := source.WrapError(niceyaml.NewError("validation failed at 2 locations",
+		niceyaml.WithErrors(
+			niceyaml.NewError("expected integer",
+				niceyaml.WithPath(paths.Root().Child("spec", "replicas").Value()),
+				niceyaml.WithCode("NY0012"),
+				niceyaml.WithHelp("replicas must be a whole number"),
+				niceyaml.WithDocsURL("https://example.com/NY0012"),
+			),
+			niceyaml.NewError("image should be pinned",
+				niceyaml.WithPath(paths.Root().Child("spec", "image").Value()),
+				niceyaml.WithSeverity(niceyaml.SeverityWarning),
+			),
+		),
+	))
+
+	return []report.File{
+		report.NewFile("deploy.yaml", err),
+		report.NewFile("broken.yaml", errors.New("read file: permission denied")),
+		report.NewFile("valid.yaml", nil),
+	}
+}
+
+func TestEncode_Golden(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range report.Formats() {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := report.Encode(&buf, format, newFiles()...)
+			require.NoError(t, err)
+
+			golden.RequireEqual(t, buf.String())
+		})
+	}
+}
+
+func TestEncode_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := report.Encode(&buf, report.Format("yaml"))
+	require.ErrorIs(t, err, report.ErrUnknownFormat)
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	got, err := report.ParseFormat("SARIF")
+	require.NoError(t, err)
+	assert.Equal(t, report.FormatSARIF, got)
+
+	_, err = report.ParseFormat("text")
+	require.ErrorIs(t, err, report.ErrUnknownFormat)
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("key: value\nother: 1\n")
+
+	tcs := map[string]struct {
+		err  error
+		want []report.Diagnostic
+	}{
+		"nil": {},
+		"plain error": {
+			err: errors.New("boom"),
+			want: []report.Diagnostic{
+				{Message: "boom", Severity: "error"},
+			},
+		},
+		"error with path": {
+			err: source.WrapError(niceyaml.NewError("bad value",
+				niceyaml.WithPath(paths.Root().Child("key").Value()),
+			)),
+			want: []report.Diagnostic{{
+				Message:  "bad value",
+				Severity: "error",
+				Path:     "$.key",
+				Range: &report.Range{
+					Start: report.Position{Line: 1, Column: 6},
+					End:   report.Position{Line: 1, Column: 11},
+				},
+			}},
+		},
+		"error with primary label": {
+			err: source.WrapError(niceyaml.NewError("bad key",
+				niceyaml.WithLabels(niceyaml.NewPrimaryLabel(
+					position.NewRange(position.New(1, 0), position.New(1, 5)), "",
+				)),
+			)),
+			want: []report.Diagnostic{{
+				Message:  "bad key",
+				Severity: "error",
+				Range: &report.Range{
+					Start: report.Position{Line: 2, Column: 1},
+					End:   report.Position{Line: 2, Column: 6},
+				},
+			}},
+		},
+		"nested errors without source": {
+			err: niceyaml.NewError("failed", niceyaml.WithErrors(
+				niceyaml.NewError("first", niceyaml.WithPath(paths.Root().Child("a").Value())),
+				niceyaml.NewError("second", niceyaml.WithSeverity(niceyaml.SeverityHint)),
+			)),
+			want: []report.Diagnostic{
+				{Message: "first", Severity: "error", Path: "$.a"},
+				{Message: "second", Severity: "hint"},
+			},
+		},
+		"wrapped error": {
+			err: fmt.Errorf("document 0: %w", niceyaml.NewError("inner")),
+			want: []report.Diagnostic{
+				{Message: "inner", Severity: "error"},
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, report.Collect(tc.err))
+		})
+	}
+}
+
+func TestFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	files := newFiles()
+
+	assert.Equal(t, 1, files[0].Errors())
+	assert.Len(t, files[0].Diagnostics, 2)
+	assert.Equal(t, 1, files[1].Errors())
+	assert.Equal(t, 0, files[2].Errors())
+	assert.NotNil(t, files[2].Diagnostics)
+}
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// SARIF identifiers.
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "niceyaml"
+	toolURI      = "https://pkg.go.dev/go.jacobcolvin.com/niceyaml"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID      string        `json:"id"`
+	HelpURI string        `json:"helpUri,omitempty"`
+	Help    *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// EncodeSARIF writes the given [File]s to w as a SARIF 2.1.0 log with a
+// single run.
+//
+// Diagnostics with a code reference a rule with that ID, which links to the
+// documentation URL of the first diagnostic with the code. YAML paths are
+// reported as logical locations.
+func EncodeSARIF(w io.Writer, files ...File) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+
+	for _, f := range files {
+		for _, d := range f.Diagnostics {
+			if d.Code != "" && !rules[d.Code] {
+				rules[d.Code] = true
+
+				rule := sarifRule{ID: d.Code, HelpURI: d.DocsURL}
+				if d.Help != "" {
+					rule.Help = &sarifMessage{Text: d.Help}
+				}
+
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+			}
+
+			run.Results = append(run.Results, newSARIFResult(f.Path, d))
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(log)
+	if err != nil {
+		return fmt.Errorf("encode sarif: %w", err)
+	}
+
+	return nil
+}
+
+func newSARIFResult(path string, d Diagnostic) sarifResult {
+	loc := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(path)},
+		},
+	}
+
+	if d.Range != nil {
+		region := &sarifRegion{
+			StartLine:   d.Range.Start.Line,
+			StartColumn: d.Range.Start.Column,
+		}
+		if d.Range.End != d.Range.Start {
+			region.EndLine = d.Range.End.Line
+			region.EndColumn = d.Range.End.Column
+		}
+
+		loc.PhysicalLocation.Region = region
+	}
+
+	if d.Path != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: d.Path}}
+	}
+
+	return sarifResult{
+		RuleID:    d.Code,
+		Level:     sarifLevel(d.Severity),
+		Message:   sarifMessage{Text: d.Message},
+		Locations: []sarifLocation{loc},
+	}
+}
+
+// sarifLevel returns the SARIF level for the given severity name.
+func sarifLevel(severity string) string {
+	switch severity {
+	case niceyaml.SeverityWarning.String():
+		return "warning"
+	case niceyaml.SeverityInfo.String(), niceyaml.SeverityHint.String():
+		return "note"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml/ast"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+// ErrInvalidSchema indicates that schema data is not a JSON schema object.
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// maxSchemaDepth limits the $ref and subschema chains followed by
+// [PropertyOrder], so that recursive schemas terminate.
+const maxSchemaDepth = 32
+
+// PropertyOrder returns a [niceyaml.KeyOrder] that sorts keys in the order
+// in which the JSON schema data defines them as properties, for use with
+// [niceyaml.Source.SortKeys]. Keys that the schema does not define follow the
+// others, in their original order.
+//
+// The schema of each mapping is found by following "properties",
+// "patternProperties", "additionalProperties", and "items" from the root, and
+// local "$ref"s, e.g. "#/$defs/container". The properties of "allOf",
+// "anyOf", and "oneOf" subschemas are included, in order.
+//
+// data may be JSON or YAML. Returns [ErrInvalidSchema] if it is not an
+// object.
+func PropertyOrder(data []byte) (niceyaml.KeyOrder, error) {
+	file, err := niceyaml.NewSourceFromBytes(data).File()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+
+	if len(file.Docs) == 0 {
+		return nil, fmt.Errorf("%w: empty schema", ErrInvalidSchema)
+	}
+
+	root, ok := file.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return nil, fmt.Errorf("%w: schema is not an object", ErrInvalidSchema)
+	}
+
+	o := &propertyOrder{root: root, cache: map[string][]string{}}
+
+	return o.compare, nil
+}
+
+// propertyOrder is the [niceyaml.KeyOrder] returned by [PropertyOrder].
+type propertyOrder struct {
+	root  *ast.MappingNode
+	cache map[string][]string // Property names by path.
+	mu    sync.Mutex
+}
+
+// compare implements [niceyaml.KeyOrder].
+func (o *propertyOrder) compare(path []string, a, b string) int {
+	return niceyaml.CompareListedKeys(o.properties(path), a, b)
+}
+
+// properties returns the names of the properties of the schemas at path.
+func (o *propertyOrder) properties(path []string) []string {
+	id := strings.Join(path, "\x00")
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if names, ok := o.cache[id]; ok {
+		return names
+	}
+
+	schemas := o.expand(o.root, 0)
+	for _, key := range path {
+		var next []*ast.MappingNode
+		for _, s := range schemas {
+			for _, child := range o.children(s, key) {
+				next = append(next, o.expand(child, 0)...)
+			}
+		}
+
+		schemas = next
+	}
+
+	var names []string
+
+	for _, s := range schemas {
+		props, ok := lookup(s, "properties").(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+
+		for _, mv := range props.Values {
+			if name := nodeString(mv.Key); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	o.cache[id] = names
+
+	return names
+}
+
+// children returns the schemas of the value of key in instances of s, or of
+// the items of its arrays for [niceyaml.SequenceItemKey].
+func (o *propertyOrder) children(s *ast.MappingNode, key string) []ast.Node {
+	if key == niceyaml.SequenceItemKey {
+		if items := lookup(s, "items"); items != nil {
+			return []ast.Node{items}
+		}
+
+		return nil
+	}
+
+	if props, ok := lookup(s, "properties").(*ast.MappingNode); ok {
+		if prop := lookup(props, key); prop != nil {
+			return []ast.Node{prop}
+		}
+	}
+
+	var children []ast.Node
+
+	if patterns, ok := lookup(s, "patternProperties").(*ast.MappingNode); ok {
+		for _, mv := range patterns.Values {
+			re, err := regexp.Compile(nodeString(mv.Key))
+			if err == nil && re.MatchString(key) {
+				children = append(children, mv.Value)
+			}
+		}
+	}
+
+	if len(children) == 0 {
+		if additional := lookup(s, "additionalProperties"); additional != nil {
+			children = append(children, additional)
+		}
+	}
+
+	return children
+}
+
+// expand returns the schema node, with its "$ref" resolved, followed by its
+// "allOf", "anyOf", and "oneOf" subschemas. Nodes that are not objects, such
+// as boolean schemas, are skipped.
+func (o *propertyOrder) expand(node ast.Node, depth int) []*ast.MappingNode {
+	s, ok := node.(*ast.MappingNode)
+	if !ok || depth > maxSchemaDepth {
+		return nil
+	}
+
+	schemas := []*ast.MappingNode{s}
+
+	if ref := nodeString(lookup(s, "$ref")); strings.HasPrefix(ref, "#") {
+		schemas = append(schemas, o.expand(o.resolve(ref), depth+1)...)
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subs, ok := lookup(s, keyword).(*ast.SequenceNode)
+		if !ok {
+			continue
+		}
+
+		for _, sub := range subs.Values {
+			schemas = append(schemas, o.expand(sub, depth+1)...)
+		}
+	}
+
+	return schemas
+}
+
+// resolve returns the node at the local JSON pointer ref, e.g.
+// "#/$defs/container", or nil if there is none.
+func (o *propertyOrder) resolve(ref string) ast.Node {
+	var node ast.Node = o.root
+
+	pointer := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	if pointer == "" {
+		return node
+	}
+
+	for token := range strings.SplitSeq(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		m, ok := node.(*ast.MappingNode)
+		if !ok {
+			return nil
+		}
+
+		node = lookup(m, token)
+	}
+
+	return node
+}
+
+// lookup returns the value of key in m, or nil if there is none.
+func lookup(m *ast.MappingNode, key string) ast.Node {
+	for _, mv := range m.Values {
+		if nodeString(mv.Key) == key {
+			return mv.Value
+		}
+	}
+
+	return nil
+}
+
+// nodeString returns the value of a string node, or the text of other nodes.
+func nodeString(node ast.Node) string {
+	switch n := node.(type) {
+	case nil:
+		return ""
+	case *ast.StringNode:
+		return n.Value
+	default:
+		return n.GetToken().Value
+	}
+}
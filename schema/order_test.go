@@ -0,0 +1,81 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/schema"
+)
+
+func TestPropertyOrder(t *testing.T) {
+	t.Parallel()
+
+	order, err := schema.PropertyOrder([]byte(stringtest.JoinLF(
+		`{`,
+		`  "properties": {`,
+		`    "name": {"type": "string"},`,
+		`    "containers": {"type": "array", "items": {"$ref": "#/$defs/container"}},`,
+		`    "labels": {"additionalProperties": {"properties": {"z": {}, "y": {}}}}`,
+		`  },`,
+		`  "allOf": [{"properties": {"extra": {}}}],`,
+		`  "$defs": {`,
+		`    "container": {"properties": {"image": {}, "args": {}}}`,
+		`  }`,
+		`}`,
+	)))
+	require.NoError(t, err)
+
+	input := stringtest.Input(`
+		other: 1
+		extra: 2
+		labels:
+		  app:
+		    y: 1
+		    z: 2
+		containers:
+		  - args: []
+		    image: app
+		name: app
+	`)
+	want := stringtest.Input(`
+		name: app
+		containers:
+		  - image: app
+		    args: []
+		labels:
+		  app:
+		    z: 2
+		    y: 1
+		extra: 2
+		other: 1
+	`)
+
+	got, err := niceyaml.NewSourceFromString(input).SortKeys(order)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSuffix(want, "\n"), got.Content())
+}
+
+func TestPropertyOrder_YAML(t *testing.T) {
+	t.Parallel()
+
+	order, err := schema.PropertyOrder([]byte("properties:\n  b: {}\n  a: {}\n"))
+	require.NoError(t, err)
+
+	assert.Negative(t, order(nil, "b", "a"))
+	assert.Positive(t, order(nil, "a", "b"))
+	assert.Zero(t, order([]string{"a"}, "b", "a"))
+}
+
+func TestPropertyOrder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{"", "[1, 2]", "true", "{"} {
+		_, err := schema.PropertyOrder([]byte(data))
+		require.ErrorIs(t, err, schema.ErrInvalidSchema, "data: %q", data)
+	}
+}
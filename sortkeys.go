@@ -0,0 +1,435 @@
+package niceyaml
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+// ErrSortKeys indicates that the keys of a [Source] cannot be sorted without
+// changing its content.
+var ErrSortKeys = errors.New("cannot sort keys")
+
+// SequenceItemKey is the element of the paths passed to a [KeyOrder] for the
+// items of a sequence.
+const SequenceItemKey = "[]"
+
+// mergeKey is the merge key of YAML mappings.
+const mergeKey = "<<"
+
+// KeyOrder compares the keys a and b of a mapping, like [strings.Compare]:
+// it returns a negative number if a sorts before b, a positive number if a
+// sorts after b, and zero to keep their order.
+//
+// path holds the keys leading from the document root to the mapping, with
+// [SequenceItemKey] for sequence items, e.g. ["spec", "containers", "[]"]
+// for the containers of a Kubernetes deployment.
+//
+// See [AlphabeticalKeyOrder], [KubernetesKeyOrder], and
+// [go.jacobcolvin.com/niceyaml/schema.PropertyOrder] for implementations.
+type KeyOrder func(path []string, a, b string) int
+
+// AlphabeticalKeyOrder returns a [KeyOrder] that sorts keys alphabetically,
+// by byte value.
+func AlphabeticalKeyOrder() KeyOrder {
+	return func(_ []string, a, b string) int {
+		return strings.Compare(a, b)
+	}
+}
+
+// kubernetesKeys lists the conventional order of the keys of Kubernetes
+// objects, by the key of the mapping they are in. The empty key is the
+// document root.
+var kubernetesKeys = map[string][]string{
+	"": {
+		"apiVersion", "kind", "metadata", "spec", "type", "immutable", "data", "binaryData", "stringData",
+		"rules", "subjects", "roleRef", "webhooks", "status",
+	},
+	"metadata": {
+		"name", "generateName", "namespace", "labels", "annotations", "ownerReferences", "finalizers",
+	},
+	"containers": {
+		"name", "image", "imagePullPolicy", "command", "args", "workingDir", "ports", "envFrom", "env",
+		"resources", "volumeMounts", "livenessProbe", "readinessProbe", "startupProbe", "lifecycle",
+		"securityContext",
+	},
+	"ports": {"name", "containerPort", "port", "targetPort", "nodePort", "hostPort", "protocol"},
+	"env":   {"name", "value", "valueFrom"},
+}
+
+// KubernetesKeyOrder returns a [KeyOrder] that sorts the keys of Kubernetes
+// objects in their conventional order: "apiVersion", "kind", "metadata",
+// "spec", and so on at the root, "name", "namespace", "labels", and
+// "annotations" in metadata, and "name" and "image" first in containers.
+//
+// Keys without a conventional position follow the others, in their original
+// order.
+func KubernetesKeyOrder() KeyOrder {
+	return func(path []string, a, b string) int {
+		parent := ""
+
+		for _, key := range slices.Backward(path) {
+			if key != SequenceItemKey {
+				parent = key
+
+				break
+			}
+		}
+
+		switch parent {
+		case "initContainers", "ephemeralContainers":
+			parent = "containers"
+		case "":
+			if len(path) > 0 {
+				return 0
+			}
+		}
+
+		return CompareListedKeys(kubernetesKeys[parent], a, b)
+	}
+}
+
+// CompareListedKeys compares the keys a and b by their position in keys, for
+// building a [KeyOrder]. Listed keys sort before unlisted keys, which keep
+// their order.
+func CompareListedKeys(keys []string, a, b string) int {
+	i, j := slices.Index(keys, a), slices.Index(keys, b)
+
+	switch {
+	case i < 0 && j < 0:
+		return 0
+	case i < 0:
+		return 1
+	case j < 0:
+		return -1
+	default:
+		return cmp.Compare(i, j)
+	}
+}
+
+// SortKeys returns a derived [*Source] with the keys of each block mapping of
+// s sorted by order. Merge keys ("<<") sort first, and the sort is stable, so
+// keys that order considers equal keep their order.
+//
+// Each key moves with its value, and with the comment lines directly above
+// it. Blank lines and other comments between keys stay in place, and flow
+// mappings are left as is.
+//
+// The result has the same name, file path, and options as s. Returns an error
+// if s cannot be parsed, or [ErrSortKeys] if sorting would change the content
+// of s, e.g. for content that the lexer cannot reproduce, or for an alias
+// that would precede its anchor.
+func (s *Source) SortKeys(order KeyOrder) (*Source, error) {
+	file, err := s.File()
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &keySorter{lines: strings.Split(s.Content(), "\n"), order: order}
+	for _, doc := range file.Docs {
+		ks.collect(doc.Body, nil)
+	}
+
+	// Sort nested mappings before the mappings they are in, since sorting
+	// only moves lines within a mapping.
+	slices.SortStableFunc(ks.mappings, func(a, b sortableMapping) int {
+		return cmp.Compare(b.keyLines[0], a.keyLines[0])
+	})
+
+	for _, m := range ks.mappings {
+		ks.sort(m)
+	}
+
+	sorted := NewSourceFromString(strings.Join(ks.lines, "\n") + "\n")
+	s.copyOptions(sorted)
+
+	sortedFile, err := sorted.File()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSortKeys, err)
+	}
+
+	if !slices.Equal(contentSignature(file), contentSignature(sortedFile)) {
+		return nil, s.WrapError(NewErrorFrom(fmt.Errorf("%w: the sorted content differs", ErrSortKeys)))
+	}
+
+	if !slices.Equal(aliasTargets(file), aliasTargets(sortedFile)) {
+		return nil, s.WrapError(NewErrorFrom(fmt.Errorf("%w: an alias would precede its anchor", ErrSortKeys)))
+	}
+
+	return sorted, nil
+}
+
+// keySorter sorts the keys of the mappings of a [Source], see
+// [Source.SortKeys].
+type keySorter struct {
+	order    KeyOrder
+	lines    []string
+	mappings []sortableMapping
+}
+
+// sortableMapping is a block mapping with at least two keys.
+type sortableMapping struct {
+	path     []string
+	keys     []string
+	keyLines []int // Zero-based.
+}
+
+// collect collects the sortable mappings in node, at path.
+func (ks *keySorter) collect(node ast.Node, path []string) {
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingNode:
+		m := sortableMapping{path: path}
+		sortable := !n.IsFlowStyle
+
+		for _, mv := range n.Values {
+			if mv.Key == nil {
+				sortable = false
+
+				continue
+			}
+
+			if _, ok := mv.Key.(*ast.MappingKeyNode); ok {
+				// Explicit keys ("? ") may span lines.
+				sortable = false
+			}
+
+			key := keyID(mv.Key)
+			if mv.Key.IsMergeKey() {
+				key = mergeKey
+			}
+
+			m.keys = append(m.keys, key)
+			m.keyLines = append(m.keyLines, mv.Key.GetToken().Position.Line-1)
+			ks.collect(mv.Value, append(slices.Clip(path), key))
+		}
+
+		if sortable && len(m.keys) > 1 {
+			ks.mappings = append(ks.mappings, m)
+		}
+	case *ast.MappingValueNode:
+		if n.Key != nil {
+			ks.collect(n.Value, append(slices.Clip(path), keyID(n.Key)))
+		}
+	case *ast.SequenceNode:
+		for _, v := range n.Values {
+			ks.collect(v, append(slices.Clip(path), SequenceItemKey))
+		}
+	}
+}
+
+// keyEntry is the lines of a mapping entry: its key, value, and the comments
+// directly above it.
+type keyEntry struct {
+	key   string
+	start int // First line, zero-based.
+	line  int // Key line.
+	end   int // Last line.
+}
+
+// sort sorts the keys of m, reordering the lines of their entries.
+func (ks *keySorter) sort(m sortableMapping) {
+	col := indentation(ks.lines[m.keyLines[1]])
+
+	entries := make([]keyEntry, len(m.keys))
+	for i, line := range m.keyLines {
+		if i > 0 && (line <= m.keyLines[i-1] || indentation(ks.lines[line]) != col) {
+			return
+		}
+
+		entries[i] = keyEntry{key: m.keys[i], start: line, line: line}
+	}
+
+	// The first key may follow a prefix, such as "- ", which stays in front
+	// of the first key.
+	var prefix []rune
+
+	inline := indentation(ks.lines[m.keyLines[0]]) != col
+	if inline {
+		prefix = []rune(ks.lines[m.keyLines[0]])[:col]
+	}
+
+	for i := range entries {
+		if i > 0 || !inline {
+			for entries[i].start > 0 && isCommentLine(ks.lines[entries[i].start-1], col) {
+				entries[i].start--
+			}
+		}
+
+		if i > 0 {
+			entries[i-1].end = ks.lastLine(entries[i-1].line, entries[i].start-1, col)
+		}
+	}
+
+	last := &entries[len(entries)-1]
+	last.end = ks.lastLine(last.line, ks.valueEnd(last.line, col), col)
+
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b keyEntry) int {
+		aMerge, bMerge := a.key == mergeKey, b.key == mergeKey
+
+		switch {
+		case aMerge && bMerge:
+			return 0
+		case aMerge:
+			return -1
+		case bMerge:
+			return 1
+		default:
+			return ks.order(m.path, a.key, b.key)
+		}
+	})
+
+	if slices.Equal(sorted, entries) {
+		return
+	}
+
+	var lines []string
+
+	for i, e := range sorted {
+		chunk := slices.Clone(ks.lines[e.start : e.end+1])
+
+		if inline {
+			keyLine := []rune(chunk[e.line-e.start])
+			if i == 0 {
+				keyLine = append(slices.Clone(prefix), keyLine[col:]...)
+			} else {
+				keyLine = append([]rune(strings.Repeat(" ", col)), keyLine[col:]...)
+			}
+
+			chunk[e.line-e.start] = string(keyLine)
+		}
+
+		lines = append(lines, chunk...)
+
+		// Lines between entries stay in place.
+		if i+1 < len(entries) {
+			lines = append(lines, ks.lines[entries[i].end+1:entries[i+1].start]...)
+		}
+	}
+
+	copy(ks.lines[entries[0].start:], lines)
+}
+
+// valueEnd returns the last line that may belong to the value of the last
+// key of a mapping, at the key line, with its keys at column col.
+func (ks *keySorter) valueEnd(line, col int) int {
+	end := line
+
+	for i := line + 1; i < len(ks.lines); i++ {
+		text := ks.lines[i]
+		trimmed := strings.TrimSpace(text)
+		indent := indentation(text)
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(text, "---") || strings.HasPrefix(text, "..."):
+			return end
+		case indent > col:
+		case indent == col && (trimmed == "-" || strings.HasPrefix(trimmed, "- ")):
+			// A sequence aligned with its key.
+		default:
+			return end
+		}
+
+		end = i
+	}
+
+	return end
+}
+
+// lastLine returns the last line from start to end that is neither blank nor
+// a comment at column col or less.
+func (ks *keySorter) lastLine(start, end, col int) int {
+	for end > start {
+		trimmed := strings.TrimSpace(ks.lines[end])
+		if trimmed != "" && (!strings.HasPrefix(trimmed, "#") || indentation(ks.lines[end]) > col) {
+			break
+		}
+
+		end--
+	}
+
+	return end
+}
+
+// isCommentLine reports whether text is a comment at column col.
+func isCommentLine(text string, col int) bool {
+	return indentation(text) == col && strings.HasPrefix(strings.TrimSpace(text), "#")
+}
+
+// indentation returns the number of leading spaces of text.
+func indentation(text string) int {
+	return len(text) - len(strings.TrimLeft(text, " "))
+}
+
+// contentSignature returns the paths, types, and scalar values of the nodes of
+// file, sorted, to compare the content of files regardless of key order.
+func contentSignature(file *ast.File) []string {
+	var sig []string
+
+	for _, doc := range file.Docs {
+		ast.Walk(visitFunc(func(node ast.Node) {
+			if node.Type() == ast.CommentType {
+				return
+			}
+
+			entry := node.GetPath() + " " + node.Type().String()
+			if scalar, ok := node.(ast.ScalarNode); ok {
+				entry += fmt.Sprintf(" %v", scalar.GetValue())
+			}
+
+			sig = append(sig, entry)
+		}), doc)
+	}
+
+	slices.Sort(sig)
+
+	return sig
+}
+
+// aliasTargets returns the path of each alias in file with the path of the
+// anchor it refers to, which is the last anchor with its name before it, or
+// none if there is no such anchor.
+func aliasTargets(file *ast.File) []string {
+	var targets []string
+
+	for _, doc := range file.Docs {
+		anchors := map[string]string{}
+
+		ast.Walk(visitFunc(func(node ast.Node) {
+			switch n := node.(type) {
+			case *ast.AnchorNode:
+				if n.Name != nil {
+					anchors[n.Name.GetToken().Value] = n.GetPath()
+				}
+			case *ast.AliasNode:
+				if n.Value != nil {
+					targets = append(targets, n.GetPath()+" -> "+anchors[n.Value.GetToken().Value])
+				}
+			}
+		}), doc)
+	}
+
+	slices.Sort(targets)
+
+	return targets
+}
+
+// visitFunc is an [ast.Visitor] calling a function for each node.
+type visitFunc func(node ast.Node)
+
+// Visit implements [ast.Visitor].
+func (f visitFunc) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+
+	f(node)
+
+	return f
+}
@@ -0,0 +1,196 @@
+package niceyaml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+)
+
+func TestSource_SortKeys(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		order niceyaml.KeyOrder
+		input string
+		want  string
+	}{
+		"alphabetical": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: stringtest.Input(`
+				c: 3
+				# about a
+				a:
+				  z: 1
+				  y: 2 # y
+				b: [x, y]
+			`),
+			want: stringtest.Input(`
+				# about a
+				a:
+				  y: 2 # y
+				  z: 1
+				b: [x, y]
+				c: 3
+			`),
+		},
+		"blank lines stay in place": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: "b: 2\n\na: 1\n",
+			want:  "a: 1\n\nb: 2\n",
+		},
+		"sequence items": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: stringtest.Input(`
+				items:
+				  - b: 2
+				    a: 1
+				  - d: |
+				      text
+				    c: 3
+			`),
+			want: stringtest.Input(`
+				items:
+				  - a: 1
+				    b: 2
+				  - c: 3
+				    d: |
+				      text
+			`),
+		},
+		"merge keys first": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: stringtest.Input(`
+				base: &base
+				  x: 1
+				other:
+				  b: 2
+				  <<: *base
+				  a: 1
+			`),
+			want: stringtest.Input(`
+				base: &base
+				  x: 1
+				other:
+				  <<: *base
+				  a: 1
+				  b: 2
+			`),
+		},
+		"kubernetes": {
+			order: niceyaml.KubernetesKeyOrder(),
+			input: stringtest.Input(`
+				spec:
+				  containers:
+				    - image: app
+				      name: app
+				metadata:
+				  labels: {}
+				  name: app
+				kind: Pod
+				apiVersion: v1
+			`),
+			want: stringtest.Input(`
+				apiVersion: v1
+				kind: Pod
+				metadata:
+				  name: app
+				  labels: {}
+				spec:
+				  containers:
+				    - name: app
+				      image: app
+			`),
+		},
+		"flow mappings": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: "a: {c: 1, b: 2}\n",
+			want:  "a: {c: 1, b: 2}\n",
+		},
+		"aliases after anchors": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: "a: &x 1\nc: 2\nb: *x\n",
+			want:  "a: &x 1\nb: *x\nc: 2\n",
+		},
+		"documents": {
+			order: niceyaml.AlphabeticalKeyOrder(),
+			input: "b: 1\na: 2\n---\nd: 3\nc: 4\n",
+			want:  "a: 2\nb: 1\n---\nc: 4\nd: 3\n",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(tc.input, niceyaml.WithName("test.yaml"))
+
+			got, err := source.SortKeys(tc.order)
+			require.NoError(t, err)
+			assert.Equal(t, strings.TrimSuffix(tc.want, "\n"), got.Content())
+			assert.Equal(t, "test.yaml", got.Name())
+		})
+	}
+}
+
+func TestSource_SortKeys_Path(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+
+	source := niceyaml.NewSourceFromString("a:\n  - c: 1\n    b: 2\n")
+
+	_, err := source.SortKeys(func(path []string, a, b string) int {
+		paths = append(paths, strings.Join(path, "."))
+
+		return strings.Compare(a, b)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, paths, "a.[]")
+}
+
+func TestSource_SortKeys_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := niceyaml.NewSourceFromString("a: [1\n").SortKeys(niceyaml.AlphabeticalKeyOrder())
+	require.Error(t, err)
+
+	// The lexer does not keep the original text of escapes.
+	_, err = niceyaml.NewSourceFromString("b: \"\\u0041\"\na: 1\n").SortKeys(niceyaml.AlphabeticalKeyOrder())
+	require.ErrorIs(t, err, niceyaml.ErrSortKeys)
+}
+
+func TestSource_SortKeys_AliasBeforeAnchor(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]string{
+		"alias":            "b: &x 1\na: *x\n",
+		"nested alias":     "b:\n  c: &x 1\na:\n  d: *x\n",
+		"redefined anchor": "a: &x 1\nc: &x 2\nb: *x\n",
+	}
+
+	for name, input := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := niceyaml.NewSourceFromString(input).SortKeys(niceyaml.AlphabeticalKeyOrder())
+			require.ErrorIs(t, err, niceyaml.ErrSortKeys)
+		})
+	}
+}
+
+func TestCompareListedKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"a", "b"}
+
+	assert.Negative(t, niceyaml.CompareListedKeys(keys, "a", "b"))
+	assert.Positive(t, niceyaml.CompareListedKeys(keys, "b", "a"))
+	assert.Negative(t, niceyaml.CompareListedKeys(keys, "b", "z"))
+	assert.Positive(t, niceyaml.CompareListedKeys(keys, "z", "a"))
+	assert.Zero(t, niceyaml.CompareListedKeys(keys, "y", "z"))
+}
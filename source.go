@@ -3,6 +3,7 @@ package niceyaml
 import (
 	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"os"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/goccy/go-yaml/token"
 
 	"go.jacobcolvin.com/niceyaml/line"
+	"go.jacobcolvin.com/niceyaml/outline"
 	"go.jacobcolvin.com/niceyaml/position"
 	"go.jacobcolvin.com/niceyaml/style"
 )
@@ -62,16 +64,27 @@ type LineIterator interface {
 // Create instances with [NewSourceFromFile], [NewSourceFromString],
 // [NewSourceFromToken], or [NewSourceFromTokens].
 type Source struct {
-	name       string
-	filePath   string
-	lines      line.Lines
-	file       *ast.File
-	fileErr    error
-	parserOpts []parser.Option
-	decodeOpts []yaml.DecodeOption
-	errorOpts  []ErrorOption
-	fileOnce   sync.Once
-	overlayMu  sync.RWMutex
+	name        string
+	filePath    string
+	lines       line.Lines
+	file        *ast.File
+	fileErr     error
+	outline     *outline.Outline
+	parserOpts  []parser.Option
+	decodeOpts  []yaml.DecodeOption
+	errorOpts   []ErrorOption
+	fileOnce    sync.Once
+	outlineOnce sync.Once
+	overlayMu   sync.RWMutex
+	from        *Source
+	origins     []lineOrigin
+	decodeMode  DecodeMode
+	partialFile *ast.File
+	partialErr  error
+	partialOnce sync.Once
+
+	duplicateKeyOpts   []DuplicateKeyOption
+	checkDuplicateKeys bool
 }
 
 // SourceOption configures [Source] creation.
@@ -82,6 +95,8 @@ type Source struct {
 //   - [WithParserOptions]
 //   - [WithDecodeOptions]
 //   - [WithErrorOptions]
+//   - [WithDecodeMode]
+//   - [WithDuplicateKeyCheck]
 type SourceOption func(*Source)
 
 // WithName is a [SourceOption] that sets the name for the [Source].
@@ -158,6 +173,27 @@ func NewSourceFromFile(path string, opts ...SourceOption) (*Source, error) {
 	return NewSourceFromString(string(data), opts...), nil
 }
 
+// NewSourceFromReader creates a new [*Source] by reading r until EOF, e.g.
+// for YAML piped to stdin.
+//
+// Use [WithName] and [WithFilePath] to set a display name, and a path for
+// schema routing, since r has neither:
+//
+//	source, err := niceyaml.NewSourceFromReader(os.Stdin,
+//		niceyaml.WithName("deployment.yaml"),
+//		niceyaml.WithFilePath("deployment.yaml"),
+//	)
+//
+// Returns an error if r cannot be read.
+func NewSourceFromReader(r io.Reader, opts ...SourceOption) (*Source, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	return NewSourceFromBytes(data, opts...), nil
+}
+
 // NewSourceFromBytes creates a new [*Source] from raw YAML bytes.
 func NewSourceFromBytes(data []byte, opts ...SourceOption) *Source {
 	return NewSourceFromString(string(data), opts...)
@@ -209,6 +245,33 @@ func NewSourceFromTokens(tks token.Tokens, opts ...SourceOption) *Source {
 	return t
 }
 
+// Clone returns a new [*Source] with the tokens, name, file path, and options
+// of s, and copies of its line annotations, e.g. to annotate it without
+// modifying s. Overlays are not copied.
+func (s *Source) Clone() *Source {
+	c := NewSourceFromTokens(s.Tokens())
+	s.copyOptions(c)
+
+	for i, ln := range s.lines {
+		c.lines[i].AddAnnotation(ln.Annotations...)
+	}
+
+	return c
+}
+
+// copyOptions sets the name, file path, and options of dst to those of s, as
+// if dst was created with the same [SourceOption]s.
+func (s *Source) copyOptions(dst *Source) {
+	dst.name = s.name
+	dst.filePath = s.filePath
+	dst.parserOpts = s.parserOpts
+	dst.decodeOpts = s.decodeOpts
+	dst.errorOpts = s.errorOpts
+	dst.decodeMode = s.decodeMode
+	dst.duplicateKeyOpts = s.duplicateKeyOpts
+	dst.checkDuplicateKeys = s.checkDuplicateKeys
+}
+
 // Name returns the name of the [Source].
 func (s *Source) Name() string {
 	return s.name
@@ -237,7 +300,8 @@ func (s *Source) Decoder() (*Decoder, error) {
 // File returns an [*ast.File] for the [Source] tokens.
 //
 // The file is lazily parsed on first call using [parser.Parse] with options
-// provided via [WithParserOptions]. Subsequent calls return the cached result.
+// provided via [WithParserOptions], and [parser.AllowDuplicateMapKey] with
+// [WithDuplicateKeyCheck]. Subsequent calls return the cached result.
 //
 // Any YAML parsing errors are converted to [Error] with source annotations.
 func (s *Source) File() (*ast.File, error) {
@@ -248,8 +312,34 @@ func (s *Source) File() (*ast.File, error) {
 	return s.file, s.fileErr
 }
 
-func (s *Source) parse() (*ast.File, error) {
-	file, err := parser.Parse(s.Tokens(), parser.ParseComments, s.parserOpts...)
+// Outline returns an [*outline.Outline] describing the mapping keys and
+// sequence items of each document in the [Source], with their line spans,
+// paths, and value kinds.
+//
+// The outline is built from [Source.File], so any parsing error is returned
+// as-is. The result is cached, so [*outline.Node] pointers are stable across
+// calls and may be used as keys (see [Folds]).
+func (s *Source) Outline() (*outline.Outline, error) {
+	file, err := s.File()
+	if err != nil {
+		return nil, err
+	}
+
+	s.outlineOnce.Do(func() {
+		s.outline = outline.New(file, s.lines)
+	})
+
+	return s.outline, nil
+}
+
+// parse parses the tokens of s with its parser options, followed by opts.
+func (s *Source) parse(opts ...parser.Option) (*ast.File, error) {
+	parserOpts := append([]parser.Option{}, s.parserOpts...)
+	if s.checkDuplicateKeys {
+		parserOpts = append(parserOpts, parser.AllowDuplicateMapKey())
+	}
+
+	file, err := parser.Parse(s.Tokens(), parser.ParseComments, append(parserOpts, opts...)...)
 	if err == nil {
 		return file, nil
 	}
@@ -490,6 +580,16 @@ func (s *Source) ClearOverlays() {
 	s.lines.ClearOverlays()
 }
 
+// RemoveOverlays removes the overlays of the given kinds from all lines,
+// keeping overlays of other kinds, such as those added by
+// [Source.ParseTolerant].
+func (s *Source) RemoveOverlays(kinds ...style.Style) {
+	s.overlayMu.Lock()
+	defer s.overlayMu.Unlock()
+
+	s.lines.RemoveOverlays(kinds...)
+}
+
 // Width returns the maximum line width across all lines.
 func (s *Source) Width() int {
 	var maxWidth int
@@ -2,7 +2,9 @@ package niceyaml_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"unicode/utf8"
 
 	"github.com/goccy/go-yaml/lexer"
@@ -930,6 +932,22 @@ func TestNewSourceFromBytes(t *testing.T) {
 	assert.Equal(t, "key: value", s.Content())
 }
 
+func TestNewSourceFromReader(t *testing.T) {
+	t.Parallel()
+
+	s, err := niceyaml.NewSourceFromReader(strings.NewReader("key: value\n"),
+		niceyaml.WithName("piped.yaml"),
+		niceyaml.WithFilePath("piped.yaml"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "key: value", s.Content())
+	assert.Equal(t, "piped.yaml", s.Name())
+	assert.Equal(t, "piped.yaml", s.FilePath())
+
+	_, err = niceyaml.NewSourceFromReader(iotest.ErrReader(errors.New("broken pipe")))
+	require.ErrorContains(t, err, "broken pipe")
+}
+
 func TestNewSourceFromToken_WalksToPrev(t *testing.T) {
 	t.Parallel()
 
@@ -1152,6 +1170,40 @@ func TestSource_Parse(t *testing.T) {
 	})
 }
 
+func TestSource_Outline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid YAML", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString(stringtest.Input(`
+			parent:
+			  child: value
+		`))
+
+		o, err := source.Outline()
+		require.NoError(t, err)
+
+		node := o.Lookup("$.parent.child")
+		require.NotNil(t, node)
+		assert.Equal(t, position.NewSpan(1, 2), node.Span)
+	})
+
+	t.Run("invalid YAML returns Error", func(t *testing.T) {
+		t.Parallel()
+
+		source := niceyaml.NewSourceFromString("key: [unclosed\n")
+
+		o, err := source.Outline()
+		require.Error(t, err)
+		assert.Nil(t, o)
+
+		var yamlErr *niceyaml.Error
+
+		require.ErrorAs(t, err, &yamlErr)
+	})
+}
+
 func TestSource_WithParserOptions(t *testing.T) {
 	t.Parallel()
 
@@ -1319,6 +1371,46 @@ func TestSource_ClearOverlays(t *testing.T) {
 	})
 }
 
+func TestSource_RemoveOverlays(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("key: value\n")
+	source.AddOverlay("keep", position.NewRange(position.New(0, 0), position.New(0, 3)))
+	source.AddOverlay("remove1", position.NewRange(position.New(0, 5), position.New(0, 10)))
+	source.AddOverlay("remove2", position.NewRange(position.New(0, 0), position.New(0, 10)))
+
+	source.RemoveOverlays("remove1", "remove2")
+
+	require.Len(t, source.Line(0).Overlays, 1)
+	assert.Equal(t, style.Style("keep"), source.Line(0).Overlays[0].Kind)
+}
+
+func TestSource_Clone(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("a: 1\na: 2\n",
+		niceyaml.WithName("name.yaml"),
+		niceyaml.WithFilePath("path.yaml"),
+		niceyaml.WithDuplicateKeyCheck(),
+	)
+	source.Line(0).AddAnnotation(line.Annotation{Content: "note", Position: line.Below})
+	source.AddOverlay("overlay", position.NewRange(position.New(0, 0), position.New(0, 1)))
+
+	clone := source.Clone()
+	clone.Line(1).AddAnnotation(line.Annotation{Content: "added", Position: line.Below})
+
+	assert.Equal(t, source.Content(), clone.Content())
+	assert.Equal(t, "name.yaml", clone.Name())
+	assert.Equal(t, "path.yaml", clone.FilePath())
+	assert.Equal(t, source.Line(0).Annotations, clone.Line(0).Annotations)
+	assert.Empty(t, clone.Line(0).Overlays)
+	assert.Empty(t, source.Line(1).Annotations)
+
+	// The duplicate key check is kept.
+	_, err := clone.Decoder()
+	require.ErrorIs(t, err, niceyaml.ErrDuplicateKey)
+}
+
 func TestSource_Name(t *testing.T) {
 	t.Parallel()
 
@@ -93,6 +93,48 @@ func Encode(style lipgloss.Style) string {
 	return strings.Join(parts, " ")
 }
 
+// EncodeCSS encodes a [lipgloss.Style] as CSS declarations, e.g.
+// "color: #c678dd; font-weight: bold".
+//
+// Only the properties supported by [Encode] are included, plus strikethrough.
+// Returns an empty string if the style sets none of them.
+//
+//nolint:gocritic // Value semantics preferred for API ergonomics.
+func EncodeCSS(style lipgloss.Style) string {
+	var decls []string
+
+	if fg := style.GetForeground(); fg != nil {
+		if hex := colorToHex(fg); hex != "" {
+			decls = append(decls, "color: "+hex)
+		}
+	}
+
+	if bg := style.GetBackground(); bg != nil {
+		if hex := colorToHex(bg); hex != "" {
+			decls = append(decls, "background-color: "+hex)
+		}
+	}
+
+	if style.GetBold() {
+		decls = append(decls, "font-weight: bold")
+	}
+
+	if style.GetItalic() {
+		decls = append(decls, "font-style: italic")
+	}
+
+	switch {
+	case style.GetUnderline() && style.GetStrikethrough():
+		decls = append(decls, "text-decoration: underline line-through")
+	case style.GetUnderline():
+		decls = append(decls, "text-decoration: underline")
+	case style.GetStrikethrough():
+		decls = append(decls, "text-decoration: line-through")
+	}
+
+	return strings.Join(decls, "; ")
+}
+
 // applyToken applies a single token to the style.
 //
 //nolint:gocritic // Value semantics preferred for API ergonomics.
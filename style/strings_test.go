@@ -282,6 +282,46 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeCSS(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		style lipgloss.Style
+		want  string
+	}{
+		"empty style": {
+			style: lipgloss.NewStyle(),
+			want:  "",
+		},
+		"colors": {
+			style: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#ff0000")).
+				Background(lipgloss.Color("#00ff00")),
+			want: "color: #ff0000; background-color: #00ff00",
+		},
+		"modifiers": {
+			style: lipgloss.NewStyle().Bold(true).Italic(true).Underline(true),
+			want:  "font-weight: bold; font-style: italic; text-decoration: underline",
+		},
+		"strikethrough": {
+			style: lipgloss.NewStyle().Strikethrough(true),
+			want:  "text-decoration: line-through",
+		},
+		"underline and strikethrough": {
+			style: lipgloss.NewStyle().Underline(true).Strikethrough(true),
+			want:  "text-decoration: underline line-through",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, style.EncodeCSS(tt.style))
+		})
+	}
+}
+
 func TestRoundTrip(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,25 @@
+// Package svg renders styled terminal output as SVG images, for
+// deterministic snapshots of YAML in documentation and pull request comments.
+//
+// [Render] accepts ANSI-styled text, normally the output of
+// [niceyaml.Printer.Print], and lays it out on a monospace cell grid. Colors
+// and text attributes are taken from the SGR sequences in the input, so the
+// image matches what a terminal would display for the same [style.Styles]:
+//
+//	printer := niceyaml.NewPrinter(niceyaml.WithStyles(theme.Charm()))
+//	image := svg.Render(printer.Print(source), svg.WithStyles(theme.Charm()))
+//
+// # Layout
+//
+// Each cell has a fixed width relative to the font size (see [WithFontSize]),
+// and wide characters span two cells. Runs of cells with the same style are
+// drawn as one <text> element whose length is fixed to its cells, so the
+// layout does not depend on the metrics of the font used by the viewer.
+//
+// The unstyled foreground and background are taken from the [style.Text]
+// style (see [WithStyles]). [WithWindow] draws the grid inside a terminal
+// window frame with a title.
+//
+// Output is deterministic: rendering the same input with the same options
+// always produces the same bytes.
+package svg
@@ -0,0 +1,181 @@
+package svg
+
+import (
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Tab stops are placed every tabWidth cells.
+const tabWidth = 8
+
+// attr is a set of SGR text attributes.
+type attr uint8
+
+const (
+	attrBold attr = 1 << iota
+	attrFaint
+	attrItalic
+	attrUnderline
+	attrReverse
+	attrConceal
+	attrStrikethrough
+)
+
+// cellStyle is the style of a cell. Nil colors are the defaults.
+type cellStyle struct {
+	fg    color.Color
+	bg    color.Color
+	attrs attr
+}
+
+// cell is a single grapheme on the grid.
+type cell struct {
+	content string
+	style   cellStyle
+	width   int
+}
+
+// grid holds the rows of cells of styled terminal output.
+type grid struct {
+	rows [][]cell
+}
+
+// width returns the width of the widest row, in cells.
+func (g *grid) width() int {
+	w := 0
+
+	for _, row := range g.rows {
+		rw := 0
+		for _, c := range row {
+			rw += c.width
+		}
+
+		w = max(w, rw)
+	}
+
+	return w
+}
+
+// parseGrid parses ANSI-styled text into a [grid].
+//
+// Newlines start new rows, tabs advance to the next tab stop, and SGR
+// sequences set the style of the following cells. Other control characters
+// and escape sequences are ignored. A trailing newline does not add a row.
+func parseGrid(s string) *grid {
+	var (
+		g     = &grid{rows: [][]cell{nil}}
+		p     = ansi.NewParser()
+		st    cellStyle
+		col   int
+		state byte
+	)
+
+	for len(s) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(s, state, p)
+		state = newState
+		s = s[n:]
+
+		row := len(g.rows) - 1
+
+		switch {
+		case seq == "\n":
+			if len(s) > 0 {
+				g.rows = append(g.rows, nil)
+			}
+
+			col = 0
+
+		case seq == "\t":
+			for next := (col/tabWidth + 1) * tabWidth; col < next; col++ {
+				g.rows[row] = append(g.rows[row], cell{content: " ", style: st, width: 1})
+			}
+
+		case width > 0:
+			g.rows[row] = append(g.rows[row], cell{content: seq, style: st, width: width})
+			col += width
+
+		case ansi.HasCsiPrefix(seq) && ansi.Cmd(p.Command()).Final() == 'm':
+			st = applySGR(st, p.Params())
+		}
+	}
+
+	return g
+}
+
+// applySGR returns st with the SGR parameters applied.
+func applySGR(st cellStyle, params ansi.Params) cellStyle {
+	if len(params) == 0 {
+		return cellStyle{}
+	}
+
+	for i := 0; i < len(params); i++ {
+		param := params[i].Param(0)
+
+		switch {
+		case param == 0:
+			st = cellStyle{}
+		case param == 1:
+			st.attrs |= attrBold
+		case param == 2:
+			st.attrs |= attrFaint
+		case param == 3:
+			st.attrs |= attrItalic
+		case param == 4:
+			st.attrs |= attrUnderline
+		case param == 7:
+			st.attrs |= attrReverse
+		case param == 8:
+			st.attrs |= attrConceal
+		case param == 9:
+			st.attrs |= attrStrikethrough
+		case param == 22:
+			st.attrs &^= attrBold | attrFaint
+		case param == 23:
+			st.attrs &^= attrItalic
+		case param == 24:
+			st.attrs &^= attrUnderline
+		case param == 27:
+			st.attrs &^= attrReverse
+		case param == 28:
+			st.attrs &^= attrConceal
+		case param == 29:
+			st.attrs &^= attrStrikethrough
+		case param >= 30 && param <= 37:
+			st.fg = ansi.BasicColor(param - 30)
+		case param == 39:
+			st.fg = nil
+		case param >= 40 && param <= 47:
+			st.bg = ansi.BasicColor(param - 40)
+		case param == 49:
+			st.bg = nil
+		case param >= 90 && param <= 97:
+			st.fg = ansi.BasicColor(param - 90 + 8)
+		case param >= 100 && param <= 107:
+			st.bg = ansi.BasicColor(param - 100 + 8)
+		case param == 38, param == 48, param == 58:
+			var c color.Color
+
+			n := ansi.ReadStyleColor(params[i:], &c)
+			if n == 0 {
+				return st
+			}
+
+			switch param {
+			case 38:
+				st.fg = c
+			case 48:
+				st.bg = c
+			}
+
+			i += n - 1
+		}
+
+		// Skip sub-parameters, such as underline styles.
+		for params[i].HasMore() && i+1 < len(params) {
+			i++
+		}
+	}
+
+	return st
+}
@@ -0,0 +1,343 @@
+package svg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+)
+
+const (
+	// DefaultFontSize is the default font size, in pixels.
+	DefaultFontSize = 14
+	// DefaultFontFamily is the default font-family list.
+	DefaultFontFamily = "ui-monospace, SFMono-Regular, Menlo, Consolas, monospace"
+	// DefaultPadding is the default padding around the grid, in pixels.
+	DefaultPadding = 16
+
+	// Cell dimensions, relative to the font size.
+	cellWidthRatio  = 0.6
+	cellHeightRatio = 1.4
+
+	// Height of the window title bar, relative to the font size.
+	titleBarRatio = 2.5
+)
+
+var (
+	fallbackForeground = color.RGBA{R: 0xdd, G: 0xdd, B: 0xdd, A: 0xff}
+	fallbackBackground = color.RGBA{R: 0x17, G: 0x17, B: 0x17, A: 0xff}
+)
+
+// Option configures [Render].
+//
+// Available options:
+//   - [WithStyles]
+//   - [WithFontSize]
+//   - [WithFontFamily]
+//   - [WithPadding]
+//   - [WithWindow]
+type Option func(*renderer)
+
+// WithStyles is an [Option] that sets the [style.Styles] providing the
+// default foreground and background colors, from [style.Text].
+// Use the same styles as the [niceyaml.Printer] that produced the input.
+// By default, [theme.Charm] is used.
+func WithStyles(s style.Styles) Option {
+	return func(r *renderer) {
+		r.styles = s
+	}
+}
+
+// WithFontSize is an [Option] that sets the font size, in pixels.
+// Cell dimensions scale with the font size.
+// By default, [DefaultFontSize] is used.
+func WithFontSize(size float64) Option {
+	return func(r *renderer) {
+		r.fontSize = size
+	}
+}
+
+// WithFontFamily is an [Option] that sets the CSS font-family list.
+// By default, [DefaultFontFamily] is used.
+func WithFontFamily(family string) Option {
+	return func(r *renderer) {
+		r.fontFamily = family
+	}
+}
+
+// WithPadding is an [Option] that sets the padding around the grid, in
+// pixels. By default, [DefaultPadding] is used.
+func WithPadding(padding float64) Option {
+	return func(r *renderer) {
+		r.padding = padding
+	}
+}
+
+// WithWindow is an [Option] that draws the grid inside a terminal window
+// frame, with the given title in its title bar.
+func WithWindow(title string) Option {
+	return func(r *renderer) {
+		r.window = true
+		r.title = title
+	}
+}
+
+// renderer holds the configuration of [Render].
+type renderer struct {
+	styles     style.Styles
+	fontFamily string
+	title      string
+	fontSize   float64
+	padding    float64
+	window     bool
+}
+
+// Render renders ANSI-styled text, such as the output of
+// [niceyaml.Printer.Print], as an SVG image.
+//
+// The image is sized to fit the widest line and all lines of s. See the
+// package documentation for details on the layout.
+func Render(s string, opts ...Option) string {
+	r := &renderer{
+		styles:     theme.Charm(),
+		fontSize:   DefaultFontSize,
+		fontFamily: DefaultFontFamily,
+		padding:    DefaultPadding,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r.render(parseGrid(s))
+}
+
+// render renders g as an SVG image.
+func (r *renderer) render(g *grid) string {
+	var (
+		fg, bg  = r.defaultColors()
+		cellW   = r.fontSize * cellWidthRatio
+		cellH   = r.fontSize * cellHeightRatio
+		titleH  = 0.0
+		numRows = len(g.rows)
+	)
+
+	if numRows == 1 && len(g.rows[0]) == 0 {
+		numRows = 0
+	}
+
+	if r.window {
+		titleH = r.fontSize * titleBarRatio
+	}
+
+	width := float64(g.width())*cellW + 2*r.padding
+	height := float64(numRows)*cellH + 2*r.padding + titleH
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="%s" font-size="%s">`+"\n",
+		num(width), num(height), num(width), num(height), escape(r.fontFamily), num(r.fontSize))
+
+	rx := ""
+	if r.window {
+		rx = ` rx="8"`
+	}
+
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%"%s fill="%s"/>`+"\n", rx, hex(bg))
+
+	if r.window {
+		r.writeTitleBar(&sb, fg, width, titleH)
+	}
+
+	fmt.Fprintf(&sb, `<g transform="translate(%s %s)">`+"\n", num(r.padding), num(r.padding+titleH))
+
+	for y, row := range g.rows {
+		r.writeRow(&sb, row, float64(y)*cellH, cellW, cellH, fg, bg)
+	}
+
+	sb.WriteString("</g>\n</svg>\n")
+
+	return sb.String()
+}
+
+// writeTitleBar writes the window buttons and title.
+func (r *renderer) writeTitleBar(sb *strings.Builder, fg color.Color, width, height float64) {
+	radius := r.fontSize * 0.4
+	cy := height / 2
+
+	for i, c := range []string{"#ff5f57", "#febc2e", "#28c840"} {
+		cx := r.padding + radius + float64(i)*radius*3.5
+		fmt.Fprintf(sb, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(cx), num(cy), num(radius), c)
+	}
+
+	if r.title != "" {
+		fmt.Fprintf(sb,
+			`<text x="%s" y="%s" fill="%s" fill-opacity="0.6" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+			num(width/2), num(cy), hex(fg), escape(r.title))
+	}
+}
+
+// writeRow writes the backgrounds and text runs of a row at offset y.
+func (r *renderer) writeRow(
+	sb *strings.Builder,
+	row []cell,
+	y, cellW, cellH float64,
+	defaultFG, defaultBG color.Color,
+) {
+	col := 0
+
+	for start := 0; start < len(row); {
+		end := start + 1
+		for end < len(row) && row[end].style == row[start].style {
+			end++
+		}
+
+		var (
+			st    = row[start].style
+			text  strings.Builder
+			width int
+		)
+
+		for _, c := range row[start:end] {
+			text.WriteString(c.content)
+			width += c.width
+		}
+
+		fg, bg := st.fg, st.bg
+		if st.attrs&attrReverse != 0 {
+			fg, bg = bg, fg
+			if fg == nil {
+				fg = defaultBG
+			}
+
+			if bg == nil {
+				bg = defaultFG
+			}
+		}
+
+		if fg == nil {
+			fg = defaultFG
+		}
+
+		x := float64(col) * cellW
+		w := float64(width) * cellW
+
+		if bg != nil && !sameColor(bg, defaultBG) {
+			fmt.Fprintf(sb, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+				num(x), num(y), num(w), num(cellH), hex(bg))
+		}
+
+		content := text.String()
+		visible := strings.TrimSpace(content) != "" || st.attrs&(attrUnderline|attrStrikethrough) != 0
+		if visible && st.attrs&attrConceal == 0 {
+			fmt.Fprintf(sb,
+				`<text x="%s" y="%s" textLength="%s" fill="%s"%s xml:space="preserve">%s</text>`+"\n",
+				num(x), num(y+cellH/2), num(w), hex(fg), textAttrs(st.attrs), escape(content))
+		}
+
+		col += width
+		start = end
+	}
+}
+
+// textAttrs returns the SVG attributes for attrs, with a leading space.
+// The dominant baseline is always included, so that text is vertically
+// centered in its cells.
+func textAttrs(attrs attr) string {
+	var sb strings.Builder
+
+	sb.WriteString(` dominant-baseline="central"`)
+
+	if attrs&attrBold != 0 {
+		sb.WriteString(` font-weight="bold"`)
+	}
+
+	if attrs&attrItalic != 0 {
+		sb.WriteString(` font-style="italic"`)
+	}
+
+	if attrs&attrFaint != 0 {
+		sb.WriteString(` fill-opacity="0.5"`)
+	}
+
+	var decorations []string
+	if attrs&attrUnderline != 0 {
+		decorations = append(decorations, "underline")
+	}
+
+	if attrs&attrStrikethrough != 0 {
+		decorations = append(decorations, "line-through")
+	}
+
+	if len(decorations) > 0 {
+		fmt.Fprintf(&sb, ` text-decoration="%s"`, strings.Join(decorations, " "))
+	}
+
+	return sb.String()
+}
+
+// defaultColors returns the foreground and background of [style.Text].
+func (r *renderer) defaultColors() (color.Color, color.Color) {
+	var fg, bg color.Color = fallbackForeground, fallbackBackground
+
+	if text := r.styles.Style(style.Text); text != nil {
+		if c := text.GetForeground(); isSet(c) {
+			fg = c
+		}
+
+		if c := text.GetBackground(); isSet(c) {
+			bg = c
+		}
+	}
+
+	return fg, bg
+}
+
+// isSet reports whether c is a visible color.
+func isSet(c color.Color) bool {
+	if c == nil {
+		return false
+	}
+
+	_, _, _, a := c.RGBA()
+
+	return a != 0
+}
+
+// sameColor reports whether a and b have the same RGBA values.
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+// hex returns c as a "#rrggbb" string.
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// num formats v with at most two decimal places.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&#34;",
+	"'", "&#39;",
+)
+
+// escape escapes XML special characters.
+func escape(s string) string {
+	return xmlEscaper.Replace(s)
+}
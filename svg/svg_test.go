@@ -0,0 +1,181 @@
+package svg_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/style/theme"
+	"go.jacobcolvin.com/niceyaml/svg"
+)
+
+func TestRender_Golden(t *testing.T) {
+	t.Parallel()
+
+	type goldenTest struct {
+		setupFunc   func(*niceyaml.Source)
+		printerOpts []niceyaml.PrinterOption
+		opts        []svg.Option
+	}
+
+	tcs := map[string]goldenTest{
+		"default": {
+			printerOpts: []niceyaml.PrinterOption{
+				niceyaml.WithStyles(theme.Charm()),
+				niceyaml.WithStyle(lipgloss.NewStyle()),
+			},
+		},
+		"window": {
+			printerOpts: []niceyaml.PrinterOption{
+				niceyaml.WithStyles(theme.Charm()),
+				niceyaml.WithStyle(lipgloss.NewStyle()),
+				niceyaml.WithGutter(niceyaml.NoGutter()),
+			},
+			opts: []svg.Option{
+				svg.WithWindow("input.yaml"),
+				svg.WithFontSize(12),
+			},
+		},
+		"light theme": {
+			printerOpts: []niceyaml.PrinterOption{
+				niceyaml.WithStyles(theme.Github()),
+				niceyaml.WithStyle(lipgloss.NewStyle()),
+			},
+			opts: []svg.Option{
+				svg.WithStyles(theme.Github()),
+			},
+		},
+		"highlight": {
+			printerOpts: []niceyaml.PrinterOption{
+				niceyaml.WithStyles(theme.Charm()),
+				niceyaml.WithStyle(lipgloss.NewStyle()),
+				niceyaml.WithGutter(niceyaml.NoGutter()),
+			},
+			setupFunc: func(source *niceyaml.Source) {
+				finder := niceyaml.NewFinder()
+				finder.Load(source)
+
+				source.AddOverlay(style.GenericHighlight, finder.Find("日本")...)
+			},
+		},
+	}
+
+	input, err := os.ReadFile("testdata/input.yaml")
+	require.NoError(t, err)
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(string(input))
+			if tc.setupFunc != nil {
+				tc.setupFunc(source)
+			}
+
+			printer := niceyaml.NewPrinter(tc.printerOpts...)
+
+			golden.RequireEqual(t, svg.Render(printer.Print(source), tc.opts...))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	opts := []svg.Option{
+		svg.WithStyles(style.NewStyles(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ffffff")).
+			Background(lipgloss.Color("#000000")))),
+		svg.WithFontSize(10),
+		svg.WithPadding(0),
+	}
+
+	tcs := map[string]struct {
+		input string
+		want  []string
+	}{
+		"empty": {
+			input: "",
+			want: []string{
+				`<svg xmlns="http://www.w3.org/2000/svg" width="0" height="0" viewBox="0 0 0 0"`,
+			},
+		},
+		"size": {
+			input: "ab\nc\n",
+			want: []string{
+				`width="12" height="28"`,
+				`<text x="0" y="7" textLength="12" fill="#ffffff" dominant-baseline="central" xml:space="preserve">ab</text>`,
+				`<text x="0" y="21" textLength="6" fill="#ffffff" dominant-baseline="central" xml:space="preserve">c</text>`,
+			},
+		},
+		"sgr attributes": {
+			input: "\x1b[1;3;4;38;2;255;0;0;48;5;21mx\x1b[22;23;24;39;49;9;2my\x1b[m",
+			want: []string{
+				`<rect x="0" y="0" width="6" height="14" fill="#0000ff"/>`,
+				`<text x="0" y="7" textLength="6" fill="#ff0000" dominant-baseline="central" ` +
+					`font-weight="bold" font-style="italic" text-decoration="underline" xml:space="preserve">x</text>`,
+				`<text x="6" y="7" textLength="6" fill="#ffffff" dominant-baseline="central" ` +
+					`fill-opacity="0.5" text-decoration="line-through" xml:space="preserve">y</text>`,
+			},
+		},
+		"reverse": {
+			input: "\x1b[7;31mr\x1b[0m",
+			want: []string{
+				`<rect x="0" y="0" width="6" height="14" fill="#800000"/>`,
+				`fill="#000000" dominant-baseline="central" xml:space="preserve">r</text>`,
+			},
+		},
+		"wide characters and tabs": {
+			input: "日本\tx<&>",
+			want: []string{
+				`width="72"`,
+				`<text x="0" y="7" textLength="72" fill="#ffffff" dominant-baseline="central" xml:space="preserve">` +
+					"日本    x&lt;&amp;&gt;</text>",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := svg.Render(tc.input, opts...)
+
+			for _, want := range tc.want {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func TestRender_Printer(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("key: value\n")
+	source.AddOverlay(style.GenericError, position.NewRange(position.New(0, 5), position.New(0, 10)))
+
+	styles := theme.Charm()
+	printer := niceyaml.NewPrinter(
+		niceyaml.WithStyles(styles),
+		niceyaml.WithStyle(lipgloss.NewStyle()),
+		niceyaml.WithGutter(niceyaml.NoGutter()),
+	)
+
+	got := svg.Render(printer.Print(source))
+
+	bg := styles.Style(style.Text).GetBackground()
+	r, g, b, _ := bg.RGBA()
+
+	assert.Contains(t, got, fmt.Sprintf(`<rect width="100%%" height="100%%" fill="#%02x%02x%02x"/>`, r>>8, g>>8, b>>8))
+	assert.Contains(t, got, ">value</text>")
+	assert.Equal(t, 1, strings.Count(got, "<rect x="), "only the error overlay has a background")
+}
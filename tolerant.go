@@ -0,0 +1,231 @@
+package niceyaml
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+
+	"go.jacobcolvin.com/niceyaml/position"
+	"go.jacobcolvin.com/niceyaml/style"
+	"go.jacobcolvin.com/niceyaml/tokens"
+)
+
+// ParseTolerant parses the [Source] like [Source.File], but recovers from
+// syntax errors to report all of them at once.
+//
+// Each document is parsed separately. When parsing a document fails, the
+// block containing the error is removed and the rest of the document is
+// parsed again, until it succeeds. A block is the line of the error and all
+// following lines that are indented deeper than it, e.g. the entries of a
+// mapping whose key is misplaced.
+//
+// The returned [*ast.File] contains the documents and blocks that could be
+// parsed, and is never nil. Removed blocks are marked in the [Source] with
+// [style.GenericErrorInvalid] overlays, so they stand out when printed.
+//
+// A single syntax error is returned as is. Several are returned as one
+// [*Error] with an error per problem, see [WithErrors]. Errors are resolved
+// against the [Source] and have the options of [WithErrorOptions] applied.
+//
+// The result is cached, and overlays are only added by the first call.
+func (s *Source) ParseTolerant() (*ast.File, error) {
+	s.partialOnce.Do(func() {
+		s.partialFile, s.partialErr = s.parseTolerant()
+	})
+
+	return s.partialFile, s.partialErr
+}
+
+func (s *Source) parseTolerant() (*ast.File, error) {
+	file := &ast.File{Name: s.filePath}
+
+	var (
+		errs    []*Error
+		invalid []position.Range
+	)
+
+	for _, docTokens := range splitDocuments(s.Tokens()) {
+		remaining := docTokens
+
+		// Each iteration removes at least one token, so this terminates.
+		for {
+			f, err := parser.Parse(remaining, parser.ParseComments, s.parserOpts...)
+			if err == nil {
+				file.Docs = append(file.Docs, f.Docs...)
+
+				break
+			}
+
+			var (
+				yamlErr yaml.Error
+				tk      *token.Token
+			)
+
+			if errors.As(err, &yamlErr) {
+				tk = yamlErr.GetToken()
+				errs = append(errs, NewError(yamlErr.GetMessage(), WithErrorToken(tk)))
+			} else {
+				errs = append(errs, NewErrorFrom(err))
+			}
+
+			start, end := invalidBlock(remaining, tk)
+			invalid = append(invalid, s.tokensRange(remaining[start:end]))
+
+			remaining = append(remaining[:start:start], remaining[end:]...)
+			if len(remaining) == 0 {
+				break
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		s.AddOverlay(style.GenericErrorInvalid, invalid...)
+	}
+
+	var result *Error
+
+	switch len(errs) {
+	case 0:
+		return file, nil
+	case 1:
+		result = errs[0]
+	default:
+		result = NewError(
+			fmt.Sprintf("found %d syntax errors", len(errs)),
+			WithErrors(errs...),
+		)
+	}
+
+	result.SetOption(s.errorOpts...)
+	result.SetOption(WithSource(s))
+
+	return file, result
+}
+
+// splitDocuments splits tks into documents like [tokens.SplitDocuments], but
+// keeps directives, such as "%YAML 1.2", with the document they precede
+// instead of the one before them.
+func splitDocuments(tks token.Tokens) []token.Tokens {
+	var (
+		docs    []token.Tokens
+		pending token.Tokens
+	)
+
+	for _, doc := range tokens.SplitDocuments(tks) {
+		doc = append(pending, doc...)
+		pending = nil
+
+		if start := directivesStart(doc); start < len(doc) {
+			pending = append(token.Tokens{}, doc[start:]...)
+			doc = doc[:start]
+		}
+
+		if len(doc) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+
+	// Directives without a following document are left to the parser.
+	if len(pending) > 0 {
+		if len(docs) == 0 {
+			return []token.Tokens{pending}
+		}
+
+		docs[len(docs)-1] = append(docs[len(docs)-1], pending...)
+	}
+
+	return docs
+}
+
+// directivesStart returns the index of the first token of the directive lines
+// at the end of doc, or len(doc) if it does not end with directives. Comment
+// lines between the directives are included.
+func directivesStart(doc token.Tokens) int {
+	start := len(doc)
+
+	for i := len(doc) - 1; i >= 0; i-- {
+		// Only the first token of each line decides.
+		if i > 0 && doc[i-1].Position.Line == doc[i].Position.Line {
+			continue
+		}
+
+		if doc[i].Type != token.DirectiveType && doc[i].Type != token.CommentType {
+			break
+		}
+
+		start = i
+	}
+
+	// Comments before the first directive stay with the previous document.
+	for start < len(doc) && doc[start].Type == token.CommentType {
+		start++
+	}
+
+	return start
+}
+
+// invalidBlock returns the index range of the tokens of the block containing
+// tk: the tokens from the first token of its line, up to the next line
+// starting at the same or a lower indentation.
+//
+// If tk is not found, all tokens are returned.
+func invalidBlock(tks token.Tokens, tk *token.Token) (int, int) {
+	idx := -1
+
+	for i, t := range tks {
+		if t == tk || tk != nil && tk.Position != nil && t.Position != nil &&
+			t.Position.Offset == tk.Position.Offset && t.Type == tk.Type {
+			idx = i
+
+			break
+		}
+	}
+
+	if idx < 0 {
+		return 0, len(tks)
+	}
+
+	line := tk.Position.Line
+
+	start := idx
+	for start > 0 && tks[start-1].Position.Line == line {
+		start--
+	}
+
+	column := tks[start].Position.Column
+
+	end := idx + 1
+	for end < len(tks) {
+		t := tks[end]
+		if t.Position.Line != tks[end-1].Position.Line && t.Position.Column <= column {
+			break
+		}
+
+		end++
+	}
+
+	return start, end
+}
+
+// tokensRange returns the range of the content of tks in the [Source].
+func (s *Source) tokensRange(tks token.Tokens) position.Range {
+	rng := position.Range{Start: position.NewFromToken(tks[0])}
+
+	if first := s.ContentPositionRanges(rng.Start); len(first) > 0 {
+		rng.Start = first[0].Start
+	}
+
+	last := s.ContentPositionRanges(position.NewFromToken(tks[len(tks)-1]))
+
+	if len(last) > 0 {
+		rng.End = last[len(last)-1].End
+	} else {
+		rng.End = rng.Start
+	}
+
+	return rng
+}
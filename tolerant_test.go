@@ -0,0 +1,166 @@
+package niceyaml_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jacobcolvin.com/x/stringtest"
+
+	"go.jacobcolvin.com/niceyaml"
+	"go.jacobcolvin.com/niceyaml/style"
+)
+
+func TestSource_ParseTolerant(t *testing.T) {
+	t.Parallel()
+
+	tcs := map[string]struct {
+		input        string
+		wantErr      string
+		wantFile     string
+		wantInvalid  []int
+		wantDocCount int
+	}{
+		"valid": {
+			input: stringtest.Input(`
+				a: 1
+				---
+				b: 2
+			`),
+			wantFile:     "a: 1\n---\nb: 2\n",
+			wantDocCount: 2,
+		},
+		"directives": {
+			input: stringtest.Input(`
+				%YAML 1.2
+				---
+				a: 1
+				---
+				b: 2
+				...
+				%YAML 1.2
+				---
+				c: 3
+			`),
+			wantFile: "%YAML 1.2\n---\na: 1\n---\nb: 2\n...\n%YAML 1.2\n---\nc: 3\n",
+			// Like [Source.File], directives are documents of their own.
+			wantDocCount: 5,
+		},
+		"minimal directive": {
+			input:        "%YAML 1.2\n---\na: 1",
+			wantFile:     "%YAML 1.2\n---\na: 1\n",
+			wantDocCount: 2,
+		},
+		"single error": {
+			input: stringtest.Input(`
+				a: 1
+				b: [
+				c: 2
+			`),
+			wantErr:      "2:4: error: sequence end token ']' not found",
+			wantFile:     "a: 1\nc: 2\n",
+			wantInvalid:  []int{1},
+			wantDocCount: 1,
+		},
+		"misindented block": {
+			input: stringtest.Input(`
+				a:
+				  b: 1
+				 c: 2
+				  d: 3
+				e: 4
+			`),
+			wantErr:      "3:2: error: value is not allowed in this context",
+			wantFile:     "a:\n  b: 1\ne: 4\n",
+			wantInvalid:  []int{2, 3},
+			wantDocCount: 1,
+		},
+		"errors in several documents": {
+			input: stringtest.Input(`
+				a: [
+				b: 1
+				---
+				c: 1
+				 d: 2
+				e: 3
+			`),
+			wantErr: stringtest.JoinLF(
+				"1:4: error: sequence end token ']' not found",
+				"4:4: error: mapping value is not allowed in this context",
+			),
+			// "1 d" is a multiline scalar, so the whole entry is invalid.
+			wantFile:     "b: 1\n---\ne: 3\n",
+			wantInvalid:  []int{0, 3, 4},
+			wantDocCount: 2,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := niceyaml.NewSourceFromString(tc.input)
+
+			file, err := source.ParseTolerant()
+			require.NotNil(t, file)
+			assert.Len(t, file.Docs, tc.wantDocCount)
+			assert.Equal(t, tc.wantFile, file.String())
+
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.wantErr, compactMessages(t, err))
+			}
+
+			var invalid []int
+
+			for i, l := range source.Lines() {
+				for _, o := range l.Overlays {
+					if o.Kind == style.GenericErrorInvalid {
+						invalid = append(invalid, i)
+
+						break
+					}
+				}
+			}
+
+			assert.Equal(t, tc.wantInvalid, invalid)
+		})
+	}
+}
+
+func TestSource_ParseTolerant_Cached(t *testing.T) {
+	t.Parallel()
+
+	source := niceyaml.NewSourceFromString("a: [\nb: 1\n")
+
+	file1, err1 := source.ParseTolerant()
+	file2, err2 := source.ParseTolerant()
+
+	assert.Same(t, file1, file2)
+	assert.Same(t, err1, err2)
+	assert.Len(t, source.Lines()[0].Overlays, 1)
+}
+
+func TestSource_ParseTolerant_BrokenFile(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/broken.yaml")
+	require.NoError(t, err)
+
+	source := niceyaml.NewSourceFromBytes(content, niceyaml.WithName("broken.yaml"))
+
+	_, fileErr := source.File()
+	require.Error(t, fileErr)
+
+	file, err := source.ParseTolerant()
+	assert.Equal(t,
+		"broken.yaml:7:4: error: value is not allowed in this context",
+		compactMessages(t, err),
+	)
+
+	// Everything but the misindented line is kept.
+	assert.Contains(t, file.String(), "units:")
+	assert.NotContains(t, file.String(), "summation")
+}